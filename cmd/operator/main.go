@@ -33,6 +33,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
+	obsctrl "github.com/rhobs/observability-operator/pkg/controllers/observability"
 	"github.com/rhobs/observability-operator/pkg/operator"
 )
 
@@ -53,11 +54,13 @@ var defaultImages = map[string]string{
 	"ui-logging-pf5":               "quay.io/openshift-observability-ui/logging-view-plugin:v6.1.6",
 	"ui-logging":                   "quay.io/openshift-observability-ui/logging-view-plugin:v6.2.1",
 	"korrel8r":                     "quay.io/korrel8r/korrel8r:0.11.1",
+	"lokistack-router":             "registry.access.redhat.com/ubi9/nginx-124:9.6",
 	"health-analyzer":              "quay.io/openshiftanalytics/cluster-health-analyzer:v1.1.1",
 	"ui-monitoring-pf5":            "quay.io/openshift-observability-ui/monitoring-console-plugin:v0.4.5",
 	"ui-monitoring-pf6":            "quay.io/openshift-observability-ui/monitoring-console-plugin:v0.5.4",
 	"ui-monitoring":                "quay.io/openshift-observability-ui/monitoring-console-plugin:v1.0.0",
 	"perses":                       "quay.io/openshift-observability-ui/perses:v0.54.0",
+	"storage-probe":                obsctrl.DefaultStorageProbeImage,
 }
 
 func imagesUsed() []string {
@@ -97,6 +100,9 @@ func main() {
 		openShiftEnabled bool
 		otelCSVName      string
 		tempoCSVName     string
+		compatMatrix     string
+		dryRun           bool
+		reportApplyDiff  bool
 
 		setupLog = ctrl.Log.WithName("setup")
 	)
@@ -109,6 +115,10 @@ func main() {
 	flag.BoolVar(&openShiftEnabled, "openshift.enabled", false, "Enable OpenShift specific features such as Console Plugins.")
 	flag.StringVar(&otelCSVName, "opentelemetry-csv", "", "OpenTelemetry Operator starting CSV name. This can be used to install a specific OpenTelemetry Operator version. Empty string means the latest version will be installed.")
 	flag.StringVar(&tempoCSVName, "tempo-csv", "", "Tempo Operator starting CSV name. This can be used to install a specific Tempo Operator version. Empty string means the latest version will be installed.")
+	flag.StringVar(&compatMatrix, "ui-plugins-compatibility-matrix", "", "YAML file holding the compatibility matrix of the UI plugins which maps the OpenShift versions to the plugin images, support levels and features. It can be mounted from a ConfigMap. Empty string means the built-in matrix is used.")
+
+	flag.BoolVar(&dryRun, "dry-run", false, "Run the controllers without mutating the cluster. The changes which would be applied are logged.")
+	flag.BoolVar(&reportApplyDiff, "report-apply-diff", false, "Log the changes applied by the controllers to the managed resources.")
 
 	opts := zap.Options{
 		Development: true,
@@ -124,6 +134,9 @@ func main() {
 		"metrics-bind-address", metricsAddr,
 		"images", images,
 		"openshift.enabled", openShiftEnabled,
+		"ui-plugins-compatibility-matrix", compatMatrix,
+		"dry-run", dryRun,
+		"report-apply-diff", reportApplyDiff,
 	)
 
 	imgMap, err := validateImages(images)
@@ -176,10 +189,12 @@ func main() {
 			operator.WithThanosSidecarImage(imgMap["thanos"]),
 			operator.WithThanosQuerierImage(imgMap["thanos"]),
 			operator.WithUIPluginImages(imgMap),
+			operator.WithUIPluginCompatibilityMatrix(compatMatrix),
 			operator.WithObservabilityInstaller(operator.ObservabilityInstallerConfiguration{
-				COONamespace:     os.Getenv("NAMESPACE"),
-				OpenTelemetryCSV: otelCSVName,
-				TempoCSV:         tempoCSVName,
+				COONamespace:      os.Getenv("NAMESPACE"),
+				OpenTelemetryCSV:  otelCSVName,
+				TempoCSV:          tempoCSVName,
+				StorageProbeImage: imgMap["storage-probe"],
 			}),
 			operator.WithFeatureGates(operator.FeatureGates{
 				OpenShift: operator.OpenShiftFeatureGates{
@@ -188,6 +203,8 @@ func main() {
 				},
 			}),
 			operator.WithCancelFunc(cancel),
+			operator.WithDryRun(dryRun),
+			operator.WithReportApplyDiff(reportApplyDiff),
 
 			func() func(*operator.OperatorConfiguration) {
 				if openShiftEnabled {
@@ -0,0 +1,8 @@
+// Package v1alpha1 contains API types shared by the observability-operator API groups.
+//
+// The observability-operator API module uses semantic versioning for version tags,
+// but does not guarantee backward compatibility, even for versions v1.0.0 and above.
+// Breaking changes may occur without major version bumps.
+//
+// +kubebuilder:object:generate=true
+package v1alpha1
@@ -0,0 +1,49 @@
+package v1alpha1
+
+// PatchType is the type of patch of a resource override.
+// +kubebuilder:validation:Enum=StrategicMerge;JSON
+type PatchType string
+
+const (
+	// StrategicMergePatchType is a strategic merge patch in YAML or JSON.
+	// Lists of resources which don't define a merge strategy are replaced.
+	StrategicMergePatchType PatchType = "StrategicMerge"
+	// JSONPatchType is a JSON patch (RFC 6902) in YAML or JSON.
+	JSONPatchType PatchType = "JSON"
+)
+
+// ResourceOverride patches a resource generated by the operator before it is
+// applied to the cluster.
+type ResourceOverride struct {
+	// Kind of the generated resource to patch, e.g. Deployment.
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Kind string `json:"kind"`
+
+	// Name of the generated resource to patch.
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Type of the patch.
+	// +optional
+	// +kubebuilder:default=StrategicMerge
+	Type PatchType `json:"type,omitempty"`
+
+	// Patch applied to the generated resource.
+	// The name and the namespace of the resource can't be changed.
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Patch string `json:"patch"`
+}
+
+// GetType returns the type of the patch, it defaults to StrategicMerge.
+func (o ResourceOverride) GetType() PatchType {
+	if o.Type == "" {
+		return StrategicMergePatchType
+	}
+	return o.Type
+}
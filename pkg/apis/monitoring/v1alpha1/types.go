@@ -8,6 +8,8 @@ import (
 	monv1 "github.com/rhobs/obo-prometheus-operator/pkg/apis/monitoring/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	commonv1alpha1 "github.com/rhobs/observability-operator/pkg/apis/common/v1alpha1"
 )
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -177,6 +179,15 @@ type MonitoringStackSpec struct {
 	// +optional
 	// +kubebuilder:default={disabled: false}
 	AlertmanagerConfig AlertmanagerConfig `json:"alertmanagerConfig,omitempty"`
+
+	// Overrides patch the Prometheus, Alertmanager and supporting resources
+	// of the stack before they are applied, e.g. to set a Prometheus field
+	// which the MonitoringStack doesn't expose. Overridden fields aren't
+	// supported and may break after an upgrade of the operator. Failed
+	// patches are reported by the OverridesApplied condition.
+	// +optional
+	// +listType=atomic
+	Overrides []commonv1alpha1.ResourceOverride `json:"overrides,omitempty"`
 }
 
 // MonitoringStackStatus defines the observed state of MonitoringStack.
@@ -203,6 +214,7 @@ const (
 	ReconciledCondition        ConditionType = "Reconciled"
 	AvailableCondition         ConditionType = "Available"
 	ResourceDiscoveryCondition ConditionType = "ResourceDiscovery"
+	OverridesAppliedCondition  ConditionType = "OverridesApplied"
 )
 
 type Condition struct {
@@ -398,11 +410,25 @@ type ThanosQuerierSpec struct {
 	// webTLSConfig configures the TLS options for the Thanos web server.
 	// +optional
 	WebTLSConfig *WebTLSConfig `json:"webTLSConfig,omitempty"`
+
+	// Overrides patch the Deployment, Service, ServiceMonitor and other
+	// resources of the Thanos Querier before they are applied, e.g. to add
+	// command-line flags to the querier container. Overridden fields aren't
+	// supported and may break after an upgrade of the operator. Failed
+	// patches are reported by the OverridesApplied condition.
+	// +optional
+	// +listType=atomic
+	Overrides []commonv1alpha1.ResourceOverride `json:"overrides,omitempty"`
 }
 
 // ThanosQuerierStatus defines the observed state of ThanosQuerier.
 // It should always be reconstructable from the state of the cluster and/or outside world.
-type ThanosQuerierStatus struct{}
+type ThanosQuerierStatus struct {
+	// Conditions provide status information about the ThanosQuerier.
+	// +optional
+	// +listType=atomic
+	Conditions []Condition `json:"conditions,omitempty"`
+}
 
 // SecretKeySelector selects a key of a secret.
 type SecretKeySelector struct {
@@ -22,6 +22,7 @@ package v1alpha1
 
 import (
 	monitoringv1 "github.com/rhobs/obo-prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/rhobs/observability-operator/pkg/apis/common/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -178,6 +179,11 @@ func (in *MonitoringStackSpec) DeepCopyInto(out *MonitoringStackSpec) {
 		(*in).DeepCopyInto(*out)
 	}
 	in.AlertmanagerConfig.DeepCopyInto(&out.AlertmanagerConfig)
+	if in.Overrides != nil {
+		in, out := &in.Overrides, &out.Overrides
+		*out = make([]v1alpha1.ResourceOverride, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitoringStackSpec.
@@ -308,7 +314,7 @@ func (in *ThanosQuerier) DeepCopyInto(out *ThanosQuerier) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ThanosQuerier.
@@ -376,6 +382,11 @@ func (in *ThanosQuerierSpec) DeepCopyInto(out *ThanosQuerierSpec) {
 		*out = new(WebTLSConfig)
 		**out = **in
 	}
+	if in.Overrides != nil {
+		in, out := &in.Overrides, &out.Overrides
+		*out = make([]v1alpha1.ResourceOverride, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ThanosQuerierSpec.
@@ -391,6 +402,13 @@ func (in *ThanosQuerierSpec) DeepCopy() *ThanosQuerierSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ThanosQuerierStatus) DeepCopyInto(out *ThanosQuerierStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ThanosQuerierStatus.
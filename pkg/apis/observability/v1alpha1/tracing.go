@@ -1,12 +1,21 @@
 package v1alpha1
 
 // TracingSpec defines the desired state of the tracing capability.
-// +kubebuilder:validation:XValidation:rule="(!has(self.enabled) || !self.enabled) || (has(self.storage) && has(self.storage.objectStorage) && [has(self.storage.objectStorage.s3), has(self.storage.objectStorage.s3STS), has(self.storage.objectStorage.s3CCO), has(self.storage.objectStorage.azure), has(self.storage.objectStorage.azureWIF), has(self.storage.objectStorage.gcs), has(self.storage.objectStorage.gcsWIF)].filter(x, x).size() > 0)",message="Storage configuration is required when tracing is enabled"
+// +kubebuilder:validation:XValidation:rule="(!has(self.enabled) || !self.enabled) || (has(self.adopt) && has(self.adopt.tempoStack)) || (has(self.storage) && has(self.storage.objectStorage) && [has(self.storage.objectStorage.s3), has(self.storage.objectStorage.s3STS), has(self.storage.objectStorage.s3CCO), has(self.storage.objectStorage.azure), has(self.storage.objectStorage.azureWIF), has(self.storage.objectStorage.gcs), has(self.storage.objectStorage.gcsWIF)].filter(x, x).size() > 0)",message="Storage configuration is required when tracing is enabled"
 type TracingSpec struct {
 	CommonCapabilitiesSpec `json:",inline"`
 
 	// Storage defines the storage for the tracing capability
 	Storage *TracingStorageSpec `json:"storage,omitempty"`
+
+	// Adopt references existing tracing instances in the namespace of the ObservabilityInstaller.
+	// Adopted instances are used instead of creating new ones, the installer only manages
+	// the resources which connect them together. Adopted instances are left in place
+	// when the ObservabilityInstaller is deleted.
+	// +optional
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Adopted instances"
+	Adopt *TracingAdoptSpec `json:"adopt,omitempty"`
 }
 
 func (t *TracingSpec) GetStorage() *TracingStorageSpec {
@@ -16,6 +25,29 @@ func (t *TracingSpec) GetStorage() *TracingStorageSpec {
 	return nil
 }
 
+func (t *TracingSpec) GetAdopt() *TracingAdoptSpec {
+	if t != nil {
+		return t.Adopt
+	}
+	return nil
+}
+
+// TracingAdoptSpec references existing tracing instances.
+type TracingAdoptSpec struct {
+	// TempoStack is the name of an existing TempoStack.
+	// The TempoStack must have the gateway enabled and use the openshift tenancy mode
+	// with a tenant called application. The storage configuration is ignored when set.
+	// +optional
+	// +kubebuilder:validation:Optional
+	TempoStack string `json:"tempoStack,omitempty"`
+
+	// OpenTelemetryCollector is the name of an existing OpenTelemetryCollector.
+	// The installer grants the collector service account the permissions to write traces to Tempo.
+	// +optional
+	// +kubebuilder:validation:Optional
+	OpenTelemetryCollector string `json:"openTelemetryCollector,omitempty"`
+}
+
 // TracingStorageSpec defines the storage for tracing capability.
 type TracingStorageSpec struct {
 	// ObjectStorageSpec defines the object storage configuration for tracing.
@@ -23,6 +55,13 @@ type TracingStorageSpec struct {
 	// +kubebuilder:validation:Optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Object storage config"
 	ObjectStorageSpec *TracingObjectStorageSpec `json:"objectStorage,omitempty"`
+
+	// Preflight defines the checks run against the object storage before the Tempo instance is deployed.
+	// The referenced Secret and ConfigMap keys, the endpoint URL and the TLS material are always validated.
+	// +optional
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Storage preflight"
+	Preflight *StoragePreflightSpec `json:"preflight,omitempty"`
 }
 
 func (s *TracingStorageSpec) GetObjectStorageSpec() *TracingObjectStorageSpec {
@@ -32,6 +71,30 @@ func (s *TracingStorageSpec) GetObjectStorageSpec() *TracingObjectStorageSpec {
 	return nil
 }
 
+func (s *TracingStorageSpec) GetPreflight() *StoragePreflightSpec {
+	if s != nil {
+		return s.Preflight
+	}
+	return nil
+}
+
+// StoragePreflightSpec defines the object storage preflight checks.
+type StoragePreflightSpec struct {
+	// Probe indicates whether a short-lived Job should check that the object storage
+	// endpoint and the bucket are reachable from the cluster.
+	// By default, it is set to false.
+	// +optional
+	// +kubebuilder:validation:Optional
+	Probe bool `json:"probe,omitempty"`
+
+	// Image is the container image used by the probe Job.
+	// The image must provide the curl binary and run as a non-root user.
+	// Defaults to the storage-probe image of the operator.
+	// +optional
+	// +kubebuilder:validation:Optional
+	Image string `json:"image,omitempty"`
+}
+
 // TracingObjectStorageSpec defines the object storage for the tracing capability.
 // +kubebuilder:validation:XValidation:rule="[has(self.s3), has(self.s3STS), has(self.s3CCO), has(self.azure), has(self.azureWIF), has(self.gcs), has(self.gcsWIF)].filter(x, x).size() <= 1",message="Only one or zero storage configurations can be specified"
 type TracingObjectStorageSpec struct {
@@ -5,6 +5,8 @@ package v1alpha1
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	commonv1alpha1 "github.com/rhobs/observability-operator/pkg/apis/common/v1alpha1"
 )
 
 // ObservabilityInstaller defines the desired state of the observability stack.
@@ -43,6 +45,15 @@ type ObservabilityInstallerSpec struct {
 	// +kubebuilder:validation:Optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Capabilities",order=2
 	Capabilities *CapabilitiesSpec `json:"capabilities,omitempty"`
+
+	// Overrides patch the resources deployed for the capabilities, such as
+	// the TempoStack and the OpenTelemetryCollector of the tracing capability,
+	// before they are applied. Overridden fields aren't supported and may
+	// conflict with the configuration of the capabilities. Failed patches are
+	// reported by the OverridesApplied condition.
+	// +optional
+	// +listType=atomic
+	Overrides []commonv1alpha1.ResourceOverride `json:"overrides,omitempty"`
 }
 
 func (s *ObservabilityInstallerSpec) GetCapabilities() *CapabilitiesSpec {
@@ -127,6 +138,18 @@ type CommonCapabilitiesSpec struct {
 	// +optional
 	// +kubebuilder:validation:Optional
 	Operators *OperatorsSpec `json:"operators,omitempty"`
+
+	// DeletionPolicy defines what happens to the resources of the capability
+	// when the capability is disabled or the ObservabilityInstaller is deleted.
+	// Delete removes all the resources, including the operators installed by the capability.
+	// Retain keeps the data-bearing resources (e.g. the TempoStack and the storage secrets) and the operator subscriptions.
+	// Orphan keeps all the resources.
+	// Retained resources are no longer owned by the ObservabilityInstaller.
+	// +optional
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=Delete
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Deletion policy"
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
 }
 
 func (c *CommonCapabilitiesSpec) GetOperators() *OperatorsSpec {
@@ -136,6 +159,26 @@ func (c *CommonCapabilitiesSpec) GetOperators() *OperatorsSpec {
 	return nil
 }
 
+func (c *CommonCapabilitiesSpec) GetDeletionPolicy() DeletionPolicy {
+	if c != nil && c.DeletionPolicy != "" {
+		return c.DeletionPolicy
+	}
+	return DeletionPolicyDelete
+}
+
+// DeletionPolicy defines how the resources of a capability are removed.
+// +kubebuilder:validation:Enum=Delete;Retain;Orphan
+type DeletionPolicy string
+
+const (
+	// DeletionPolicyDelete removes all the resources of the capability.
+	DeletionPolicyDelete DeletionPolicy = "Delete"
+	// DeletionPolicyRetain keeps the data-bearing resources and the operator subscriptions.
+	DeletionPolicyRetain DeletionPolicy = "Retain"
+	// DeletionPolicyOrphan keeps all the resources of the capability.
+	DeletionPolicyOrphan DeletionPolicy = "Orphan"
+)
+
 // OperatorsSpec defines the operators installation.
 type OperatorsSpec struct {
 	// Install indicates whether the operator(s) used by the capability should be installed via OLM.
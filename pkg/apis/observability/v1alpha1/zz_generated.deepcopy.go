@@ -21,6 +21,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"github.com/rhobs/observability-operator/pkg/apis/common/v1alpha1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
@@ -210,6 +211,11 @@ func (in *ObservabilityInstallerSpec) DeepCopyInto(out *ObservabilityInstallerSp
 		*out = new(CapabilitiesSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Overrides != nil {
+		in, out := &in.Overrides, &out.Overrides
+		*out = make([]v1alpha1.ResourceOverride, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObservabilityInstallerSpec.
@@ -325,6 +331,21 @@ func (in *SecretKeySelector) DeepCopy() *SecretKeySelector {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StoragePreflightSpec) DeepCopyInto(out *StoragePreflightSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StoragePreflightSpec.
+func (in *StoragePreflightSpec) DeepCopy() *StoragePreflightSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(StoragePreflightSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TLSSpec) DeepCopyInto(out *TLSSpec) {
 	*out = *in
@@ -355,6 +376,21 @@ func (in *TLSSpec) DeepCopy() *TLSSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TracingAdoptSpec) DeepCopyInto(out *TracingAdoptSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TracingAdoptSpec.
+func (in *TracingAdoptSpec) DeepCopy() *TracingAdoptSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TracingAdoptSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TracingObjectStorageSpec) DeepCopyInto(out *TracingObjectStorageSpec) {
 	*out = *in
@@ -419,6 +455,11 @@ func (in *TracingSpec) DeepCopyInto(out *TracingSpec) {
 		*out = new(TracingStorageSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Adopt != nil {
+		in, out := &in.Adopt, &out.Adopt
+		*out = new(TracingAdoptSpec)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TracingSpec.
@@ -439,6 +480,11 @@ func (in *TracingStorageSpec) DeepCopyInto(out *TracingStorageSpec) {
 		*out = new(TracingObjectStorageSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Preflight != nil {
+		in, out := &in.Preflight, &out.Preflight
+		*out = new(StoragePreflightSpec)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TracingStorageSpec.
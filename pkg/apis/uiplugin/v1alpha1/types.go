@@ -7,6 +7,9 @@ package v1alpha1
 import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	commonv1alpha1 "github.com/rhobs/observability-operator/pkg/apis/common/v1alpha1"
 )
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -15,6 +18,9 @@ import (
 // +k8s:openapi-gen=true
 // +kubebuilder:resource:scope=Cluster
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Type",type="string",JSONPath=".spec.type"
+// +kubebuilder:printcolumn:name="Ready",type="integer",JSONPath=".status.readyReplicas"
+// +kubebuilder:printcolumn:name="Available",type="string",JSONPath=".status.conditions[?(@.type==\"Available\")].status"
 // +kubebuilder:validation:XValidation:rule="self.spec.type != 'Logging' || self.metadata.name == 'logging'",message="UIPlugin name must be 'logging' if type is Logging"
 // +kubebuilder:validation:XValidation:rule="self.spec.type != 'TroubleshootingPanel' || self.metadata.name == 'troubleshooting-panel'",message="UIPlugin name must be 'troubleshooting-panel' if type is TroubleshootingPanel"
 // +kubebuilder:validation:XValidation:rule="self.spec.type != 'DistributedTracing' || self.metadata.name == 'distributed-tracing'",message="UIPlugin name must be 'distributed-tracing' if type is DistributedTracing"
@@ -88,6 +94,60 @@ type DeploymentConfig struct {
 	// +kubebuilder:validation:Optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Pod Tolerations",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:selector:core:v1:Toleration"}
 	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// Define the number of pods of the deployment.
+	//
+	// Defaults to 1 if not specified.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// Define the compute resources of the container.
+	//
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Resource Requirements",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:resourceRequirements"}
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// Define the priority class of the pods.
+	//
+	// +kubebuilder:validation:Optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// Define the scheduling constraints of the pods.
+	//
+	// +kubebuilder:validation:Optional
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+
+	// Define additional environment variables of the container.
+	//
+	// +kubebuilder:validation:Optional
+	// +listType=atomic
+	Env []corev1.EnvVar `json:"env,omitempty"`
+
+	// Define a PodDisruptionBudget for the pods of the deployment.
+	//
+	// When not specified, no PodDisruptionBudget is created.
+	//
+	// +kubebuilder:validation:Optional
+	PodDisruptionBudget *PodDisruptionBudgetConfig `json:"podDisruptionBudget,omitempty"`
+}
+
+// PodDisruptionBudgetConfig contains options for the PodDisruptionBudget of a deployment.
+//
+// +kubebuilder:validation:XValidation:rule="!(has(self.minAvailable) && has(self.maxUnavailable))",message="minAvailable and maxUnavailable are mutually exclusive"
+type PodDisruptionBudgetConfig struct {
+	// MinAvailable is the number of pods which must remain available during a disruption.
+	//
+	// +kubebuilder:validation:Optional
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+
+	// MaxUnavailable is the number of pods which can be unavailable during a disruption.
+	//
+	// Defaults to 1 if minAvailable is not specified.
+	//
+	// +kubebuilder:validation:Optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
 }
 
 // TroubleshootingPanelConfig contains options for configuring the Troubleshooting Panel  plugin
@@ -110,6 +170,105 @@ type TroubleshootingPanelConfig struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="OCP Console Agent Navigation",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
 	// +kubebuilder:validation:Optional
 	EnableAgentNavigation bool `json:"enableAgentNavigation,omitempty"`
+
+	// Korrel8r contains configuration for the korrel8r service queried by the panel.
+	//
+	// +optional
+	Korrel8r *Korrel8rConfig `json:"korrel8r,omitempty"`
+}
+
+// Korrel8rDomain is a korrel8r domain backed by a store.
+//
+// +kubebuilder:validation:Enum=alert;log;metric;netflow;trace
+type Korrel8rDomain string
+
+const (
+	Korrel8rDomainAlert   Korrel8rDomain = "alert"
+	Korrel8rDomainLog     Korrel8rDomain = "log"
+	Korrel8rDomainMetric  Korrel8rDomain = "metric"
+	Korrel8rDomainNetflow Korrel8rDomain = "netflow"
+	Korrel8rDomainTrace   Korrel8rDomain = "trace"
+)
+
+// Korrel8rConfig contains options for configuring korrel8r.
+type Korrel8rConfig struct {
+	// Stores declare the stores of the korrel8r domains. The operator
+	// discovers the stores of the domains which aren't declared.
+	//
+	// +optional
+	// +listType=map
+	// +listMapKey=domain
+	Stores []Korrel8rStore `json:"stores,omitempty"`
+
+	// DisabledDomains are the domains for which no store is configured,
+	// for instance when the cluster doesn't run network observability.
+	//
+	// +optional
+	// +listType=set
+	DisabledDomains []Korrel8rDomain `json:"disabledDomains,omitempty"`
+
+	// Deployment allows customizing aspects of the korrel8r deployment.
+	//
+	// +optional
+	Deployment *DeploymentConfig `json:"deployment,omitempty"`
+
+	// Rules reference ConfigMap keys holding korrel8r rule files which are
+	// loaded in addition to the default rules.
+	// The ConfigMaps must be in the namespace of the operator.
+	//
+	// +optional
+	// +listType=atomic
+	Rules []ConfigMapKeyReference `json:"rules,omitempty"`
+}
+
+// Korrel8rStore declares the store of a korrel8r domain.
+type Korrel8rStore struct {
+	// Domain of the store.
+	//
+	// +required
+	Domain Korrel8rDomain `json:"domain"`
+
+	// URL of the store. For the alert domain, it is the URL of the
+	// Alertmanager API, the metrics are queried from the store of the
+	// metric domain.
+	//
+	// When not set, the operator discovers the service of the store.
+	//
+	// +optional
+	// +kubebuilder:validation:Pattern:="^https?://.+$"
+	URL string `json:"url,omitempty"`
+
+	// Namespace in which the operator discovers the service of the store
+	// when the URL isn't set. It only applies to the log, netflow and trace domains.
+	//
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// CertificateAuthority references the ConfigMap key holding the CA
+	// bundle used to verify the certificate of the store.
+	// The ConfigMap must be in the namespace of the operator.
+	//
+	// Defaults to the service CA if not specified.
+	//
+	// +optional
+	CertificateAuthority *ConfigMapKeyReference `json:"certificateAuthority,omitempty"`
+}
+
+// ConfigMapKeyReference references a key of a ConfigMap.
+//
+// +structType=atomic
+type ConfigMapKeyReference struct {
+	// Name of the ConfigMap.
+	//
+	// +required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Key of the ConfigMap.
+	//
+	// +required
+	// +kubebuilder:validation:MinLength=1
+	Key string `json:"key"`
 }
 
 // DistributedTracingConfig contains options for configuring the Distributed Tracing plugin
@@ -123,9 +282,58 @@ type DistributedTracingConfig struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="OCP Console Query Timeout",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:ocpConsoleTimeout"}
 	// +kubebuilder:validation:Pattern:="^([0-9]+)([sm]{1})$"
 	Timeout string `json:"timeout,omitempty"`
+
+	// Instances restricts the plugin to the listed Tempo instances.
+	//
+	// When neither instances nor instanceSelector are set, the plugin shows
+	// all the Tempo instances of the cluster.
+	//
+	// +optional
+	// +listType=atomic
+	Instances []TempoInstanceReference `json:"instances,omitempty"`
+
+	// InstanceSelector restricts the plugin to the Tempo instances matching
+	// the label selector, in addition to the listed instances.
+	//
+	// +optional
+	InstanceSelector *metav1.LabelSelector `json:"instanceSelector,omitempty"`
+}
+
+// TempoInstanceKind is the kind of a Tempo instance.
+//
+// +kubebuilder:validation:Enum=TempoStack;TempoMonolithic
+type TempoInstanceKind string
+
+const (
+	TempoStackKind      TempoInstanceKind = "TempoStack"
+	TempoMonolithicKind TempoInstanceKind = "TempoMonolithic"
+)
+
+// TempoInstanceReference references a TempoStack or TempoMonolithic instance.
+//
+// +structType=atomic
+type TempoInstanceReference struct {
+	// Kind of the Tempo instance.
+	//
+	// +required
+	Kind TempoInstanceKind `json:"kind"`
+
+	// Name of the Tempo instance.
+	//
+	// +required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Namespace of the Tempo instance.
+	//
+	// +required
+	// +kubebuilder:validation:MinLength=1
+	Namespace string `json:"namespace"`
 }
 
 // LoggingConfig contains options for configuring the logging console plugin.
+//
+// +kubebuilder:validation:XValidation:rule="!has(self.lokiStack) || !has(self.lokiStacks)",message="lokiStack and lokiStacks are mutually exclusive"
 type LoggingConfig struct {
 	// LokiStack points to the LokiStack instance of which logs should be displayed.
 	// It always references a LokiStack in the "openshift-logging" namespace.
@@ -133,6 +341,17 @@ type LoggingConfig struct {
 	// +kubebuilder:validation:Optional
 	LokiStack *LokiStackReference `json:"lokiStack"`
 
+	// LokiStacks points to the LokiStack instances of which logs should be displayed
+	// when the log tenants are stored in different LokiStacks. The console switches
+	// between the LokiStacks depending on the tenant being queried.
+	//
+	// It can't be set together with lokiStack.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxItems=3
+	// +listType=atomic
+	LokiStacks []LokiStackBackend `json:"lokiStacks,omitempty"`
+
 	// LogsLimit is the max number of entries returned for a query.
 	//
 	// +kubebuilder:validation:Minimum=0
@@ -150,7 +369,8 @@ type LoggingConfig struct {
 
 	// Schema is the schema to use for logs querying and display.
 	//
-	// Defaults to "viaq" if not specified. Use "otel" for OpenTelemetry schema, or "select" to allow users to choose the schema from the UI.
+	// Use "viaq" for the ViaQ schema, "otel" for OpenTelemetry schema, or "select" to allow users to choose the schema from the UI.
+	// If not specified, it defaults to "select" when a LokiStack is configured to ingest OTLP logs, and to "viaq" otherwise.
 	//
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="OCP Console Logs Schema",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:ocpConsoleLogsSchema"}
 	// +kubebuilder:validation:Optional
@@ -164,6 +384,22 @@ type LoggingConfig struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="OCP Console Logs show timezone selector",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:ocpConsoleLogsShowTimezoneSelector"}
 	// +kubebuilder:validation:Optional
 	ShowTimezoneSelector bool `json:"showTimezoneSelector,omitempty"`
+
+	// Alerts contains configuration for the log-based alerts views.
+	//
+	// +kubebuilder:validation:Optional
+	Alerts *LoggingAlertsConfig `json:"alerts,omitempty"`
+}
+
+// LoggingAlertsConfig contains options for configuring the log-based alerts
+// views, which merge the alerts of the Loki ruler with the console alerts.
+type LoggingAlertsConfig struct {
+	// Enabled indicates whether the log-based alerts views are enabled.
+	//
+	// Defaults to true if the ruler of a LokiStack is enabled.
+	//
+	// +kubebuilder:validation:Optional
+	Enabled *bool `json:"enabled,omitempty"`
 }
 
 // LokiStackReference is used to configure a reference to a LokiStack that should be used
@@ -182,6 +418,42 @@ type LokiStackReference struct {
 	Namespace string `json:"namespace,omitempty"`
 }
 
+// LoggingTenant is a tenant of the logs stored in a LokiStack.
+//
+// +kubebuilder:validation:Enum=application;infrastructure;audit
+type LoggingTenant string
+
+const (
+	LoggingTenantApplication    LoggingTenant = "application"
+	LoggingTenantInfrastructure LoggingTenant = "infrastructure"
+	LoggingTenantAudit          LoggingTenant = "audit"
+)
+
+// LokiStackBackend is a LokiStack queried by the Logging console plugin for
+// a set of tenants.
+type LokiStackBackend struct {
+	// Name of the LokiStack resource.
+	//
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Namespace of the LokiStack resource.
+	//
+	// Defaults to "openshift-logging" if not specified.
+	//
+	// +kubebuilder:validation:Optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Tenants are the tenants of which logs are queried from the LokiStack.
+	// A tenant can be served by one LokiStack only.
+	//
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	// +listType=set
+	Tenants []LoggingTenant `json:"tenants"`
+}
+
 // MonitoringConfig contains options for configuring the monitoring console plugin.
 type MonitoringConfig struct {
 	// ACM points to the alertmanager and thanosQuerier instance services of which it should create a proxy to.
@@ -215,14 +487,24 @@ type AdvancedClusterManagementReference struct {
 	// +kubebuilder:validation:Required
 	Enabled bool `json:"enabled"`
 	// Alertmanager points to the alertmanager instance of which it should create a proxy to.
+	// It is required unless discover is true.
 	//
-	// +kubebuilder:validation:Required
-	Alertmanager AlertmanagerReference `json:"alertmanager"`
+	// +optional
+	Alertmanager AlertmanagerReference `json:"alertmanager,omitempty"`
 
 	// ThanosQuerier points to the thanos-querier service of which it should create a proxy to.
+	// It is required unless discover is true.
 	//
-	// +kubebuilder:validation:Required
-	ThanosQuerier ThanosQuerierReference `json:"thanosQuerier"`
+	// +optional
+	ThanosQuerier ThanosQuerierReference `json:"thanosQuerier,omitempty"`
+
+	// Discover enables the discovery of the hub Alertmanager and of the
+	// observatorium query endpoint from the MultiClusterObservability
+	// resource of ACM. The URLs set in alertmanager and thanosQuerier take
+	// precedence over the discovered ones.
+	//
+	// +optional
+	Discover bool `json:"discover,omitempty"`
 }
 
 // Alertmanager is used to configure a reference to an alertmanager that should be used
@@ -254,6 +536,228 @@ type PersesReference struct {
 	//
 	// +kubebuilder:validation:Required
 	Enabled bool `json:"enabled"`
+
+	// Deployment allows customizing aspects of the Perses deployment.
+	// The priority class and the environment variables are not supported.
+	//
+	// +kubebuilder:validation:Optional
+	Deployment *DeploymentConfig `json:"deployment,omitempty"`
+
+	// PrometheusURL is the URL of the Prometheus API queried by the default
+	// Perses datasource.
+	// It defaults to the Thanos Querier of the OpenShift platform monitoring
+	// and it is required on Kubernetes clusters.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern:="^https?://.+$"
+	PrometheusURL string `json:"prometheusURL,omitempty"`
+
+	// Ingress exposes the Perses instance outside of the cluster.
+	// On Kubernetes clusters, which have no OpenShift console, it is the
+	// way to reach the dashboards.
+	//
+	// +kubebuilder:validation:Optional
+	Ingress *PersesIngress `json:"ingress,omitempty"`
+
+	// Dashboards select the ConfigMaps holding Perses dashboard definitions.
+	// A PersesDashboard is created for each definition in the namespace of
+	// its ConfigMap.
+	//
+	// +kubebuilder:validation:Optional
+	Dashboards *PersesDefinitionsReference `json:"dashboards,omitempty"`
+
+	// Datasources select the ConfigMaps holding Perses datasource
+	// definitions. A PersesDatasource is created for each definition in the
+	// namespace of its ConfigMap.
+	//
+	// +kubebuilder:validation:Optional
+	Datasources *PersesDefinitionsReference `json:"datasources,omitempty"`
+
+	// GrafanaDashboards select the ConfigMaps holding Grafana dashboards in
+	// JSON. Each dashboard is converted into a PersesDashboard created in the
+	// namespace of its ConfigMap. The panels and variables which can't be
+	// converted are reported in the status.
+	//
+	// +kubebuilder:validation:Optional
+	GrafanaDashboards *PersesDefinitionsReference `json:"grafanaDashboards,omitempty"`
+
+	// MonitoringStackSelector selects the MonitoringStacks for which a
+	// PersesDatasource and a dashboard are created in their namespace.
+	// All the MonitoringStacks are selected when not set. A datasource and a
+	// dashboard are always created for the ThanosQueriers.
+	//
+	// +kubebuilder:validation:Optional
+	MonitoringStackSelector *metav1.LabelSelector `json:"monitoringStackSelector,omitempty"`
+
+	// Storage configures where Perses stores the dashboards and datasources
+	// created through its UI and API.
+	// They are stored on an ephemeral volume when not set and they are lost
+	// when the pod restarts.
+	//
+	// +kubebuilder:validation:Optional
+	Storage *PersesStorage `json:"storage,omitempty"`
+
+	// DatasourceProxy configures the proxy of the default Prometheus
+	// datasource.
+	//
+	// +kubebuilder:validation:Optional
+	DatasourceProxy *PersesDatasourceProxy `json:"datasourceProxy,omitempty"`
+}
+
+// PersesStorage configures the database of the Perses instance.
+// Running more than one replica of Perses requires an SQL database.
+//
+// +kubebuilder:validation:XValidation:rule="!(has(self.persistentVolumeClaim) && has(self.sql))",message="persistentVolumeClaim and sql are mutually exclusive"
+type PersesStorage struct {
+	// PersistentVolumeClaim stores the Perses database on a persistent
+	// volume claimed with this spec. The claim must request a storage size.
+	//
+	// +kubebuilder:validation:Optional
+	PersistentVolumeClaim *corev1.PersistentVolumeClaimSpec `json:"persistentVolumeClaim,omitempty"`
+
+	// SQL stores the Perses database in a MySQL or MariaDB database.
+	//
+	// +kubebuilder:validation:Optional
+	SQL *PersesSQLStorage `json:"sql,omitempty"`
+}
+
+// PersesSQLStorage configures the SQL database of the Perses instance.
+type PersesSQLStorage struct {
+	// Address of the database server in the host:port format.
+	//
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Address string `json:"address"`
+
+	// Database is the name of the database holding the Perses tables.
+	//
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Database string `json:"database"`
+
+	// CredentialsSecretName is the name of the secret holding the `user` and
+	// `password` keys used to connect to the database. It must be in the
+	// namespace of the operator.
+	//
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	CredentialsSecretName string `json:"credentialsSecretName"`
+
+	// CASecretName is the name of the secret holding the `ca.crt` key used to
+	// verify the certificate of the database server. It must be in the
+	// namespace of the operator.
+	// The connection isn't encrypted when not set.
+	//
+	// +kubebuilder:validation:Optional
+	CASecretName string `json:"caSecretName,omitempty"`
+}
+
+// PersesDatasourceProxy configures the proxy of the default Prometheus
+// datasource.
+type PersesDatasourceProxy struct {
+	// Headers are added to the requests sent to the Prometheus API, for
+	// instance to select the tenant of a multi-tenant backend.
+	// The Authorization header isn't allowed.
+	//
+	// +kubebuilder:validation:Optional
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// AllowedEndpoints restricts the endpoints of the Prometheus API which
+	// can be reached through the proxy. All the endpoints are allowed when
+	// empty.
+	//
+	// +kubebuilder:validation:Optional
+	// +listType=atomic
+	AllowedEndpoints []PersesAllowedEndpoint `json:"allowedEndpoints,omitempty"`
+
+	// DisableLocalDatasources prevents dashboards from defining their own
+	// datasources, so that Perses only proxies the datasources created as
+	// resources.
+	//
+	// +kubebuilder:validation:Optional
+	DisableLocalDatasources bool `json:"disableLocalDatasources,omitempty"`
+}
+
+// PersesAllowedEndpoint is an endpoint of the Prometheus API reachable
+// through the proxy of the datasource.
+type PersesAllowedEndpoint struct {
+	// EndpointPattern is a regular expression matching the path of the
+	// endpoint, for instance `/api/v1/query_range`.
+	//
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	EndpointPattern string `json:"endpointPattern"`
+
+	// Method is the HTTP method allowed on the endpoint.
+	//
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Enum=GET;POST
+	Method string `json:"method"`
+}
+
+// PersesDefinitionsReference selects ConfigMaps holding Perses definitions.
+// Each key of the ConfigMaps holds one definition in JSON or YAML, in the
+// format exported by Perses. The name of the definition is the name of the
+// created resource, its project is ignored.
+//
+// Only the ConfigMaps with the label
+// observability.openshift.io/perses-definitions=true are read.
+type PersesDefinitionsReference struct {
+	// ConfigMaps reference ConfigMaps by name and namespace.
+	//
+	// +kubebuilder:validation:Optional
+	// +listType=atomic
+	ConfigMaps []NamespacedConfigMapReference `json:"configMaps,omitempty"`
+
+	// Selector selects ConfigMaps by label in all namespaces.
+	//
+	// +kubebuilder:validation:Optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+}
+
+// NamespacedConfigMapReference references a ConfigMap in a namespace.
+//
+// +structType=atomic
+type NamespacedConfigMapReference struct {
+	// Name of the ConfigMap.
+	//
+	// +required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Namespace of the ConfigMap.
+	//
+	// +required
+	// +kubebuilder:validation:MinLength=1
+	Namespace string `json:"namespace"`
+}
+
+// PersesIngress configures the Ingress of the Perses instance.
+type PersesIngress struct {
+	// Host is the fully qualified domain name serving Perses.
+	//
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Host string `json:"host"`
+
+	// IngressClassName is the class of the Ingress.
+	// The default class of the cluster is used when not set.
+	//
+	// +kubebuilder:validation:Optional
+	IngressClassName *string `json:"ingressClassName,omitempty"`
+
+	// TLSSecretName is the name of the secret holding the TLS certificate
+	// of the host. It must be in the namespace of the operator.
+	// The Ingress doesn't terminate TLS when not set.
+	//
+	// +kubebuilder:validation:Optional
+	TLSSecretName string `json:"tlsSecretName,omitempty"`
+
+	// Annotations are added to the Ingress, for instance to configure the
+	// ingress controller or to request a certificate.
+	//
+	// +kubebuilder:validation:Optional
+	Annotations map[string]string `json:"annotations,omitempty"`
 }
 
 // IncidentsReference is used to configure if the incidents feature flag should be enabled.
@@ -270,12 +774,90 @@ type ClusterHealthAnalyzerReference struct {
 	//
 	// +kubebuilder:validation:Required
 	Enabled bool `json:"enabled"`
+
+	// Deployment allows customizing aspects of the cluster-health-analyzer deployment.
+	// It also applies when the analyzer is deployed for the incidents feature.
+	//
+	// +kubebuilder:validation:Optional
+	Deployment *DeploymentConfig `json:"deployment,omitempty"`
+
+	// Components references a ConfigMap holding additional components in its
+	// `components.yaml` key, in the format of the component tree of the
+	// cluster-health-analyzer. Each component selects alerts by labels and
+	// Kubernetes objects by resource and labels.
+	// The components are merged with the default tree: a component with the
+	// same name and parent as a default one extends it. The ConfigMap is
+	// read again every 5 minutes.
+	//
+	// +kubebuilder:validation:Optional
+	Components *NamespacedConfigMapReference `json:"components,omitempty"`
+
+	// Target is the MonitoringStack or ThanosQuerier managed by the operator
+	// whose alerts are analyzed. The OpenShift platform monitoring is
+	// analyzed when not set.
+	//
+	// +kubebuilder:validation:Optional
+	Target *HealthAnalyzerTarget `json:"target,omitempty"`
+}
+
+// HealthAnalyzerTargetKind is the kind of workload analyzed by the health
+// analyzer.
+// +kubebuilder:validation:Enum=MonitoringStack;ThanosQuerier
+type HealthAnalyzerTargetKind string
+
+const (
+	HealthAnalyzerTargetMonitoringStack HealthAnalyzerTargetKind = "MonitoringStack"
+	HealthAnalyzerTargetThanosQuerier   HealthAnalyzerTargetKind = "ThanosQuerier"
+)
+
+// HealthAnalyzerTarget references a MonitoringStack or a ThanosQuerier.
+// The health analyzer queries the Prometheus API of the target and, for a
+// MonitoringStack, its Alertmanager. When they serve TLS, they are verified
+// with the CA of their TLS configuration.
+type HealthAnalyzerTarget struct {
+	// Kind of the target.
+	//
+	// +kubebuilder:validation:Required
+	Kind HealthAnalyzerTargetKind `json:"kind"`
+
+	// Name of the target.
+	//
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Namespace of the target.
+	//
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Namespace string `json:"namespace"`
+}
+
+// UIPluginFeatures opts in or out of features of a plugin. The features
+// enabled by default depend on the version of the cluster and on the
+// configuration of the plugin.
+type UIPluginFeatures struct {
+	// Enabled lists the features to enable in addition to the default ones.
+	// Only the features supported by the plugin for the version of the
+	// cluster can be enabled, the other ones are ignored.
+	//
+	// +optional
+	// +listType=set
+	Enabled []string `json:"enabled,omitempty"`
+
+	// Disabled lists the features to disable. It takes precedence over
+	// enabled.
+	//
+	// +optional
+	// +listType=set
+	Disabled []string `json:"disabled,omitempty"`
 }
 
 // UIPluginSpec is the specification for desired state of UIPlugin.
 //
 // +kubebuilder:validation:XValidation:rule="self.type == 'TroubleshootingPanel' || !has(self.troubleshootingPanel)", message="Troubleshooting Panel configuration is only supported with the TroubleshootingPanel type"
 // +kubebuilder:validation:XValidation:rule="self.type == 'DistributedTracing' || !has(self.distributedTracing)", message="Distributed Tracing configuration is only supported with the DistributedTracing type"
+// +kubebuilder:validation:XValidation:rule="self.type != 'Dashboards' || !has(self.features)", message="Features are not supported with the Dashboards type"
 type UIPluginSpec struct {
 	// Type defines the UI plugin.
 	// +required
@@ -287,6 +869,11 @@ type UIPluginSpec struct {
 	// +kubebuilder:validation:Optional
 	Deployment *DeploymentConfig `json:"deployment,omitempty"`
 
+	// Features opts in or out of individual features of the plugin.
+	//
+	// +kubebuilder:validation:Optional
+	Features *UIPluginFeatures `json:"features,omitempty"`
+
 	// TroubleshootingPanel contains configuration for the troubleshooting console plugin.
 	//
 	// +kubebuilder:validation:Optional
@@ -308,6 +895,15 @@ type UIPluginSpec struct {
 	//
 	// +kubebuilder:validation:Optional
 	Monitoring *MonitoringConfig `json:"monitoring,omitempty"`
+
+	// Overrides patch the Deployment, Service, ConsolePlugin and other
+	// resources of the plugin before they are applied, e.g. to change the
+	// arguments of the plugin backend. Overridden fields aren't supported and
+	// may break after an upgrade of the operator. Failed patches are reported
+	// by the OverridesApplied condition.
+	// +optional
+	// +listType=atomic
+	Overrides []commonv1alpha1.ResourceOverride `json:"overrides,omitempty"`
 }
 
 // UIPluginStatus defines the observed state of UIPlugin.
@@ -316,6 +912,213 @@ type UIPluginStatus struct {
 	// Conditions provide status information about the plugin.
 	// +listType=atomic
 	Conditions []metav1.Condition `json:"conditions"`
+
+	// ConsolePlugin reports the console plugin registered for the UIPlugin.
+	// +optional
+	ConsolePlugin *ConsolePluginStatus `json:"consolePlugin,omitempty"`
+
+	// Image is the image of the plugin deployed by the operator.
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// SupportLevel is the support level of the plugin for the version of the cluster.
+	// +optional
+	SupportLevel string `json:"supportLevel,omitempty"`
+
+	// Backends are the backends resolved by the operator for the plugin.
+	// +optional
+	// +listType=atomic
+	Backends []UIPluginBackend `json:"backends,omitempty"`
+
+	// Replicas is the number of desired pods of the plugin deployment.
+	// +optional
+	Replicas int32 `json:"replicas,omitempty"`
+
+	// ReadyReplicas is the number of ready pods of the plugin deployment.
+	// +optional
+	ReadyReplicas int32 `json:"readyReplicas,omitempty"`
+
+	// Korrel8rStores are the stores configured in korrel8r for the
+	// TroubleshootingPanel plugin.
+	// +optional
+	// +listType=atomic
+	Korrel8rStores []Korrel8rStoreStatus `json:"korrel8rStores,omitempty"`
+
+	// Logging reports the capabilities of the LokiStacks detected for the
+	// Logging plugin.
+	// +optional
+	Logging *LoggingStatus `json:"logging,omitempty"`
+
+	// PersesDefinitions report the Perses definitions loaded from the
+	// ConfigMaps selected by the Monitoring plugin.
+	// +optional
+	// +listType=atomic
+	PersesDefinitions []PersesDefinitionStatus `json:"persesDefinitions,omitempty"`
+
+	// ACM reports the hub endpoints used by the ACM alerting of the
+	// Monitoring plugin.
+	// +optional
+	ACM *ACMStatus `json:"acm,omitempty"`
+
+	// Features are the features enabled in the plugin.
+	// +optional
+	// +listType=atomic
+	Features []string `json:"features,omitempty"`
+
+	// UnsupportedFeatures are the features of spec.features.enabled which
+	// aren't supported by the plugin for the version of the cluster. They
+	// are ignored.
+	// +optional
+	// +listType=atomic
+	UnsupportedFeatures []string `json:"unsupportedFeatures,omitempty"`
+}
+
+// ACMStatus reports the hub endpoints used by the ACM alerting of the
+// Monitoring plugin.
+type ACMStatus struct {
+	// MultiClusterObservability is the name of the MultiClusterObservability
+	// resource from which the endpoints are discovered.
+	// +optional
+	MultiClusterObservability string `json:"multiClusterObservability,omitempty"`
+
+	// AlertmanagerURL is the URL of the hub Alertmanager.
+	// +optional
+	AlertmanagerURL string `json:"alertmanagerURL,omitempty"`
+
+	// ThanosQuerierURL is the URL of the observatorium query endpoint.
+	// +optional
+	ThanosQuerierURL string `json:"thanosQuerierURL,omitempty"`
+
+	// CAConfigMap is the ConfigMap holding the CA certificates verifying the
+	// discovered endpoints, in the namespace/name format.
+	// +optional
+	CAConfigMap string `json:"caConfigMap,omitempty"`
+
+	// Error explains why the endpoints can't be discovered or why the ACM
+	// alerting is disabled.
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// PersesDefinitionStatus reports a Perses definition loaded from a ConfigMap.
+type PersesDefinitionStatus struct {
+	// Kind of the created resource, PersesDashboard or PersesDatasource.
+	Kind string `json:"kind"`
+
+	// Namespace of the ConfigMap and of the created resource.
+	Namespace string `json:"namespace"`
+
+	// ConfigMap is the name of the ConfigMap holding the definition.
+	ConfigMap string `json:"configMap"`
+
+	// Key of the ConfigMap holding the definition.
+	// +optional
+	Key string `json:"key,omitempty"`
+
+	// Name of the created resource.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Error explains why the definition isn't applied.
+	// +optional
+	Error string `json:"error,omitempty"`
+
+	// Warnings report the parts of a converted Grafana dashboard which
+	// aren't supported and are left out of the Perses dashboard.
+	// +optional
+	// +listType=atomic
+	Warnings []string `json:"warnings,omitempty"`
+
+	// SkippedPanels are the titles of the panels of a converted Grafana
+	// dashboard which aren't supported and are left out of the Perses
+	// dashboard.
+	// +optional
+	// +listType=atomic
+	SkippedPanels []string `json:"skippedPanels,omitempty"`
+}
+
+// LoggingStatus reports the capabilities of the LokiStacks detected for the
+// Logging plugin.
+type LoggingStatus struct {
+	// Schema is the schema used for logs querying and display.
+	Schema string `json:"schema"`
+
+	// OTLP is true when a LokiStack is configured to ingest OTLP logs.
+	OTLP bool `json:"otlp"`
+
+	// Ruler is true when the ruler of a LokiStack is enabled.
+	Ruler bool `json:"ruler"`
+
+	// Alerts is true when the log-based alerts views are enabled.
+	Alerts bool `json:"alerts"`
+}
+
+// Korrel8rStoreSource tells where the store of a korrel8r domain comes from.
+// +kubebuilder:validation:Enum=Configured;Discovered;Default
+type Korrel8rStoreSource string
+
+const (
+	// Korrel8rStoreConfigured is a store declared in the UIPlugin.
+	Korrel8rStoreConfigured Korrel8rStoreSource = "Configured"
+	// Korrel8rStoreDiscovered is a store discovered by the operator.
+	Korrel8rStoreDiscovered Korrel8rStoreSource = "Discovered"
+	// Korrel8rStoreDefault is a store expected at its default location.
+	Korrel8rStoreDefault Korrel8rStoreSource = "Default"
+)
+
+// Korrel8rStoreStatus reports a store configured in korrel8r.
+type Korrel8rStoreStatus struct {
+	// Domain of the store.
+	Domain string `json:"domain"`
+
+	// URL of the store.
+	// +optional
+	URL string `json:"url,omitempty"`
+
+	// Source tells whether the store is declared in the UIPlugin, discovered
+	// by the operator or expected at its default location.
+	Source Korrel8rStoreSource `json:"source"`
+}
+
+// ConsolePluginStatus reports the registration of the plugin with the console.
+type ConsolePluginStatus struct {
+	// Name of the ConsolePlugin resource.
+	Name string `json:"name"`
+
+	// Enabled is true when the plugin is enabled in the Console operator configuration.
+	Enabled bool `json:"enabled"`
+}
+
+// BackendType is the type of a backend used by a plugin.
+// +kubebuilder:validation:Enum=LokiStack;TempoStack;TempoMonolithic;ThanosQuerier;Alertmanager;Korrel8r;Perses
+type BackendType string
+
+const (
+	BackendTypeLokiStack       BackendType = "LokiStack"
+	BackendTypeTempoStack      BackendType = "TempoStack"
+	BackendTypeTempoMonolithic BackendType = "TempoMonolithic"
+	BackendTypeThanosQuerier   BackendType = "ThanosQuerier"
+	BackendTypeAlertmanager    BackendType = "Alertmanager"
+	BackendTypeKorrel8r        BackendType = "Korrel8r"
+	BackendTypePerses          BackendType = "Perses"
+)
+
+// UIPluginBackend is a backend resolved by the operator for a plugin.
+type UIPluginBackend struct {
+	// Type of the backend.
+	Type BackendType `json:"type"`
+
+	// Name of the resource or service of the backend, if it runs in the cluster.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Namespace of the resource or service of the backend, if it runs in the cluster.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// URL of the backend.
+	// +optional
+	URL string `json:"url,omitempty"`
 }
 
 type ConditionStatus string
@@ -331,4 +1134,8 @@ const (
 	AvailableCondition         ConditionType = "Available"
 	ResourceDiscoveryCondition ConditionType = "ResourceDiscovery"
 	DegradedCondition          ConditionType = "Degraded"
+	OverridesAppliedCondition  ConditionType = "OverridesApplied"
+	// LoggingCapabilitiesDetectedCondition reports whether the capabilities
+	// of the LokiStacks queried by the Logging plugin could be detected.
+	LoggingCapabilitiesDetectedCondition ConditionType = "LoggingCapabilitiesDetected"
 )
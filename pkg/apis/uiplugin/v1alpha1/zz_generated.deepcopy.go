@@ -21,11 +21,28 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"github.com/rhobs/observability-operator/pkg/apis/common/v1alpha1"
 	"k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ACMStatus) DeepCopyInto(out *ACMStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ACMStatus.
+func (in *ACMStatus) DeepCopy() *ACMStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ACMStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AdvancedClusterManagementReference) DeepCopyInto(out *AdvancedClusterManagementReference) {
 	*out = *in
@@ -61,6 +78,21 @@ func (in *AlertmanagerReference) DeepCopy() *AlertmanagerReference {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterHealthAnalyzerReference) DeepCopyInto(out *ClusterHealthAnalyzerReference) {
 	*out = *in
+	if in.Deployment != nil {
+		in, out := &in.Deployment, &out.Deployment
+		*out = new(DeploymentConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = new(NamespacedConfigMapReference)
+		**out = **in
+	}
+	if in.Target != nil {
+		in, out := &in.Target, &out.Target
+		*out = new(HealthAnalyzerTarget)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterHealthAnalyzerReference.
@@ -73,6 +105,36 @@ func (in *ClusterHealthAnalyzerReference) DeepCopy() *ClusterHealthAnalyzerRefer
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigMapKeyReference) DeepCopyInto(out *ConfigMapKeyReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigMapKeyReference.
+func (in *ConfigMapKeyReference) DeepCopy() *ConfigMapKeyReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigMapKeyReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConsolePluginStatus) DeepCopyInto(out *ConsolePluginStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConsolePluginStatus.
+func (in *ConsolePluginStatus) DeepCopy() *ConsolePluginStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ConsolePluginStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DeploymentConfig) DeepCopyInto(out *DeploymentConfig) {
 	*out = *in
@@ -90,6 +152,33 @@ func (in *DeploymentConfig) DeepCopyInto(out *DeploymentConfig) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(v1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]v1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PodDisruptionBudget != nil {
+		in, out := &in.PodDisruptionBudget, &out.PodDisruptionBudget
+		*out = new(PodDisruptionBudgetConfig)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeploymentConfig.
@@ -105,6 +194,16 @@ func (in *DeploymentConfig) DeepCopy() *DeploymentConfig {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DistributedTracingConfig) DeepCopyInto(out *DistributedTracingConfig) {
 	*out = *in
+	if in.Instances != nil {
+		in, out := &in.Instances, &out.Instances
+		*out = make([]TempoInstanceReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.InstanceSelector != nil {
+		in, out := &in.InstanceSelector, &out.InstanceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DistributedTracingConfig.
@@ -117,6 +216,21 @@ func (in *DistributedTracingConfig) DeepCopy() *DistributedTracingConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealthAnalyzerTarget) DeepCopyInto(out *HealthAnalyzerTarget) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthAnalyzerTarget.
+func (in *HealthAnalyzerTarget) DeepCopy() *HealthAnalyzerTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(HealthAnalyzerTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IncidentsReference) DeepCopyInto(out *IncidentsReference) {
 	*out = *in
@@ -132,6 +246,98 @@ func (in *IncidentsReference) DeepCopy() *IncidentsReference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Korrel8rConfig) DeepCopyInto(out *Korrel8rConfig) {
+	*out = *in
+	if in.Stores != nil {
+		in, out := &in.Stores, &out.Stores
+		*out = make([]Korrel8rStore, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DisabledDomains != nil {
+		in, out := &in.DisabledDomains, &out.DisabledDomains
+		*out = make([]Korrel8rDomain, len(*in))
+		copy(*out, *in)
+	}
+	if in.Deployment != nil {
+		in, out := &in.Deployment, &out.Deployment
+		*out = new(DeploymentConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]ConfigMapKeyReference, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Korrel8rConfig.
+func (in *Korrel8rConfig) DeepCopy() *Korrel8rConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(Korrel8rConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Korrel8rStore) DeepCopyInto(out *Korrel8rStore) {
+	*out = *in
+	if in.CertificateAuthority != nil {
+		in, out := &in.CertificateAuthority, &out.CertificateAuthority
+		*out = new(ConfigMapKeyReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Korrel8rStore.
+func (in *Korrel8rStore) DeepCopy() *Korrel8rStore {
+	if in == nil {
+		return nil
+	}
+	out := new(Korrel8rStore)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Korrel8rStoreStatus) DeepCopyInto(out *Korrel8rStoreStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Korrel8rStoreStatus.
+func (in *Korrel8rStoreStatus) DeepCopy() *Korrel8rStoreStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(Korrel8rStoreStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoggingAlertsConfig) DeepCopyInto(out *LoggingAlertsConfig) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoggingAlertsConfig.
+func (in *LoggingAlertsConfig) DeepCopy() *LoggingAlertsConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(LoggingAlertsConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LoggingConfig) DeepCopyInto(out *LoggingConfig) {
 	*out = *in
@@ -140,6 +346,18 @@ func (in *LoggingConfig) DeepCopyInto(out *LoggingConfig) {
 		*out = new(LokiStackReference)
 		**out = **in
 	}
+	if in.LokiStacks != nil {
+		in, out := &in.LokiStacks, &out.LokiStacks
+		*out = make([]LokiStackBackend, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Alerts != nil {
+		in, out := &in.Alerts, &out.Alerts
+		*out = new(LoggingAlertsConfig)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoggingConfig.
@@ -152,6 +370,41 @@ func (in *LoggingConfig) DeepCopy() *LoggingConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoggingStatus) DeepCopyInto(out *LoggingStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoggingStatus.
+func (in *LoggingStatus) DeepCopy() *LoggingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LoggingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LokiStackBackend) DeepCopyInto(out *LokiStackBackend) {
+	*out = *in
+	if in.Tenants != nil {
+		in, out := &in.Tenants, &out.Tenants
+		*out = make([]LoggingTenant, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LokiStackBackend.
+func (in *LokiStackBackend) DeepCopy() *LokiStackBackend {
+	if in == nil {
+		return nil
+	}
+	out := new(LokiStackBackend)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LokiStackReference) DeepCopyInto(out *LokiStackReference) {
 	*out = *in
@@ -178,7 +431,7 @@ func (in *MonitoringConfig) DeepCopyInto(out *MonitoringConfig) {
 	if in.Perses != nil {
 		in, out := &in.Perses, &out.Perses
 		*out = new(PersesReference)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.Incidents != nil {
 		in, out := &in.Incidents, &out.Incidents
@@ -188,7 +441,7 @@ func (in *MonitoringConfig) DeepCopyInto(out *MonitoringConfig) {
 	if in.ClusterHealthAnalyzer != nil {
 		in, out := &in.ClusterHealthAnalyzer, &out.ClusterHealthAnalyzer
 		*out = new(ClusterHealthAnalyzerReference)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 }
 
@@ -202,9 +455,183 @@ func (in *MonitoringConfig) DeepCopy() *MonitoringConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespacedConfigMapReference) DeepCopyInto(out *NamespacedConfigMapReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespacedConfigMapReference.
+func (in *NamespacedConfigMapReference) DeepCopy() *NamespacedConfigMapReference {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespacedConfigMapReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PersesAllowedEndpoint) DeepCopyInto(out *PersesAllowedEndpoint) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PersesAllowedEndpoint.
+func (in *PersesAllowedEndpoint) DeepCopy() *PersesAllowedEndpoint {
+	if in == nil {
+		return nil
+	}
+	out := new(PersesAllowedEndpoint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PersesDatasourceProxy) DeepCopyInto(out *PersesDatasourceProxy) {
+	*out = *in
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.AllowedEndpoints != nil {
+		in, out := &in.AllowedEndpoints, &out.AllowedEndpoints
+		*out = make([]PersesAllowedEndpoint, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PersesDatasourceProxy.
+func (in *PersesDatasourceProxy) DeepCopy() *PersesDatasourceProxy {
+	if in == nil {
+		return nil
+	}
+	out := new(PersesDatasourceProxy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PersesDefinitionStatus) DeepCopyInto(out *PersesDefinitionStatus) {
+	*out = *in
+	if in.Warnings != nil {
+		in, out := &in.Warnings, &out.Warnings
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SkippedPanels != nil {
+		in, out := &in.SkippedPanels, &out.SkippedPanels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PersesDefinitionStatus.
+func (in *PersesDefinitionStatus) DeepCopy() *PersesDefinitionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PersesDefinitionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PersesDefinitionsReference) DeepCopyInto(out *PersesDefinitionsReference) {
+	*out = *in
+	if in.ConfigMaps != nil {
+		in, out := &in.ConfigMaps, &out.ConfigMaps
+		*out = make([]NamespacedConfigMapReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PersesDefinitionsReference.
+func (in *PersesDefinitionsReference) DeepCopy() *PersesDefinitionsReference {
+	if in == nil {
+		return nil
+	}
+	out := new(PersesDefinitionsReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PersesIngress) DeepCopyInto(out *PersesIngress) {
+	*out = *in
+	if in.IngressClassName != nil {
+		in, out := &in.IngressClassName, &out.IngressClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PersesIngress.
+func (in *PersesIngress) DeepCopy() *PersesIngress {
+	if in == nil {
+		return nil
+	}
+	out := new(PersesIngress)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PersesReference) DeepCopyInto(out *PersesReference) {
 	*out = *in
+	if in.Deployment != nil {
+		in, out := &in.Deployment, &out.Deployment
+		*out = new(DeploymentConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Ingress != nil {
+		in, out := &in.Ingress, &out.Ingress
+		*out = new(PersesIngress)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Dashboards != nil {
+		in, out := &in.Dashboards, &out.Dashboards
+		*out = new(PersesDefinitionsReference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Datasources != nil {
+		in, out := &in.Datasources, &out.Datasources
+		*out = new(PersesDefinitionsReference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GrafanaDashboards != nil {
+		in, out := &in.GrafanaDashboards, &out.GrafanaDashboards
+		*out = new(PersesDefinitionsReference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MonitoringStackSelector != nil {
+		in, out := &in.MonitoringStackSelector, &out.MonitoringStackSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Storage != nil {
+		in, out := &in.Storage, &out.Storage
+		*out = new(PersesStorage)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DatasourceProxy != nil {
+		in, out := &in.DatasourceProxy, &out.DatasourceProxy
+		*out = new(PersesDatasourceProxy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PersesReference.
@@ -217,6 +644,86 @@ func (in *PersesReference) DeepCopy() *PersesReference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PersesSQLStorage) DeepCopyInto(out *PersesSQLStorage) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PersesSQLStorage.
+func (in *PersesSQLStorage) DeepCopy() *PersesSQLStorage {
+	if in == nil {
+		return nil
+	}
+	out := new(PersesSQLStorage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PersesStorage) DeepCopyInto(out *PersesStorage) {
+	*out = *in
+	if in.PersistentVolumeClaim != nil {
+		in, out := &in.PersistentVolumeClaim, &out.PersistentVolumeClaim
+		*out = new(v1.PersistentVolumeClaimSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SQL != nil {
+		in, out := &in.SQL, &out.SQL
+		*out = new(PersesSQLStorage)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PersesStorage.
+func (in *PersesStorage) DeepCopy() *PersesStorage {
+	if in == nil {
+		return nil
+	}
+	out := new(PersesStorage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodDisruptionBudgetConfig) DeepCopyInto(out *PodDisruptionBudgetConfig) {
+	*out = *in
+	if in.MinAvailable != nil {
+		in, out := &in.MinAvailable, &out.MinAvailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodDisruptionBudgetConfig.
+func (in *PodDisruptionBudgetConfig) DeepCopy() *PodDisruptionBudgetConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(PodDisruptionBudgetConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TempoInstanceReference) DeepCopyInto(out *TempoInstanceReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TempoInstanceReference.
+func (in *TempoInstanceReference) DeepCopy() *TempoInstanceReference {
+	if in == nil {
+		return nil
+	}
+	out := new(TempoInstanceReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ThanosQuerierReference) DeepCopyInto(out *ThanosQuerierReference) {
 	*out = *in
@@ -235,6 +742,11 @@ func (in *ThanosQuerierReference) DeepCopy() *ThanosQuerierReference {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TroubleshootingPanelConfig) DeepCopyInto(out *TroubleshootingPanelConfig) {
 	*out = *in
+	if in.Korrel8r != nil {
+		in, out := &in.Korrel8r, &out.Korrel8r
+		*out = new(Korrel8rConfig)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TroubleshootingPanelConfig.
@@ -274,6 +786,46 @@ func (in *UIPlugin) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UIPluginBackend) DeepCopyInto(out *UIPluginBackend) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UIPluginBackend.
+func (in *UIPluginBackend) DeepCopy() *UIPluginBackend {
+	if in == nil {
+		return nil
+	}
+	out := new(UIPluginBackend)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UIPluginFeatures) DeepCopyInto(out *UIPluginFeatures) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Disabled != nil {
+		in, out := &in.Disabled, &out.Disabled
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UIPluginFeatures.
+func (in *UIPluginFeatures) DeepCopy() *UIPluginFeatures {
+	if in == nil {
+		return nil
+	}
+	out := new(UIPluginFeatures)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *UIPluginList) DeepCopyInto(out *UIPluginList) {
 	*out = *in
@@ -314,15 +866,20 @@ func (in *UIPluginSpec) DeepCopyInto(out *UIPluginSpec) {
 		*out = new(DeploymentConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Features != nil {
+		in, out := &in.Features, &out.Features
+		*out = new(UIPluginFeatures)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.TroubleshootingPanel != nil {
 		in, out := &in.TroubleshootingPanel, &out.TroubleshootingPanel
 		*out = new(TroubleshootingPanelConfig)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.DistributedTracing != nil {
 		in, out := &in.DistributedTracing, &out.DistributedTracing
 		*out = new(DistributedTracingConfig)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.Logging != nil {
 		in, out := &in.Logging, &out.Logging
@@ -334,6 +891,11 @@ func (in *UIPluginSpec) DeepCopyInto(out *UIPluginSpec) {
 		*out = new(MonitoringConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Overrides != nil {
+		in, out := &in.Overrides, &out.Overrides
+		*out = make([]v1alpha1.ResourceOverride, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UIPluginSpec.
@@ -356,6 +918,48 @@ func (in *UIPluginStatus) DeepCopyInto(out *UIPluginStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ConsolePlugin != nil {
+		in, out := &in.ConsolePlugin, &out.ConsolePlugin
+		*out = new(ConsolePluginStatus)
+		**out = **in
+	}
+	if in.Backends != nil {
+		in, out := &in.Backends, &out.Backends
+		*out = make([]UIPluginBackend, len(*in))
+		copy(*out, *in)
+	}
+	if in.Korrel8rStores != nil {
+		in, out := &in.Korrel8rStores, &out.Korrel8rStores
+		*out = make([]Korrel8rStoreStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Logging != nil {
+		in, out := &in.Logging, &out.Logging
+		*out = new(LoggingStatus)
+		**out = **in
+	}
+	if in.PersesDefinitions != nil {
+		in, out := &in.PersesDefinitions, &out.PersesDefinitions
+		*out = make([]PersesDefinitionStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ACM != nil {
+		in, out := &in.ACM, &out.ACM
+		*out = new(ACMStatus)
+		**out = **in
+	}
+	if in.Features != nil {
+		in, out := &in.Features, &out.Features
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.UnsupportedFeatures != nil {
+		in, out := &in.UnsupportedFeatures, &out.UnsupportedFeatures
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UIPluginStatus.
@@ -7,6 +7,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/rhobs/observability-operator/pkg/apis/monitoring/v1alpha1"
+	"github.com/rhobs/observability-operator/pkg/reconciler"
 )
 
 const (
@@ -25,12 +26,16 @@ const (
 	NoReason                       = "None"
 )
 
-func updateConditions(ms *v1alpha1.MonitoringStack, prom monv1.Prometheus, recError error) []v1alpha1.Condition {
-	return []v1alpha1.Condition{
+func updateConditions(ms *v1alpha1.MonitoringStack, prom monv1.Prometheus, recError, overridesErr error) []v1alpha1.Condition {
+	conditions := []v1alpha1.Condition{
 		updateResourceDiscovery(ms),
 		updateAvailable(ms.Status.Conditions, prom, ms.Generation),
 		updateReconciled(ms.Status.Conditions, prom, ms.Generation, recError),
 	}
+	if len(ms.Spec.Overrides) > 0 {
+		conditions = append(conditions, updateOverridesApplied(ms.Status.Conditions, ms.Generation, overridesErr))
+	}
+	return conditions
 }
 
 func getMSCondition(conditions []v1alpha1.Condition, t v1alpha1.ConditionType) (v1alpha1.Condition, error) {
@@ -162,6 +167,29 @@ func updateReconciled(conditions []v1alpha1.Condition, prom monv1.Prometheus, ge
 	return rc
 }
 
+// updateOverridesApplied updates the "OverridesApplied" condition based on the
+// error returned when applying the overrides of the MonitoringStack spec.
+func updateOverridesApplied(conditions []v1alpha1.Condition, generation int64, overridesErr error) v1alpha1.Condition {
+	oc := v1alpha1.Condition{
+		Type:               v1alpha1.OverridesAppliedCondition,
+		Status:             v1alpha1.ConditionTrue,
+		Reason:             reconciler.OverridesAppliedReason,
+		Message:            reconciler.OverridesAppliedMessage,
+		ObservedGeneration: generation,
+	}
+	if overridesErr != nil {
+		oc.Status = v1alpha1.ConditionFalse
+		oc.Reason = reconciler.FailedToApplyOverridesReason
+		oc.Message = overridesErr.Error()
+	}
+
+	oc.LastTransitionTime = metav1.Now()
+	if previous, err := getMSCondition(conditions, v1alpha1.OverridesAppliedCondition); err == nil && previous.Status == oc.Status {
+		oc.LastTransitionTime = previous.LastTransitionTime
+	}
+	return oc
+}
+
 func getPrometheusCondition(prometheusConditions []monv1.Condition, t monv1.ConditionType) (*monv1.Condition, error) {
 	for _, c := range prometheusConditions {
 		if c.Type == t {
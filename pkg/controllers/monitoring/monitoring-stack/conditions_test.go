@@ -1,13 +1,16 @@
 package monitoringstack
 
 import (
+	"errors"
 	"testing"
+	"time"
 
 	monv1 "github.com/rhobs/obo-prometheus-operator/pkg/apis/monitoring/v1"
 	"gotest.tools/v3/assert"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/rhobs/observability-operator/pkg/apis/monitoring/v1alpha1"
+	"github.com/rhobs/observability-operator/pkg/reconciler"
 )
 
 func TestUpdateAvailable(t *testing.T) {
@@ -323,3 +326,63 @@ func TestUpdateResourceDiscovery(t *testing.T) {
 	}
 
 }
+
+func TestUpdateOverridesApplied(t *testing.T) {
+	transitionTime := metav1.NewTime(metav1.Now().Add(-time.Hour))
+	tt := []struct {
+		name               string
+		previousConditions []v1alpha1.Condition
+		overridesErr       error
+		expectedResult     v1alpha1.Condition
+	}{
+		{
+			name: "set overrides applied true when there's no error",
+			expectedResult: v1alpha1.Condition{
+				Type:               v1alpha1.OverridesAppliedCondition,
+				Status:             v1alpha1.ConditionTrue,
+				Reason:             reconciler.OverridesAppliedReason,
+				Message:            reconciler.OverridesAppliedMessage,
+				ObservedGeneration: 1,
+			},
+		},
+		{
+			name:         "set overrides applied false when an override fails",
+			overridesErr: errors.New("override 0 (Prometheus foo): no matching resource"),
+			expectedResult: v1alpha1.Condition{
+				Type:               v1alpha1.OverridesAppliedCondition,
+				Status:             v1alpha1.ConditionFalse,
+				Reason:             reconciler.FailedToApplyOverridesReason,
+				Message:            "override 0 (Prometheus foo): no matching resource",
+				ObservedGeneration: 1,
+			},
+		},
+		{
+			name: "keep the transition time when the status doesn't change",
+			previousConditions: []v1alpha1.Condition{
+				{
+					Type:               v1alpha1.OverridesAppliedCondition,
+					Status:             v1alpha1.ConditionTrue,
+					Reason:             reconciler.OverridesAppliedReason,
+					Message:            reconciler.OverridesAppliedMessage,
+					LastTransitionTime: transitionTime,
+				},
+			},
+			expectedResult: v1alpha1.Condition{
+				Type:               v1alpha1.OverridesAppliedCondition,
+				Status:             v1alpha1.ConditionTrue,
+				Reason:             reconciler.OverridesAppliedReason,
+				Message:            reconciler.OverridesAppliedMessage,
+				ObservedGeneration: 1,
+				LastTransitionTime: transitionTime,
+			},
+		},
+	}
+
+	for _, test := range tt {
+		res := updateOverridesApplied(test.previousConditions, 1, test.overridesErr)
+		assert.Check(t, test.expectedResult.Equal(res), "%s - expected:\n %v\n and got:\n %v\n", test.name, test.expectedResult, res)
+		if !test.expectedResult.LastTransitionTime.IsZero() {
+			assert.Check(t, test.expectedResult.LastTransitionTime.Equal(&res.LastTransitionTime), "%s - unexpected transition time", test.name)
+		}
+	}
+}
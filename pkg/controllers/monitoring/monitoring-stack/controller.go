@@ -27,6 +27,7 @@ import (
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/events"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -34,12 +35,14 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	stack "github.com/rhobs/observability-operator/pkg/apis/monitoring/v1alpha1"
+	"github.com/rhobs/observability-operator/pkg/reconciler"
 )
 
 type resourceManager struct {
 	k8sClient    client.Client
 	scheme       *runtime.Scheme
 	logger       logr.Logger
+	recorder     events.EventRecorder
 	prometheus   PrometheusConfiguration
 	alertmanager AlertmanagerConfiguration
 	thanos       ThanosConfiguration
@@ -70,10 +73,13 @@ const finalizerName = "monitoring.observability.openshift.io/finalizer"
 //+kubebuilder:rbac:groups=monitoring.rhobs,resources=monitoringstacks,verbs=list;watch;create;update;patch
 //+kubebuilder:rbac:groups=monitoring.rhobs,resources=monitoringstacks/status,verbs=get;update
 
+// RBAC for recording events
+//+kubebuilder:rbac:groups=events.k8s.io,resources=events,verbs=create;patch
+
 // RBAC for managing Prometheus Operator CRs
 //+kubebuilder:rbac:groups=monitoring.rhobs,resources=alertmanagers;prometheuses;servicemonitors,verbs=list;watch;create;update;delete;patch
 //+kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles;rolebindings;clusterroles;clusterrolebindings,verbs=list;watch;create;update;delete;patch
-//+kubebuilder:rbac:groups="",resources=serviceaccounts;services;secrets,verbs=list;watch;create;update;delete;patch
+//+kubebuilder:rbac:groups="",resources=serviceaccounts;services;secrets;configmaps,verbs=list;watch;create;update;delete;patch
 //+kubebuilder:rbac:groups="policy",resources=poddisruptionbudgets,verbs=list;watch;create;update;delete;patch
 
 // RBAC for delegating permissions to Prometheus
@@ -89,6 +95,7 @@ func RegisterWithManager(mgr ctrl.Manager, opts Options) error {
 	rm := &resourceManager{
 		k8sClient:    mgr.GetClient(),
 		scheme:       mgr.GetScheme(),
+		recorder:     mgr.GetEventRecorder("observability-operator"),
 		logger:       ctrl.Log.WithName("observability-operator"),
 		thanos:       opts.Thanos,
 		prometheus:   opts.Prometheus,
@@ -132,9 +139,10 @@ func (rm resourceManager) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 	if !ms.ObjectMeta.DeletionTimestamp.IsZero() {
 		logger.V(6).Info("removing cluster scoped resources")
 
+		recorder := reconciler.NewEventRecorder(rm.recorder, ms)
 		reconcilers := stackComponentCleanup(ms)
 		for _, reconciler := range reconcilers {
-			err := reconciler.Reconcile(ctx, rm.k8sClient, rm.scheme)
+			err := reconciler.Reconcile(ctx, rm.k8sClient, rm.scheme, recorder)
 			if err != nil {
 				logger.Error(err, "failed to cleanup monitoring stack")
 			}
@@ -168,28 +176,39 @@ func (rm resourceManager) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 		}
 	}
 
-	reconcilers := stackComponentReconcilers(ms,
+	plan := reconciler.NewPlan()
+	components := plan.AddAll(stackComponentReconcilers(ms,
 		rm.thanos,
 		rm.prometheus,
 		rm.alertmanager,
-	)
-	for _, reconciler := range reconcilers {
-		err := reconciler.Reconcile(ctx, rm.k8sClient, rm.scheme)
-		// handle create / update errors that can happen due to a stale cache by
-		// retrying after some time.
-		if errors.IsAlreadyExists(err) || errors.IsConflict(err) {
-			logger.V(3).Info("skipping reconcile error", "err", err)
-			return ctrl.Result{RequeueAfter: 2 * time.Second}, nil
-		}
-		if err != nil {
-			return rm.updateStatus(ctx, req, ms, err), err
-		}
+	))
+	// Failed overrides are reported in the status and don't prevent the
+	// reconciliation of the resources.
+	overridesErr := reconciler.ApplyOverrides(plan.Reconcilers(), ms.Spec.Overrides)
+	if overridesErr != nil {
+		logger.Info("failed to apply overrides", "err", overridesErr)
+	}
+	// Prune the resources which were applied by a previous reconciliation
+	// but aren't desired anymore.
+	plan.Add(reconciler.NewInventory(ms, ms.Namespace, plan.Reconcilers()), components...)
+
+	// The components which don't depend on a failing one are still
+	// reconciled, the failures are reported in the Reconciled condition.
+	err = plan.Reconcile(ctx, rm.k8sClient, rm.scheme, reconciler.NewEventRecorder(rm.recorder, ms))
+	// handle create / update errors that can happen due to a stale cache by
+	// retrying after some time.
+	if reconciler.IsStaleCacheError(err) {
+		logger.V(3).Info("skipping reconcile error", "err", err)
+		return ctrl.Result{RequeueAfter: 2 * time.Second}, nil
+	}
+	if err != nil {
+		return rm.updateStatus(ctx, req, ms, err, overridesErr), err
 	}
 
-	return rm.updateStatus(ctx, req, ms, nil), nil
+	return rm.updateStatus(ctx, req, ms, nil, overridesErr), nil
 }
 
-func (rm resourceManager) updateStatus(ctx context.Context, req ctrl.Request, ms *stack.MonitoringStack, recError error) ctrl.Result {
+func (rm resourceManager) updateStatus(ctx context.Context, req ctrl.Request, ms *stack.MonitoringStack, recError, overridesErr error) ctrl.Result {
 	var prom monv1.Prometheus
 	logger := rm.logger.WithValues("stack", req.NamespacedName)
 	key := client.ObjectKey{
@@ -201,7 +220,7 @@ func (rm resourceManager) updateStatus(ctx context.Context, req ctrl.Request, ms
 		logger.Info("Failed to get prometheus object", "err", err)
 		return ctrl.Result{RequeueAfter: 2 * time.Second}
 	}
-	ms.Status.Conditions = updateConditions(ms, prom, recError)
+	ms.Status.Conditions = updateConditions(ms, prom, recError, overridesErr)
 	err = rm.k8sClient.Status().Update(ctx, ms)
 	if err != nil {
 		logger.Info("Failed to update status", "err", err)
@@ -16,6 +16,7 @@ import (
 	"context"
 	"crypto/sha256"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -28,6 +29,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/client-go/tools/events"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -36,13 +38,15 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	msoapi "github.com/rhobs/observability-operator/pkg/apis/monitoring/v1alpha1"
+	"github.com/rhobs/observability-operator/pkg/reconciler"
 )
 
 type resourceManager struct {
 	client.Client
-	scheme *runtime.Scheme
-	logger logr.Logger
-	thanos ThanosConfiguration
+	scheme   *runtime.Scheme
+	logger   logr.Logger
+	recorder events.EventRecorder
+	thanos   ThanosConfiguration
 }
 
 type ThanosConfiguration struct {
@@ -58,6 +62,10 @@ const (
 	thanosTLSPrivateKeySecretNameField           = ".spec.webTLSConfig.privateKey.name"
 	thanosTLSCertificateSecretNameField          = ".spec.webTLSConfig.certificate.name"
 	thanosTLSCertificateAuthoritySecretNameField = ".spec.webTLSConfig.certificateAuthority.name"
+
+	ReconciledReason        = "ThanosQuerierReconciled"
+	FailedToReconcileReason = "FailedToReconcile"
+	ReconciledMessage       = "Thanos Querier is successfully reconciled"
 )
 
 // RBAC for watching monitoring stacks
@@ -68,6 +76,9 @@ const (
 //+kubebuilder:rbac:groups=monitoring.rhobs,resources=thanosqueriers/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=monitoring.rhobs,resources=thanosqueriers/finalizers,verbs=update
 
+// RBAC for recording events
+//+kubebuilder:rbac:groups=events.k8s.io,resources=events,verbs=create;patch
+
 // RBAC for managing deployments
 //+kubebuilder:rbac:groups=apps,resources=deployments,verbs=list;watch;create;update;patch;delete
 
@@ -83,10 +94,11 @@ func RegisterWithManager(mgr ctrl.Manager, opts Options) error {
 	logger := ctrl.Log.WithName("thanos-querier")
 
 	rm := &resourceManager{
-		Client: mgr.GetClient(),
-		scheme: mgr.GetScheme(),
-		logger: logger,
-		thanos: opts.Thanos,
+		Client:   mgr.GetClient(),
+		scheme:   mgr.GetScheme(),
+		logger:   logger,
+		recorder: mgr.GetEventRecorder("observability-operator"),
+		thanos:   opts.Thanos,
 	}
 
 	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &msoapi.ThanosQuerier{}, thanosTLSPrivateKeySecretNameField, func(rawObj client.Object) []string {
@@ -178,20 +190,97 @@ func (rm resourceManager) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 		}
 	}
 
-	reconcilers := thanosComponentReconcilers(querier, sidecarServices, rm.thanos, tlsHashes)
-	for _, reconciler := range reconcilers {
-		err := reconciler.Reconcile(ctx, rm, rm.scheme)
-		// handle creation / updation errors that can happen due to a stale cache by
-		// retrying after some time.
-		if apierrors.IsAlreadyExists(err) || apierrors.IsConflict(err) {
-			logger.V(8).Info("skipping reconcile error", "err", err)
-			return ctrl.Result{RequeueAfter: 2 * time.Second}, nil
+	plan := reconciler.NewPlan()
+	components := plan.AddAll(thanosComponentReconcilers(querier, sidecarServices, rm.thanos, tlsHashes))
+	// Failed overrides are reported in the status and don't prevent the
+	// reconciliation of the resources.
+	overridesErr := reconciler.ApplyOverrides(plan.Reconcilers(), querier.Spec.Overrides)
+	if overridesErr != nil {
+		logger.Info("failed to apply overrides", "err", overridesErr)
+	}
+	// Prune the resources which were applied by a previous reconciliation
+	// but aren't desired anymore.
+	plan.Add(reconciler.NewInventory(querier, querier.Namespace, plan.Reconcilers()), components...)
+
+	err = plan.Reconcile(ctx, rm, rm.scheme, reconciler.NewEventRecorder(rm.recorder, querier))
+	// handle creation / updation errors that can happen due to a stale cache by
+	// retrying after some time.
+	if reconciler.IsStaleCacheError(err) {
+		logger.V(8).Info("skipping reconcile error", "err", err)
+		return ctrl.Result{RequeueAfter: 2 * time.Second}, nil
+	}
+	if statusErr := rm.updateStatus(ctx, querier, err, overridesErr); statusErr != nil {
+		logger.Info("failed to update status", "err", statusErr)
+		return ctrl.Result{RequeueAfter: 2 * time.Second}, err
+	}
+	return ctrl.Result{}, err
+}
+
+// updateStatus sets the Reconciled condition of the querier and its
+// OverridesApplied condition when it has overrides.
+func (rm resourceManager) updateStatus(ctx context.Context, querier *msoapi.ThanosQuerier, reconcileErr, overridesErr error) error {
+	reconciled := msoapi.Condition{
+		Type:               msoapi.ReconciledCondition,
+		Status:             msoapi.ConditionTrue,
+		Reason:             ReconciledReason,
+		Message:            ReconciledMessage,
+		ObservedGeneration: querier.Generation,
+	}
+	if reconcileErr != nil {
+		reconciled.Status = msoapi.ConditionFalse
+		reconciled.Reason = FailedToReconcileReason
+		reconciled.Message = reconcileErr.Error()
+	}
+	conditions, changed := setCondition(querier.Status.Conditions, reconciled)
+
+	if len(querier.Spec.Overrides) > 0 {
+		overrides := msoapi.Condition{
+			Type:               msoapi.OverridesAppliedCondition,
+			Status:             msoapi.ConditionTrue,
+			Reason:             reconciler.OverridesAppliedReason,
+			Message:            reconciler.OverridesAppliedMessage,
+			ObservedGeneration: querier.Generation,
+		}
+		if overridesErr != nil {
+			overrides.Status = msoapi.ConditionFalse
+			overrides.Reason = reconciler.FailedToApplyOverridesReason
+			overrides.Message = overridesErr.Error()
+		}
+		var overridesChanged bool
+		conditions, overridesChanged = setCondition(conditions, overrides)
+		changed = changed || overridesChanged
+	} else if i := slices.IndexFunc(conditions, func(c msoapi.Condition) bool { return c.Type == msoapi.OverridesAppliedCondition }); i >= 0 {
+		conditions = slices.Delete(conditions, i, i+1)
+		changed = true
+	}
+
+	if !changed {
+		return nil
+	}
+	querier.Status.Conditions = conditions
+	return rm.Status().Update(ctx, querier)
+}
+
+// setCondition returns the conditions with the given condition added or
+// updated and whether they changed. The transition time is kept when the
+// status of the condition doesn't change.
+func setCondition(conditions []msoapi.Condition, condition msoapi.Condition) ([]msoapi.Condition, bool) {
+	conditions = slices.Clone(conditions)
+	condition.LastTransitionTime = metav1.Now()
+	for i, c := range conditions {
+		if c.Type != condition.Type {
+			continue
 		}
-		if err != nil {
-			return ctrl.Result{}, err
+		if c.Equal(condition) {
+			return conditions, false
+		}
+		if c.Status == condition.Status {
+			condition.LastTransitionTime = c.LastTransitionTime
 		}
+		conditions[i] = condition
+		return conditions, true
 	}
-	return ctrl.Result{}, nil
+	return append(conditions, condition), true
 }
 
 // Given a ThanosQuerier object, find the matching MonitoringStacks, extract the
@@ -0,0 +1,231 @@
+package observability
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	tempov1alpha1 "github.com/grafana/tempo-operator/api/tempo/v1alpha1"
+	otelv1beta1 "github.com/open-telemetry/opentelemetry-operator/apis/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	obsv1alpha1 "github.com/rhobs/observability-operator/pkg/apis/observability/v1alpha1"
+	"github.com/rhobs/observability-operator/pkg/reconciler"
+)
+
+// adoptedTempoStackName returns the name of the adopted TempoStack or an empty string.
+func adoptedTempoStackName(instance *obsv1alpha1.ObservabilityInstaller) string {
+	if adopt := instance.Spec.GetCapabilities().GetTracing().GetAdopt(); adopt != nil {
+		return adopt.TempoStack
+	}
+	return ""
+}
+
+// adoptedOtelCollectorName returns the name of the adopted OpenTelemetryCollector or an empty string.
+func adoptedOtelCollectorName(instance *obsv1alpha1.ObservabilityInstaller) string {
+	if adopt := instance.Spec.GetCapabilities().GetTracing().GetAdopt(); adopt != nil {
+		return adopt.OpenTelemetryCollector
+	}
+	return ""
+}
+
+// instanceTempoName returns the name of the TempoStack used by the tracing capability.
+func instanceTempoName(instance *obsv1alpha1.ObservabilityInstaller) string {
+	if name := adoptedTempoStackName(instance); name != "" {
+		return name
+	}
+	return tempoName(instance.Name)
+}
+
+// instanceOtelCollectorName returns the name of the OpenTelemetryCollector used by the tracing capability.
+func instanceOtelCollectorName(instance *obsv1alpha1.ObservabilityInstaller) string {
+	if name := adoptedOtelCollectorName(instance); name != "" {
+		return name
+	}
+	return otelCollectorName(instance.Name)
+}
+
+// adoptedInstances holds the existing instances referenced by the tracing capability.
+type adoptedInstances struct {
+	tempoStack    *tempov1alpha1.TempoStack
+	otelCollector *otelv1beta1.OpenTelemetryCollector
+}
+
+// otelCollectorServiceAccount returns the service account of the collector
+// which is granted the permissions to write traces.
+func (a *adoptedInstances) otelCollectorServiceAccount(instance *obsv1alpha1.ObservabilityInstaller) string {
+	if a.otelCollector != nil && a.otelCollector.Spec.ServiceAccount != "" {
+		return a.otelCollector.Spec.ServiceAccount
+	}
+	return instanceOtelCollectorName(instance) + "-collector"
+}
+
+// getAdoptedInstances fetches and validates the instances referenced in the adopt section.
+func getAdoptedInstances(ctx context.Context, k8sReader client.Reader, instance *obsv1alpha1.ObservabilityInstaller) (*adoptedInstances, error) {
+	adopted := &adoptedInstances{}
+
+	if name := adoptedTempoStackName(instance); name != "" {
+		tempo := &tempov1alpha1.TempoStack{}
+		if err := k8sReader.Get(ctx, client.ObjectKey{Namespace: instance.Namespace, Name: name}, tempo); err != nil {
+			return nil, fmt.Errorf("failed to get adopted TempoStack %s: %w", name, err)
+		}
+		if err := validateAdoptedTempoStack(tempo); err != nil {
+			return nil, fmt.Errorf("cannot adopt TempoStack %s: %w", name, err)
+		}
+		if err := validateAdoptedOwner(tempo, instance); err != nil {
+			return nil, fmt.Errorf("cannot adopt TempoStack %s: %w", name, err)
+		}
+		adopted.tempoStack = tempo
+	}
+
+	if name := adoptedOtelCollectorName(instance); name != "" {
+		otelcol := &otelv1beta1.OpenTelemetryCollector{}
+		if err := k8sReader.Get(ctx, client.ObjectKey{Namespace: instance.Namespace, Name: name}, otelcol); err != nil {
+			return nil, fmt.Errorf("failed to get adopted OpenTelemetryCollector %s: %w", name, err)
+		}
+		if err := validateAdoptedOwner(otelcol, instance); err != nil {
+			return nil, fmt.Errorf("cannot adopt OpenTelemetryCollector %s: %w", name, err)
+		}
+		adopted.otelCollector = otelcol
+	}
+
+	return adopted, nil
+}
+
+// validateAdoptedTempoStack checks that the collector deployed by the
+// installer can write traces to the TempoStack.
+func validateAdoptedTempoStack(tempo *tempov1alpha1.TempoStack) error {
+	if !tempo.Spec.Template.Gateway.Enabled {
+		return fmt.Errorf("the gateway must be enabled")
+	}
+	if tempo.Spec.Tenants == nil || tempo.Spec.Tenants.Mode != tempov1alpha1.ModeOpenShift {
+		return fmt.Errorf("the tenants mode must be %s", tempov1alpha1.ModeOpenShift)
+	}
+	if !slices.ContainsFunc(tempo.Spec.Tenants.Authentication, func(a tempov1alpha1.AuthenticationSpec) bool {
+		return a.TenantName == tenantName
+	}) {
+		return fmt.Errorf("a tenant called %s is required", tenantName)
+	}
+	return nil
+}
+
+// validateAdoptedOwner refuses objects controlled by another owner.
+func validateAdoptedOwner(obj client.Object, instance *obsv1alpha1.ObservabilityInstaller) error {
+	if owner := metav1.GetControllerOf(obj); owner != nil && owner.UID != instance.UID {
+		return fmt.Errorf("already controlled by %s %s", owner.Kind, owner.Name)
+	}
+	return nil
+}
+
+// ownerReferenceRemover removes the owner references of the installer from
+// an existing object, so that it is not garbage collected with the installer.
+type ownerReferenceRemover struct {
+	owner    metav1.Object
+	resource client.Object
+	// labels are added to the object together with the owner reference removal.
+	labels map[string]string
+}
+
+func newOwnerReferenceRemover(resource client.Object, owner metav1.Object) ownerReferenceRemover {
+	return ownerReferenceRemover{owner: owner, resource: resource}
+}
+
+func (r ownerReferenceRemover) String() string {
+	return fmt.Sprintf("%s %s/%s", r.resource.GetObjectKind().GroupVersionKind().Kind, r.resource.GetNamespace(), r.resource.GetName())
+}
+
+func (r ownerReferenceRemover) Reconcile(ctx context.Context, c client.Client, _ *runtime.Scheme, recorder reconciler.EventRecorder) error {
+	description := r.String()
+
+	// Objects which are not read from the cluster yet are created by the
+	// installer, they carry the operator labels and are served by the cache.
+	if r.resource.GetResourceVersion() == "" {
+		if err := c.Get(ctx, client.ObjectKeyFromObject(r.resource), r.resource); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil
+			}
+			return fmt.Errorf("%s/%s (%s): failed to get object: %w",
+				r.resource.GetNamespace(), r.resource.GetName(),
+				r.resource.GetObjectKind().GroupVersionKind().String(), err)
+		}
+	}
+
+	patch := client.MergeFrom(r.resource.DeepCopyObject().(client.Object))
+	changed := false
+
+	refs := r.resource.GetOwnerReferences()
+	filtered := slices.DeleteFunc(slices.Clone(refs), func(ref metav1.OwnerReference) bool {
+		return ref.UID == r.owner.GetUID()
+	})
+	if len(filtered) != len(refs) {
+		r.resource.SetOwnerReferences(filtered)
+		changed = true
+	}
+
+	labels := r.resource.GetLabels()
+	for k, v := range r.labels {
+		if labels[k] == v {
+			continue
+		}
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		labels[k] = v
+		changed = true
+	}
+	r.resource.SetLabels(labels)
+
+	if !changed {
+		return nil
+	}
+	if err := c.Patch(ctx, r.resource, patch); client.IgnoreNotFound(err) != nil {
+		err = fmt.Errorf("%s/%s (%s): failed to remove owner reference: %w",
+			r.resource.GetNamespace(), r.resource.GetName(),
+			r.resource.GetObjectKind().GroupVersionKind().String(), err)
+		recorder.Failed(ctx, r.resource, "Release", err)
+		return err
+	}
+	recorder.Event(ctx, r.resource, corev1.EventTypeNormal, reconciler.ReasonUpdated, "Release", description+" released")
+	return nil
+}
+
+// replacedInstanceRemover removes an instance created by the installer once
+// it is replaced by an adopted instance with a different name. Instances which
+// aren't controlled by the installer are left untouched since they only share
+// the name of the installer's one. Data-bearing instances are never deleted
+// implicitly, they are released and reported as retained instead.
+type replacedInstanceRemover struct {
+	owner       metav1.Object
+	resource    client.Object
+	policy      obsv1alpha1.DeletionPolicy
+	dataBearing bool
+}
+
+func newReplacedInstanceRemover(resource client.Object, owner metav1.Object, policy obsv1alpha1.DeletionPolicy, dataBearing bool) replacedInstanceRemover {
+	return replacedInstanceRemover{owner: owner, resource: resource, policy: policy, dataBearing: dataBearing}
+}
+
+func (r replacedInstanceRemover) Reconcile(ctx context.Context, c client.Client, scheme *runtime.Scheme, recorder reconciler.EventRecorder) error {
+	current := r.resource.DeepCopyObject().(client.Object)
+	if err := c.Get(ctx, client.ObjectKeyFromObject(r.resource), current); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("%s/%s (%s): failed to get object: %w",
+			r.resource.GetNamespace(), r.resource.GetName(),
+			r.resource.GetObjectKind().GroupVersionKind().String(), err)
+	}
+	if owner := metav1.GetControllerOf(current); owner == nil || owner.UID != r.owner.GetUID() {
+		return nil
+	}
+	current.GetObjectKind().SetGroupVersionKind(r.resource.GetObjectKind().GroupVersionKind())
+
+	if r.dataBearing {
+		return newRetainer(current, r.owner).Reconcile(ctx, c, scheme, recorder)
+	}
+	return newRemover(current, r.owner, r.policy, false).Reconcile(ctx, c, scheme, recorder)
+}
@@ -0,0 +1,306 @@
+package observability
+
+import (
+	"context"
+	"testing"
+
+	tempov1alpha1 "github.com/grafana/tempo-operator/api/tempo/v1alpha1"
+	otelv1beta1 "github.com/open-telemetry/opentelemetry-operator/apis/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	obsv1alpha1 "github.com/rhobs/observability-operator/pkg/apis/observability/v1alpha1"
+	"github.com/rhobs/observability-operator/pkg/reconciler"
+)
+
+func TestGetAdoptedInstances(t *testing.T) {
+	validTempo := func() *tempov1alpha1.TempoStack {
+		return &tempov1alpha1.TempoStack{
+			ObjectMeta: metav1.ObjectMeta{Name: "existing", Namespace: "test-ns"},
+			Spec: tempov1alpha1.TempoStackSpec{
+				Template: tempov1alpha1.TempoTemplateSpec{
+					Gateway: tempov1alpha1.TempoGatewaySpec{Enabled: true},
+				},
+				Tenants: &tempov1alpha1.TenantsSpec{
+					Mode: tempov1alpha1.ModeOpenShift,
+					Authentication: []tempov1alpha1.AuthenticationSpec{
+						{TenantName: tenantName, TenantID: tenantID},
+					},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name            string
+		adopt           *obsv1alpha1.TracingAdoptSpec
+		objects         []client.Object
+		wantErr         string
+		wantTempo       bool
+		wantCollector   bool
+		wantCollectorSA string
+	}{
+		{
+			name:            "nothing adopted",
+			wantCollectorSA: "test-collector",
+		},
+		{
+			name:  "valid TempoStack and collector",
+			adopt: &obsv1alpha1.TracingAdoptSpec{TempoStack: "existing", OpenTelemetryCollector: "existing"},
+			objects: []client.Object{
+				validTempo(),
+				&otelv1beta1.OpenTelemetryCollector{
+					ObjectMeta: metav1.ObjectMeta{Name: "existing", Namespace: "test-ns"},
+					Spec: otelv1beta1.OpenTelemetryCollectorSpec{
+						OpenTelemetryCommonFields: otelv1beta1.OpenTelemetryCommonFields{ServiceAccount: "custom-sa"},
+					},
+				},
+			},
+			wantTempo:       true,
+			wantCollector:   true,
+			wantCollectorSA: "custom-sa",
+		},
+		{
+			name:            "collector without service account",
+			adopt:           &obsv1alpha1.TracingAdoptSpec{OpenTelemetryCollector: "existing"},
+			objects:         []client.Object{&otelv1beta1.OpenTelemetryCollector{ObjectMeta: metav1.ObjectMeta{Name: "existing", Namespace: "test-ns"}}},
+			wantCollector:   true,
+			wantCollectorSA: "existing-collector",
+		},
+		{
+			name:    "missing TempoStack",
+			adopt:   &obsv1alpha1.TracingAdoptSpec{TempoStack: "existing"},
+			wantErr: "failed to get adopted TempoStack existing",
+		},
+		{
+			name:  "TempoStack without gateway",
+			adopt: &obsv1alpha1.TracingAdoptSpec{TempoStack: "existing"},
+			objects: []client.Object{func() client.Object {
+				tempo := validTempo()
+				tempo.Spec.Template.Gateway.Enabled = false
+				return tempo
+			}()},
+			wantErr: "cannot adopt TempoStack existing: the gateway must be enabled",
+		},
+		{
+			name:  "TempoStack without application tenant",
+			adopt: &obsv1alpha1.TracingAdoptSpec{TempoStack: "existing"},
+			objects: []client.Object{func() client.Object {
+				tempo := validTempo()
+				tempo.Spec.Tenants.Authentication[0].TenantName = "dev"
+				return tempo
+			}()},
+			wantErr: "cannot adopt TempoStack existing: a tenant called application is required",
+		},
+		{
+			name:  "TempoStack controlled by another owner",
+			adopt: &obsv1alpha1.TracingAdoptSpec{TempoStack: "existing"},
+			objects: []client.Object{func() client.Object {
+				tempo := validTempo()
+				tempo.OwnerReferences = []metav1.OwnerReference{
+					{Kind: "ObservabilityInstaller", Name: "other", UID: "other-uid", Controller: ptr.To(true)},
+				}
+				return tempo
+			}()},
+			wantErr: "cannot adopt TempoStack existing: already controlled by ObservabilityInstaller other",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			instance := &obsv1alpha1.ObservabilityInstaller{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns", UID: "test-uid"},
+				Spec: obsv1alpha1.ObservabilityInstallerSpec{
+					Capabilities: &obsv1alpha1.CapabilitiesSpec{
+						Tracing: &obsv1alpha1.TracingSpec{
+							CommonCapabilitiesSpec: obsv1alpha1.CommonCapabilitiesSpec{Enabled: true},
+							Adopt:                  tt.adopt,
+						},
+					},
+				},
+			}
+			k8sReader := fake.NewClientBuilder().WithScheme(adoptionScheme(t)).WithObjects(tt.objects...).Build()
+
+			adopted, err := getAdoptedInstances(context.Background(), k8sReader, instance)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantTempo, adopted.tempoStack != nil)
+			assert.Equal(t, tt.wantCollector, adopted.otelCollector != nil)
+			assert.Equal(t, tt.wantCollectorSA, adopted.otelCollectorServiceAccount(instance))
+		})
+	}
+}
+
+func TestOwnerReferenceRemover(t *testing.T) {
+	instance := &obsv1alpha1.ObservabilityInstaller{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns", UID: "test-uid"},
+	}
+	tempo := &tempov1alpha1.TempoStack{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "test-ns",
+			OwnerReferences: []metav1.OwnerReference{
+				{Kind: "ObservabilityInstaller", Name: "test", UID: "test-uid", Controller: ptr.To(true)},
+				{Kind: "ConfigMap", Name: "other", UID: "other-uid"},
+			},
+		},
+	}
+	scheme := adoptionScheme(t)
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tempo).Build()
+
+	require.NoError(t, newOwnerReferenceRemover(tempo, instance).Reconcile(context.Background(), k8sClient, scheme, reconciler.EventRecorder{}))
+
+	got := &tempov1alpha1.TempoStack{}
+	require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKeyFromObject(tempo), got))
+	require.Len(t, got.OwnerReferences, 1)
+	assert.Equal(t, "other", got.OwnerReferences[0].Name)
+}
+
+func TestReplacedInstanceRemover(t *testing.T) {
+	instance := &obsv1alpha1.ObservabilityInstaller{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns", UID: "test-uid"},
+	}
+	controlledBy := func(uid string) []metav1.OwnerReference {
+		return []metav1.OwnerReference{{Kind: "ObservabilityInstaller", Name: "test", UID: types.UID(uid), Controller: ptr.To(true)}}
+	}
+
+	tests := []struct {
+		name        string
+		object      client.Object
+		dataBearing bool
+		wantDeleted bool
+		wantLabels  map[string]string
+	}{
+		{
+			name: "controlled collector is deleted",
+			object: &otelv1beta1.OpenTelemetryCollector{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns", OwnerReferences: controlledBy("test-uid")},
+			},
+			wantDeleted: true,
+		},
+		{
+			name: "collector of another owner is kept",
+			object: &otelv1beta1.OpenTelemetryCollector{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns", OwnerReferences: controlledBy("other-uid")},
+			},
+		},
+		{
+			name: "controlled TempoStack is retained",
+			object: &tempov1alpha1.TempoStack{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns", OwnerReferences: controlledBy("test-uid")},
+			},
+			dataBearing: true,
+			wantLabels:  map[string]string{retainedByLabel: "test-uid"},
+		},
+		{
+			name: "TempoStack without owner is kept",
+			object: &tempov1alpha1.TempoStack{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns"},
+			},
+			dataBearing: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := adoptionScheme(t)
+			k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tt.object).Build()
+
+			desired := tt.object.DeepCopyObject().(client.Object)
+			desired.SetOwnerReferences(nil)
+			err := newReplacedInstanceRemover(desired, instance, obsv1alpha1.DeletionPolicyDelete, tt.dataBearing).
+				Reconcile(context.Background(), k8sClient, scheme, reconciler.EventRecorder{})
+			require.NoError(t, err)
+
+			got := tt.object.DeepCopyObject().(client.Object)
+			err = k8sClient.Get(context.Background(), client.ObjectKeyFromObject(tt.object), got)
+			if tt.wantDeleted {
+				require.True(t, apierrors.IsNotFound(err))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantLabels, got.GetLabels())
+		})
+	}
+}
+
+func TestReplacedTempoStackStorageIsRetained(t *testing.T) {
+	instance := &obsv1alpha1.ObservabilityInstaller{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns", UID: "test-uid"},
+		Spec: obsv1alpha1.ObservabilityInstallerSpec{
+			Capabilities: &obsv1alpha1.CapabilitiesSpec{
+				Tracing: &obsv1alpha1.TracingSpec{
+					CommonCapabilitiesSpec: obsv1alpha1.CommonCapabilitiesSpec{Enabled: true},
+					Adopt:                  &obsv1alpha1.TracingAdoptSpec{TempoStack: "existing"},
+				},
+			},
+		},
+	}
+	adopted := &tempov1alpha1.TempoStack{
+		ObjectMeta: metav1.ObjectMeta{Name: "existing", Namespace: "test-ns"},
+		Spec: tempov1alpha1.TempoStackSpec{
+			Template: tempov1alpha1.TempoTemplateSpec{
+				Gateway: tempov1alpha1.TempoGatewaySpec{Enabled: true},
+			},
+			Tenants: &tempov1alpha1.TenantsSpec{
+				Mode: tempov1alpha1.ModeOpenShift,
+				Authentication: []tempov1alpha1.AuthenticationSpec{
+					{TenantName: tenantName, TenantID: tenantID},
+				},
+			},
+		},
+	}
+	controlled := []metav1.OwnerReference{{Kind: "ObservabilityInstaller", Name: "test", UID: "test-uid", Controller: ptr.To(true)}}
+	storageSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: tempoSecretName("test"), Namespace: "test-ns", OwnerReferences: controlled},
+	}
+	caConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: tempoStorageCAConfigMapName("test"), Namespace: "test-ns", OwnerReferences: controlled},
+	}
+	scheme := getScheme()
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(adopted, storageSecret, caConfigMap).Build()
+
+	plan, err := getReconcilePlan(context.Background(), k8sClient, k8sClient, instance, Options{}, operatorsStatus{})
+	require.NoError(t, err)
+
+	var storage []string
+	for _, r := range plan.Reconcilers() {
+		remover, ok := r.(replacedInstanceRemover)
+		if !ok {
+			continue
+		}
+		switch remover.resource.(type) {
+		case *corev1.Secret, *corev1.ConfigMap:
+			storage = append(storage, remover.resource.GetName())
+			require.NoError(t, remover.Reconcile(context.Background(), k8sClient, scheme, reconciler.EventRecorder{}))
+		}
+	}
+	assert.ElementsMatch(t, []string{"coo-test-tempo", "coo-test-tempo-storage-cert", "coo-test-tempo-storage-ca"}, storage)
+
+	// The storage of the replaced TempoStack is released rather than deleted.
+	for _, obj := range []client.Object{storageSecret, caConfigMap} {
+		got := obj.DeepCopyObject().(client.Object)
+		require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKeyFromObject(obj), got))
+		assert.Equal(t, map[string]string{retainedByLabel: "test-uid"}, got.GetLabels())
+		assert.Empty(t, got.GetOwnerReferences())
+	}
+}
+
+func adoptionScheme(t *testing.T) *runtime.Scheme {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	require.NoError(t, tempov1alpha1.AddToScheme(scheme))
+	require.NoError(t, otelv1beta1.AddToScheme(scheme))
+	return scheme
+}
@@ -10,13 +10,16 @@ import (
 	tempov1alpha1 "github.com/grafana/tempo-operator/api/tempo/v1alpha1"
 	otelv1beta1 "github.com/open-telemetry/opentelemetry-operator/apis/v1beta1"
 	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/tools/events"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -28,19 +31,26 @@ import (
 
 	obsv1alpha1 "github.com/rhobs/observability-operator/pkg/apis/observability/v1alpha1"
 	uiv1alpha1 "github.com/rhobs/observability-operator/pkg/apis/uiplugin/v1alpha1"
+	"github.com/rhobs/observability-operator/pkg/reconciler"
 )
 
 const (
 	finalizerName = "observability.openshift.io/observabilityinstaller"
 
-	conditionReasonError    = "ReconcileError"
-	conditionTypeReconciled = "Reconciled"
+	conditionReasonError      = "ReconcileError"
+	conditionReasonReconciled = "Reconciled"
+	conditionTypeReconciled   = "Reconciled"
+
+	conditionTypeOverridesApplied = "OverridesApplied"
 )
 
 // RBAC for the ObservabilityInstaller CRD
 // +kubebuilder:rbac:groups=observability.openshift.io,resources=observabilityinstallers,verbs=get;list;watch;create;update;delete;patch
 // +kubebuilder:rbac:groups=observability.openshift.io,resources=observabilityinstallers/status;observabilityinstallers/finalizers,verbs=get;update;delete;patch
 
+// RBAC for recording events
+// +kubebuilder:rbac:groups=events.k8s.io,resources=events,verbs=create;patch
+
 // RBAC for installing operators
 // +kubebuilder:rbac:groups=operators.coreos.com,resources=subscriptions,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=operators.coreos.com,resources=clusterserviceversions,verbs=get;list;watch;create;update;patch;delete
@@ -58,12 +68,16 @@ const (
 // +kubebuilder:rbac:groups=observability.openshift.io,resources=uiplugins,verbs=get;list;watch;create;update;delete;patch
 // +kubebuilder:rbac:groups=tempo.grafana.com,resources=application,resourceNames=traces,verbs=create
 
+// RBAC for the object storage probe
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;update;patch;delete
+
 type observabilityInstallerController struct {
 	client client.Client
 	// Use the reader to access config maps which are not cached
 	apiReader       client.Reader
 	scheme          *runtime.Scheme
 	logger          logr.Logger
+	recorder        events.EventRecorder
 	Options         Options
 	controller      controller.TypedController[reconcile.Request]
 	cache           cache.Cache
@@ -98,31 +112,44 @@ func (o observabilityInstallerController) Reconcile(ctx context.Context, request
 		}
 	}
 
+	if err := o.storagePreflight(ctx, instance); err != nil {
+		return o.updateStatus(ctx, instance, err), err
+	}
+
 	subs := &olmv1alpha1.SubscriptionList{}
 	// List all subscriptions to figure out if the operators are already installed
 	err = o.apiReader.List(ctx, subs, &client.ListOptions{})
 	if err != nil {
 		return ctrl.Result{}, err
 	}
-	reconcilers, err := getReconcilers(ctx, o.client, o.apiReader, instance, o.Options, operatorsStatus{
+	plan, err := getReconcilePlan(ctx, o.client, o.apiReader, instance, o.Options, operatorsStatus{
 		cooNamespace: o.Options.COONamespace,
 		subs:         subs.Items,
 	})
 	if err != nil {
-		return ctrl.Result{}, err
+		// The storage conditions set by the preflight are reported along
+		// with the error, e.g. when a storage secret is missing.
+		return o.updateStatus(ctx, instance, err), err
 	}
-	for _, reconciler := range reconcilers {
-		reconcileErr := reconciler.Reconcile(ctx, o.client, o.scheme)
-		// handle creation / update errors that can happen due to a stale cache by
-		// retrying after some time.
-		if apierrors.IsAlreadyExists(err) || apierrors.IsConflict(err) {
-			o.logger.V(1).Info("skipping reconcile error", "err", reconcileErr)
-			return ctrl.Result{RequeueAfter: 2 * time.Second}, nil
-		}
-		if reconcileErr != nil {
-			o.logger.Error(reconcileErr, "Failed to reconcile")
-			return o.updateStatus(ctx, instance, err), err
-		}
+	// Failed overrides are reported in the status and don't prevent the
+	// reconciliation of the resources.
+	overridesErr := reconciler.ApplyOverrides(plan.Reconcilers(), instance.Spec.Overrides)
+	if overridesErr != nil {
+		o.logger.Info("failed to apply overrides", "err", overridesErr)
+	}
+	setOverridesCondition(instance, overridesErr)
+	// The resources which don't depend on a failing one are still
+	// reconciled, the failures are reported in the Reconciled condition.
+	reconcileErr := plan.Reconcile(ctx, o.client, o.scheme, reconciler.NewEventRecorder(o.recorder, instance))
+	// handle creation / update errors that can happen due to a stale cache by
+	// retrying after some time.
+	if reconciler.IsStaleCacheError(reconcileErr) {
+		o.logger.V(1).Info("skipping reconcile error", "err", reconcileErr)
+		return ctrl.Result{RequeueAfter: 2 * time.Second}, nil
+	}
+	if reconcileErr != nil {
+		o.logger.Error(reconcileErr, "Failed to reconcile")
+		return o.updateStatus(ctx, instance, reconcileErr), reconcileErr
 	}
 
 	groups, err := o.discoveryClient.ServerGroups()
@@ -147,6 +174,14 @@ func (o observabilityInstallerController) Reconcile(ctx context.Context, request
 		}
 	}
 
+	if instance.ObjectMeta.DeletionTimestamp == nil {
+		retained, err := retainedResources(ctx, o.apiReader, instance)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		setRetainedCondition(instance, retained)
+	}
+
 	// We have a deletion, short circuit and let the deletion happen
 	if instance.ObjectMeta.DeletionTimestamp != nil {
 		if controllerutil.ContainsFinalizer(instance, finalizerName) {
@@ -200,28 +235,28 @@ func (o observabilityInstallerController) getInstance(ctx context.Context, req c
 }
 
 func (o observabilityInstallerController) updateStatus(ctx context.Context, instance *obsv1alpha1.ObservabilityInstaller, reconcileErr error) reconcile.Result {
+	result := ctrl.Result{}
 	if instance.Spec.Capabilities != nil {
 		capabilities := instance.Spec.Capabilities
 		if capabilities.Tracing != nil && capabilities.Tracing.Enabled {
 			otelcol := &otelv1beta1.OpenTelemetryCollector{}
-			err := o.client.Get(ctx, types.NamespacedName{
+			otelErr := o.client.Get(ctx, types.NamespacedName{
 				Namespace: instance.Namespace,
-				Name:      otelCollectorName(instance.Name),
+				Name:      instanceOtelCollectorName(instance),
 			}, otelcol)
-			if err != nil {
-				return ctrl.Result{RequeueAfter: 2 * time.Second}
-			}
 			tempo := &tempov1alpha1.TempoStack{}
-			err = o.client.Get(ctx, types.NamespacedName{
+			tempoErr := o.client.Get(ctx, types.NamespacedName{
 				Namespace: instance.Namespace,
-				Name:      tempoName(instance.Name),
+				Name:      instanceTempoName(instance),
 			}, tempo)
-			if err != nil {
-				return ctrl.Result{RequeueAfter: 2 * time.Second}
+			if otelErr != nil || tempoErr != nil {
+				// The instances are not created yet, the conditions are
+				// still persisted so that preflight failures are visible.
+				result = ctrl.Result{RequeueAfter: 2 * time.Second}
+			} else {
+				instance.Status.Tempo = fmt.Sprintf("%s/%s (%s)", instance.Namespace, instanceTempoName(instance), tempo.Status.TempoVersion)
+				instance.Status.OpenTelemetry = fmt.Sprintf("%s/%s (%s)", instance.Namespace, instanceOtelCollectorName(instance), otelcol.Status.Version)
 			}
-
-			instance.Status.Tempo = fmt.Sprintf("%s/%s (%s)", instance.Namespace, tempoName(instance.Name), tempo.Status.TempoVersion)
-			instance.Status.OpenTelemetry = fmt.Sprintf("%s/%s (%s)", instance.Namespace, otelCollectorName(instance.Name), otelcol.Status.Version)
 		}
 	} else {
 		instance.Status.Tempo = ""
@@ -229,16 +264,21 @@ func (o observabilityInstallerController) updateStatus(ctx context.Context, inst
 	}
 
 	if reconcileErr != nil {
-		instance.Status.Conditions = []metav1.Condition{
-			{
-				Reason:             conditionReasonError,
-				Type:               conditionTypeReconciled,
-				Status:             metav1.ConditionFalse,
-				Message:            reconcileErr.Error(),
-				LastTransitionTime: metav1.Now(),
-				ObservedGeneration: instance.GetGeneration(),
-			},
-		}
+		meta.SetStatusCondition(&instance.Status.Conditions, metav1.Condition{
+			Reason:             conditionReasonError,
+			Type:               conditionTypeReconciled,
+			Status:             metav1.ConditionFalse,
+			Message:            reconcileErr.Error(),
+			ObservedGeneration: instance.GetGeneration(),
+		})
+	} else {
+		meta.SetStatusCondition(&instance.Status.Conditions, metav1.Condition{
+			Reason:             conditionReasonReconciled,
+			Type:               conditionTypeReconciled,
+			Status:             metav1.ConditionTrue,
+			Message:            "All resources are reconciled",
+			ObservedGeneration: instance.GetGeneration(),
+		})
 	}
 
 	err := o.client.Status().Update(ctx, instance)
@@ -247,13 +287,103 @@ func (o observabilityInstallerController) updateStatus(ctx context.Context, inst
 		return ctrl.Result{RequeueAfter: 2 * time.Second}
 	}
 
-	return ctrl.Result{}
+	return result
+}
+
+// setOverridesCondition reports whether the overrides of the instance were
+// applied.
+func setOverridesCondition(instance *obsv1alpha1.ObservabilityInstaller, overridesErr error) {
+	if len(instance.Spec.Overrides) == 0 {
+		meta.RemoveStatusCondition(&instance.Status.Conditions, conditionTypeOverridesApplied)
+		return
+	}
+	if overridesErr != nil {
+		meta.SetStatusCondition(&instance.Status.Conditions, metav1.Condition{
+			Type:               conditionTypeOverridesApplied,
+			Status:             metav1.ConditionFalse,
+			Reason:             reconciler.FailedToApplyOverridesReason,
+			Message:            overridesErr.Error(),
+			ObservedGeneration: instance.GetGeneration(),
+		})
+		return
+	}
+	meta.SetStatusCondition(&instance.Status.Conditions, metav1.Condition{
+		Type:               conditionTypeOverridesApplied,
+		Status:             metav1.ConditionTrue,
+		Reason:             reconciler.OverridesAppliedReason,
+		Message:            reconciler.OverridesAppliedMessage,
+		ObservedGeneration: instance.GetGeneration(),
+	})
+}
+
+// storagePreflight validates the object storage configuration of the tracing
+// capability and records the outcome in the StorageReady condition. When the
+// probe is enabled, the condition reflects the state of the probe Job. An
+// invalid configuration is only reported in the condition so that the other
+// components are still reconciled, errors are returned when the probe Job
+// can't be managed.
+func (o observabilityInstallerController) storagePreflight(ctx context.Context, instance *obsv1alpha1.ObservabilityInstaller) error {
+	tracing := instance.Spec.GetCapabilities().GetTracing()
+	probeJob := storageProbeJob(instance, o.Options.StorageProbeImage)
+
+	if tracing == nil || !tracing.Enabled || !storageProbeEnabled(instance) || instance.DeletionTimestamp != nil {
+		if err := o.client.Delete(ctx, probeJob, client.PropagationPolicy(metav1.DeletePropagationBackground)); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete object storage probe job: %w", err)
+		}
+	}
+	if tracing == nil || !tracing.Enabled || instance.DeletionTimestamp != nil || adoptedTempoStackName(instance) != "" {
+		meta.RemoveStatusCondition(&instance.Status.Conditions, conditionTypeStorageReady)
+		return nil
+	}
+
+	if err := validateObjectStorage(ctx, o.apiReader, instance); err != nil {
+		meta.SetStatusCondition(&instance.Status.Conditions, metav1.Condition{
+			Type:               conditionTypeStorageReady,
+			Status:             metav1.ConditionFalse,
+			Reason:             storageReasonValidationFailed,
+			Message:            err.Error(),
+			ObservedGeneration: instance.GetGeneration(),
+		})
+		return nil
+	}
+
+	cond := metav1.Condition{
+		Type:    conditionTypeStorageReady,
+		Status:  metav1.ConditionTrue,
+		Reason:  storageReasonValidated,
+		Message: "Object storage configuration is valid",
+	}
+	if storageProbeEnabled(instance) {
+		job := &batchv1.Job{}
+		err := o.client.Get(ctx, client.ObjectKeyFromObject(probeJob), job)
+		switch {
+		case apierrors.IsNotFound(err):
+			// The job is created by the reconcilers.
+			cond = storageProbeCondition(probeJob)
+		case err != nil:
+			return fmt.Errorf("failed to get object storage probe job: %w", err)
+		case job.Annotations[storageProbeHashAnnotation] != probeJob.Annotations[storageProbeHashAnnotation]:
+			// The job template is immutable, delete the outdated job and let
+			// the reconcilers create a new one.
+			if err := o.client.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground)); client.IgnoreNotFound(err) != nil {
+				return fmt.Errorf("failed to delete outdated object storage probe job: %w", err)
+			}
+			cond = storageProbeCondition(probeJob)
+		default:
+			cond = storageProbeCondition(job)
+		}
+	}
+	cond.ObservedGeneration = instance.GetGeneration()
+	meta.SetStatusCondition(&instance.Status.Conditions, cond)
+
+	return nil
 }
 
 type Options struct {
 	COONamespace          string
 	OpenTelemetryOperator OperatorInstallConfig
 	TempoOperator         OperatorInstallConfig
+	StorageProbeImage     string
 }
 
 type OperatorInstallConfig struct {
@@ -276,6 +406,7 @@ func RegisterWithManager(mgr ctrl.Manager, opts Options) error {
 		apiReader:       mgr.GetAPIReader(),
 		scheme:          mgr.GetScheme(),
 		logger:          logger,
+		recorder:        mgr.GetEventRecorder("observability-operator"),
 		Options:         opts,
 		watchOTELcol:    &sync.Once{},
 		watchTempo:      &sync.Once{},
@@ -287,6 +418,7 @@ func RegisterWithManager(mgr ctrl.Manager, opts Options) error {
 		For(&obsv1alpha1.ObservabilityInstaller{}).
 		Owns(&olmv1alpha1.Subscription{}).
 		Owns(&corev1.Secret{}).
+		Owns(&batchv1.Job{}).
 		Owns(&corev1.Namespace{}).
 		Owns(&uiv1alpha1.UIPlugin{}).
 		Owns(&rbacv1.ClusterRole{}).
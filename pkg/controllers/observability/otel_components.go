@@ -30,7 +30,7 @@ type templateOptions struct {
 
 func otelCollector(instance *obsv1alpha1.ObservabilityInstaller) (*otelv1beta1.OpenTelemetryCollector, error) {
 	w := bytes.NewBuffer(nil)
-	err := collectorConfigTemplate.Execute(w, templateOptions{Namespace: instance.Namespace, TempoName: tempoName(instance.Name), TempoTenant: tenantName})
+	err := collectorConfigTemplate.Execute(w, templateOptions{Namespace: instance.Namespace, TempoName: instanceTempoName(instance), TempoTenant: tenantName})
 	if err != nil {
 		return nil, err
 	}
@@ -72,7 +72,7 @@ func otelCollectorName(instance string) string {
 	return instance
 }
 
-func otelCollectorComponentsRBAC(instance *obsv1alpha1.ObservabilityInstaller) (*rbacv1.ClusterRole, *rbacv1.ClusterRoleBinding) {
+func otelCollectorComponentsRBAC(instance *obsv1alpha1.ObservabilityInstaller, serviceAccount string) (*rbacv1.ClusterRole, *rbacv1.ClusterRoleBinding) {
 	name := fmt.Sprintf("coo-otelcol-%s-components", instance.Name)
 	role := &rbacv1.ClusterRole{
 		TypeMeta: metav1.TypeMeta{
@@ -112,7 +112,7 @@ func otelCollectorComponentsRBAC(instance *obsv1alpha1.ObservabilityInstaller) (
 		Subjects: []rbacv1.Subject{
 			{
 				Kind:      "ServiceAccount",
-				Name:      otelCollectorName(instance.Name) + "-collector",
+				Name:      serviceAccount,
 				Namespace: instance.Namespace,
 			},
 		},
@@ -120,7 +120,7 @@ func otelCollectorComponentsRBAC(instance *obsv1alpha1.ObservabilityInstaller) (
 	return role, binding
 }
 
-func otelCollectorTempoRBAC(instance *obsv1alpha1.ObservabilityInstaller) (*rbacv1.ClusterRole, *rbacv1.ClusterRoleBinding) {
+func otelCollectorTempoRBAC(instance *obsv1alpha1.ObservabilityInstaller, serviceAccount string) (*rbacv1.ClusterRole, *rbacv1.ClusterRoleBinding) {
 	name := fmt.Sprintf("coo-otelcol-%s-tempo", instance.Name)
 	role := &rbacv1.ClusterRole{
 		TypeMeta: metav1.TypeMeta{
@@ -156,7 +156,7 @@ func otelCollectorTempoRBAC(instance *obsv1alpha1.ObservabilityInstaller) (*rbac
 		Subjects: []rbacv1.Subject{
 			{
 				Kind:      "ServiceAccount",
-				Name:      otelCollectorName(instance.Name) + "-collector",
+				Name:      serviceAccount,
 				Namespace: instance.Namespace,
 			},
 		},
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"strings"
 
+	tempov1alpha1 "github.com/grafana/tempo-operator/api/tempo/v1alpha1"
+	otelv1beta1 "github.com/open-telemetry/opentelemetry-operator/apis/v1beta1"
 	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -41,16 +43,19 @@ func (s *operatorsStatus) cooManages(operatorName string) *olmv1alpha1.Subscript
 	return nil
 }
 
-// getReconcilers returns a list of reconcilers for the ObservabilityInstaller instance.
+// getReconcilePlan returns the reconcile plan for the ObservabilityInstaller instance.
 // The subByName is used to check if the operators are already installed, if not, they will be installed.
 // The csvByName is used to uninstall the operators, the name of the CSV contains the version therefore it must be retrieved from the cluster.
 // The CSV is not deleted when the subscription is deleted, so we need to delete it explicitly.
-func getReconcilers(ctx context.Context, k8sClient client.Client, k8sReader client.Reader, instance *obsv1alpha1.ObservabilityInstaller, opts Options, operatorsStatus operatorsStatus) ([]reconciler.Reconciler, error) {
-	var reconcilers []reconciler.Reconciler
+func getReconcilePlan(ctx context.Context, k8sClient client.Client, k8sReader client.Reader, instance *obsv1alpha1.ObservabilityInstaller, opts Options, operatorsStatus operatorsStatus) (*reconciler.Plan, error) {
+	plan := reconciler.NewPlan()
 	//var otelOperator client.Object
 	//var tempoOperator client.Object
 	var instanceObjects []client.Object
 	installedObjects := map[string]client.Object{}
+	// dataBearingObjects are kept by the Retain deletion policy.
+	dataBearingObjects := map[string]bool{}
+	deletionPolicy := tracingDeletionPolicy(instance)
 
 	// the OTEL and Tempo operators are rolling release, meaning only the latest released versions are supported.
 	// At the moment there are no compatibility issues between the operands of these two operators, so we can
@@ -59,78 +64,124 @@ func getReconcilers(ctx context.Context, k8sClient client.Client, k8sReader clie
 	otelSubs := subscription(opts.OpenTelemetryOperator)
 	tempoSubs := subscription(opts.TempoOperator)
 
+	// adopted instances are neither created nor deleted by the installer
+	adopted := &adoptedInstances{}
+	if tracing := instance.Spec.GetCapabilities().GetTracing(); tracing != nil && tracing.Enabled {
+		a, err := getAdoptedInstances(ctx, k8sReader, instance)
+		switch {
+		case err == nil:
+			adopted = a
+		case instance.ObjectMeta.DeletionTimestamp == nil:
+			return nil, err
+		}
+	}
+	if adopted.tempoStack != nil {
+		plan.Add(newOwnerReferenceRemover(adopted.tempoStack, instance))
+	}
+	if adopted.otelCollector != nil {
+		plan.Add(newOwnerReferenceRemover(adopted.otelCollector, instance))
+	}
+	// The instances previously created by the installer and replaced by
+	// adopted instances with a different name are removed.
 	// instance objects
 	otelCol, err := otelCollector(instance)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create OpenTelemetryCollector: %w", err)
 	}
-	instanceObjects = append(instanceObjects, otelCol)
-	otelcolRBAC, otelcolRBACBinding := otelCollectorComponentsRBAC(instance)
+	switch name := adoptedOtelCollectorName(instance); name {
+	case "":
+		instanceObjects = append(instanceObjects, otelCol)
+	case otelCol.Name:
+		// the adopted collector replaces the one created by the installer
+	default:
+		plan.Add(newReplacedInstanceRemover(otelCol, instance, deletionPolicy, false))
+	}
+	otelcolRBAC, otelcolRBACBinding := otelCollectorComponentsRBAC(instance, adopted.otelCollectorServiceAccount(instance))
 	instanceObjects = append(instanceObjects, otelcolRBAC)
 	instanceObjects = append(instanceObjects, otelcolRBACBinding)
-	instanceObjects = append(instanceObjects, tempoStack(instance))
 
-	secrets, err := tempoStackSecrets(ctx, k8sClient, k8sReader, *instance)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create TempoStack secret: %w", err)
-	}
-	if secrets.objectStorage != nil {
-		instanceObjects = append(instanceObjects, secrets.objectStorage)
-	}
-	if secrets.objectStorageTLSSecret != nil {
-		instanceObjects = append(instanceObjects, secrets.objectStorageTLSSecret)
-	}
-	if secrets.objectStorageCAConfigMap != nil {
-		instanceObjects = append(instanceObjects, secrets.objectStorageCAConfigMap)
+	tempo := tempoStack(instance)
+	switch name := adoptedTempoStackName(instance); name {
+	case "":
+		instanceObjects = append(instanceObjects, tempo)
+		dataBearingObjects[gvkNameIdentifier(tempo)] = true
+
+		secrets, err := tempoStackSecrets(ctx, k8sClient, k8sReader, *instance)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create TempoStack secret: %w", err)
+		}
+		var storageObjects []client.Object
+		if secrets.objectStorage != nil {
+			storageObjects = append(storageObjects, secrets.objectStorage)
+		}
+		if secrets.objectStorageTLSSecret != nil {
+			storageObjects = append(storageObjects, secrets.objectStorageTLSSecret)
+		}
+		if secrets.objectStorageCAConfigMap != nil {
+			storageObjects = append(storageObjects, secrets.objectStorageCAConfigMap)
+		}
+		for _, obj := range storageObjects {
+			instanceObjects = append(instanceObjects, obj)
+			dataBearingObjects[gvkNameIdentifier(obj)] = true
+		}
+	case tempo.Name:
+		// the adopted TempoStack replaces the one created by the installer
+		for _, obj := range tempoStackStorageObjects(instance) {
+			plan.Add(newReplacedInstanceRemover(obj, instance, deletionPolicy, true))
+		}
+	default:
+		plan.Add(newReplacedInstanceRemover(tempo, instance, deletionPolicy, true))
+		for _, obj := range tempoStackStorageObjects(instance) {
+			plan.Add(newReplacedInstanceRemover(obj, instance, deletionPolicy, true))
+		}
 	}
 
-	otelcolTempoRBAC, otelcolTempoRBACBinding := otelCollectorTempoRBAC(instance)
+	otelcolTempoRBAC, otelcolTempoRBACBinding := otelCollectorTempoRBAC(instance, adopted.otelCollectorServiceAccount(instance))
 	instanceObjects = append(instanceObjects, otelcolTempoRBAC)
 	instanceObjects = append(instanceObjects, otelcolTempoRBACBinding)
 	instanceObjects = append(instanceObjects, uiPlugin())
 
 	if instance.ObjectMeta.DeletionTimestamp != nil {
 		for _, obj := range instanceObjects {
-			reconcilers = append(reconcilers, reconciler.NewDeleter(obj))
+			plan.Add(newRemover(obj, instance, deletionPolicy, dataBearingObjects[gvkNameIdentifier(obj)]))
 		}
 		if otelSub := operatorsStatus.cooManages("opentelemetry"); otelSub != nil {
-			reconcilers = append(reconcilers, reconciler.NewDeleter(otelSub))
-			reconcilers = append(reconcilers, reconciler.NewDeleter(
-				&olmv1alpha1.ClusterServiceVersion{
-					ObjectMeta: metav1.ObjectMeta{
-						Name:      otelSub.Status.CurrentCSV,
-						Namespace: otelSub.Namespace,
-					},
-				}))
+			plan.AddAll(subscriptionRemovers(otelSub, instance, deletionPolicy))
 		}
 		if tempoSub := operatorsStatus.cooManages("tempo"); tempoSub != nil {
-			reconcilers = append(reconcilers, reconciler.NewDeleter(tempoSub))
-			reconcilers = append(reconcilers, reconciler.NewDeleter(
-				&olmv1alpha1.ClusterServiceVersion{
-					ObjectMeta: metav1.ObjectMeta{
-						Name:      tempoSub.Status.CurrentCSV,
-						Namespace: tempoSub.Namespace,
-					},
-				}))
+			plan.AddAll(subscriptionRemovers(tempoSub, instance, deletionPolicy))
 		}
-		return reconcilers, nil
+		return plan, nil
 	}
 
+	// The operands are created once the subscriptions installing their
+	// operators are reconciled.
+	var subscriptions []reconciler.Step
+
 	// Install operators and instances
 	if tracing := instance.Spec.GetCapabilities().GetTracing(); tracing != nil && tracing.Enabled {
 		// install operators and instances
 		if operatorsStatus.ShouldInstall("opentelemetry") {
-			reconcilers = append(reconcilers, reconciler.NewCreateUpdateReconciler(otelSubs, instance))
+			subscriptions = append(subscriptions, plan.Add(reconciler.NewCreateUpdateReconciler(otelSubs, instance)))
 			installedObjects[gvkNameIdentifier(otelSubs)] = otelSubs
 		}
 		if operatorsStatus.ShouldInstall("tempo") {
-			reconcilers = append(reconcilers, reconciler.NewCreateUpdateReconciler(tempoSubs, instance))
+			subscriptions = append(subscriptions, plan.Add(reconciler.NewCreateUpdateReconciler(tempoSubs, instance)))
 			installedObjects[gvkNameIdentifier(tempoSubs)] = tempoSubs
 		}
 		for _, obj := range instanceObjects {
-			reconcilers = append(reconcilers, reconciler.NewUpdater(obj, instance))
+			var dependencies []reconciler.Step
+			switch obj.(type) {
+			case *otelv1beta1.OpenTelemetryCollector, *tempov1alpha1.TempoStack:
+				dependencies = subscriptions
+			}
+			plan.Add(reconciler.NewUpdater(obj, instance), dependencies...)
 			installedObjects[gvkNameIdentifier(obj)] = obj
 		}
+		// the probe job is deleted by the storage preflight when it is not needed anymore
+		if storageProbeEnabled(instance) {
+			plan.Add(reconciler.NewUpdater(storageProbeJob(instance, opts.StorageProbeImage), instance))
+		}
 	}
 	// install operators only
 	if tracing := instance.Spec.GetCapabilities().GetTracing(); tracing != nil &&
@@ -138,11 +189,11 @@ func getReconcilers(ctx context.Context, k8sClient client.Client, k8sReader clie
 		(tracing.GetOperators().Install != nil && *tracing.GetOperators().Install) {
 		// install operators only
 		if operatorsStatus.ShouldInstall("opentelemetry") {
-			reconcilers = append(reconcilers, reconciler.NewCreateUpdateReconciler(otelSubs, instance))
+			subscriptions = append(subscriptions, plan.Add(reconciler.NewCreateUpdateReconciler(otelSubs, instance)))
 			installedObjects[gvkNameIdentifier(otelSubs)] = otelSubs
 		}
 		if operatorsStatus.ShouldInstall("tempo") {
-			reconcilers = append(reconcilers, reconciler.NewCreateUpdateReconciler(tempoSubs, instance))
+			subscriptions = append(subscriptions, plan.Add(reconciler.NewCreateUpdateReconciler(tempoSubs, instance)))
 			installedObjects[gvkNameIdentifier(tempoSubs)] = tempoSubs
 		}
 	}
@@ -150,33 +201,37 @@ func getReconcilers(ctx context.Context, k8sClient client.Client, k8sReader clie
 	// Delete not created objects.
 	for _, obj := range instanceObjects {
 		if installedObjects[gvkNameIdentifier(obj)] == nil {
-			reconcilers = append(reconcilers, reconciler.NewDeleter(obj))
+			plan.Add(newRemover(obj, instance, deletionPolicy, dataBearingObjects[gvkNameIdentifier(obj)]))
 		}
 	}
-	// Delete CSV explicitly because it is not deleted when the subscription is deleted.
 	// This handles the uninstall case when the capability is disabled or the operators installation is disabled.
 	if otelSub := operatorsStatus.cooManages("opentelemetry"); otelSub != nil && installedObjects[gvkNameIdentifier(otelSubs)] == nil {
-		reconcilers = append(reconcilers, reconciler.NewDeleter(otelSub))
-		reconcilers = append(reconcilers, reconciler.NewDeleter(
-			&olmv1alpha1.ClusterServiceVersion{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:      otelSub.Status.CurrentCSV,
-					Namespace: otelSub.Namespace,
-				},
-			}))
+		plan.AddAll(subscriptionRemovers(otelSub, instance, deletionPolicy))
 	}
 	if tempoSub := operatorsStatus.cooManages("tempo"); tempoSub != nil && installedObjects[gvkNameIdentifier(tempoSubs)] == nil {
-		reconcilers = append(reconcilers, reconciler.NewDeleter(tempoSub))
-		reconcilers = append(reconcilers, reconciler.NewDeleter(
+		plan.AddAll(subscriptionRemovers(tempoSub, instance, deletionPolicy))
+	}
+
+	return plan, nil
+}
+
+// subscriptionRemovers returns the reconcilers which uninstall an operator
+// according to the deletion policy.
+// The CSV is deleted explicitly because it is not deleted when the subscription is deleted.
+func subscriptionRemovers(sub *olmv1alpha1.Subscription, owner metav1.Object, policy obsv1alpha1.DeletionPolicy) []reconciler.Reconciler {
+	if policy != obsv1alpha1.DeletionPolicyDelete {
+		return []reconciler.Reconciler{newRetainer(sub, owner)}
+	}
+	return []reconciler.Reconciler{
+		reconciler.NewDeleter(sub),
+		reconciler.NewDeleter(
 			&olmv1alpha1.ClusterServiceVersion{
 				ObjectMeta: metav1.ObjectMeta{
-					Name:      tempoSub.Status.CurrentCSV,
-					Namespace: tempoSub.Namespace,
+					Name:      sub.Status.CurrentCSV,
+					Namespace: sub.Namespace,
 				},
-			}))
+			}),
 	}
-
-	return reconcilers, nil
 }
 
 func gvkNameIdentifier(obj client.Object) string {
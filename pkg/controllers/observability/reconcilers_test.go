@@ -21,6 +21,7 @@ import (
 
 	obsv1alpha1 "github.com/rhobs/observability-operator/pkg/apis/observability/v1alpha1"
 	uiv1alpha1 "github.com/rhobs/observability-operator/pkg/apis/uiplugin/v1alpha1"
+	"github.com/rhobs/observability-operator/pkg/reconciler"
 )
 
 func TestGetReconcilers(t *testing.T) {
@@ -234,7 +235,7 @@ func TestGetReconcilers(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			mockClient := test.mockClient()
 
-			reconcilers, err := getReconcilers(context.Background(), mockClient, mockClient, test.instance, Options{
+			plan, err := getReconcilePlan(context.Background(), mockClient, mockClient, test.instance, Options{
 				COONamespace: "operators",
 				OpenTelemetryOperator: OperatorInstallConfig{
 					Namespace:   "operators",
@@ -253,10 +254,8 @@ func TestGetReconcilers(t *testing.T) {
 			})
 			require.NoError(t, err)
 
-			for _, rec := range reconcilers {
-				err := rec.Reconcile(context.Background(), mockClient, getScheme())
-				require.NoError(t, err)
-			}
+			err = plan.Reconcile(context.Background(), mockClient, getScheme(), reconciler.EventRecorder{})
+			require.NoError(t, err)
 		})
 	}
 
@@ -0,0 +1,132 @@
+package observability
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tempov1alpha1 "github.com/grafana/tempo-operator/api/tempo/v1alpha1"
+	otelv1beta1 "github.com/open-telemetry/opentelemetry-operator/apis/v1beta1"
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	obsv1alpha1 "github.com/rhobs/observability-operator/pkg/apis/observability/v1alpha1"
+	uiv1alpha1 "github.com/rhobs/observability-operator/pkg/apis/uiplugin/v1alpha1"
+	"github.com/rhobs/observability-operator/pkg/reconciler"
+)
+
+const (
+	// retainedByLabel is set on the resources kept by the deletion policy.
+	// The value is the UID of the ObservabilityInstaller which created them.
+	retainedByLabel = "observability.openshift.io/retained-by"
+
+	conditionTypeResourcesRetained   = "ResourcesRetained"
+	conditionReasonResourcesRetained = "DeletionPolicy"
+)
+
+// newRetainer releases a resource from the installer instead of deleting it.
+func newRetainer(resource client.Object, owner metav1.Object) reconciler.Reconciler {
+	return ownerReferenceRemover{
+		owner:    owner,
+		resource: resource,
+		labels:   map[string]string{retainedByLabel: string(owner.GetUID())},
+	}
+}
+
+// newRemover returns the reconciler which removes a resource of the tracing
+// capability according to the deletion policy. Data-bearing resources are
+// the TempoStack and the storage secrets.
+func newRemover(resource client.Object, owner metav1.Object, policy obsv1alpha1.DeletionPolicy, dataBearing bool) reconciler.Reconciler {
+	switch {
+	case policy == obsv1alpha1.DeletionPolicyOrphan,
+		policy == obsv1alpha1.DeletionPolicyRetain && dataBearing:
+		return newRetainer(resource, owner)
+	default:
+		return reconciler.NewDeleter(resource)
+	}
+}
+
+func tracingDeletionPolicy(instance *obsv1alpha1.ObservabilityInstaller) obsv1alpha1.DeletionPolicy {
+	if tracing := instance.Spec.GetCapabilities().GetTracing(); tracing != nil {
+		return tracing.GetDeletionPolicy()
+	}
+	return obsv1alpha1.DeletionPolicyDelete
+}
+
+// retainableKinds are the kinds of the resources which newRemover and
+// subscriptionRemovers may retain: the instances of the tracing capability,
+// their storage secrets, RBAC and UI plugin and the operator subscriptions.
+var retainableKinds = []struct {
+	kind       string
+	namespaced bool
+	newList    func() client.ObjectList
+}{
+	{kind: "TempoStack", namespaced: true, newList: func() client.ObjectList { return &tempov1alpha1.TempoStackList{} }},
+	{kind: "OpenTelemetryCollector", namespaced: true, newList: func() client.ObjectList { return &otelv1beta1.OpenTelemetryCollectorList{} }},
+	{kind: "Secret", namespaced: true, newList: func() client.ObjectList { return &corev1.SecretList{} }},
+	{kind: "ConfigMap", namespaced: true, newList: func() client.ObjectList { return &corev1.ConfigMapList{} }},
+	{kind: "ClusterRole", newList: func() client.ObjectList { return &rbacv1.ClusterRoleList{} }},
+	{kind: "ClusterRoleBinding", newList: func() client.ObjectList { return &rbacv1.ClusterRoleBindingList{} }},
+	{kind: "UIPlugin", newList: func() client.ObjectList { return &uiv1alpha1.UIPluginList{} }},
+	{kind: "Subscription", newList: func() client.ObjectList { return &olmv1alpha1.SubscriptionList{} }},
+}
+
+// retainedResources returns the resources kept by the deletion policy which
+// are not managed by the installer anymore.
+func retainedResources(ctx context.Context, k8sReader client.Reader, instance *obsv1alpha1.ObservabilityInstaller) ([]string, error) {
+	var retained []string
+
+	for _, k := range retainableKinds {
+		opts := []client.ListOption{client.MatchingLabels{retainedByLabel: string(instance.UID)}}
+		if k.namespaced {
+			opts = append(opts, client.InNamespace(instance.Namespace))
+		}
+		list := k.newList()
+		if err := k8sReader.List(ctx, list, opts...); err != nil {
+			if meta.IsNoMatchError(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to list retained %s resources: %w", k.kind, err)
+		}
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract retained %s resources: %w", k.kind, err)
+		}
+		for _, item := range items {
+			obj, ok := item.(client.Object)
+			if !ok {
+				continue
+			}
+			if owner := metav1.GetControllerOf(obj); owner != nil && owner.UID == instance.UID {
+				// Managed again after the capability was re-enabled.
+				continue
+			}
+			name := obj.GetName()
+			if obj.GetNamespace() != "" {
+				name = obj.GetNamespace() + "/" + name
+			}
+			retained = append(retained, fmt.Sprintf("%s %s", k.kind, name))
+		}
+	}
+
+	return retained, nil
+}
+
+// setRetainedCondition warns about the resources kept by the deletion policy.
+func setRetainedCondition(instance *obsv1alpha1.ObservabilityInstaller, retained []string) {
+	if len(retained) == 0 {
+		meta.RemoveStatusCondition(&instance.Status.Conditions, conditionTypeResourcesRetained)
+		return
+	}
+	meta.SetStatusCondition(&instance.Status.Conditions, metav1.Condition{
+		Type:               conditionTypeResourcesRetained,
+		Status:             metav1.ConditionTrue,
+		Reason:             conditionReasonResourcesRetained,
+		Message:            fmt.Sprintf("The following resources were retained and must be deleted manually: %s", strings.Join(retained, ", ")),
+		ObservedGeneration: instance.GetGeneration(),
+	})
+}
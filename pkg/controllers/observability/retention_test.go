@@ -0,0 +1,177 @@
+package observability
+
+import (
+	"context"
+	"testing"
+
+	tempov1alpha1 "github.com/grafana/tempo-operator/api/tempo/v1alpha1"
+	otelv1beta1 "github.com/open-telemetry/opentelemetry-operator/apis/v1beta1"
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	obsv1alpha1 "github.com/rhobs/observability-operator/pkg/apis/observability/v1alpha1"
+	"github.com/rhobs/observability-operator/pkg/reconciler"
+)
+
+func TestNewRemover(t *testing.T) {
+	tests := []struct {
+		name        string
+		policy      obsv1alpha1.DeletionPolicy
+		dataBearing bool
+		wantRetain  bool
+	}{
+		{name: "delete data-bearing", policy: obsv1alpha1.DeletionPolicyDelete, dataBearing: true},
+		{name: "delete other", policy: obsv1alpha1.DeletionPolicyDelete},
+		{name: "retain data-bearing", policy: obsv1alpha1.DeletionPolicyRetain, dataBearing: true, wantRetain: true},
+		{name: "retain other", policy: obsv1alpha1.DeletionPolicyRetain},
+		{name: "orphan data-bearing", policy: obsv1alpha1.DeletionPolicyOrphan, dataBearing: true, wantRetain: true},
+		{name: "orphan other", policy: obsv1alpha1.DeletionPolicyOrphan, wantRetain: true},
+	}
+
+	owner := &obsv1alpha1.ObservabilityInstaller{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns"}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newRemover(&corev1.Secret{}, owner, tt.policy, tt.dataBearing)
+			if tt.wantRetain {
+				assert.IsType(t, ownerReferenceRemover{}, r)
+			} else {
+				assert.IsType(t, reconciler.Deleter{}, r)
+			}
+		})
+	}
+}
+
+func TestSubscriptionRemovers(t *testing.T) {
+	owner := &obsv1alpha1.ObservabilityInstaller{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns"}}
+	sub := &olmv1alpha1.Subscription{ObjectMeta: metav1.ObjectMeta{Name: "tempo-product", Namespace: "openshift-tempo-operator"}}
+
+	assert.Len(t, subscriptionRemovers(sub, owner, obsv1alpha1.DeletionPolicyDelete), 2)
+	retain := subscriptionRemovers(sub, owner, obsv1alpha1.DeletionPolicyRetain)
+	require.Len(t, retain, 1)
+	assert.IsType(t, ownerReferenceRemover{}, retain[0])
+}
+
+func TestTracingDeletionPolicy(t *testing.T) {
+	instance := &obsv1alpha1.ObservabilityInstaller{}
+	assert.Equal(t, obsv1alpha1.DeletionPolicyDelete, tracingDeletionPolicy(instance))
+
+	instance.Spec.Capabilities = &obsv1alpha1.CapabilitiesSpec{
+		Tracing: &obsv1alpha1.TracingSpec{
+			CommonCapabilitiesSpec: obsv1alpha1.CommonCapabilitiesSpec{DeletionPolicy: obsv1alpha1.DeletionPolicyRetain},
+		},
+	}
+	assert.Equal(t, obsv1alpha1.DeletionPolicyRetain, tracingDeletionPolicy(instance))
+}
+
+func TestRetainedResources(t *testing.T) {
+	instance := &obsv1alpha1.ObservabilityInstaller{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns", UID: "test-uid"},
+	}
+	tempo := &tempov1alpha1.TempoStack{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "test-ns",
+			OwnerReferences: []metav1.OwnerReference{
+				{Kind: "ObservabilityInstaller", Name: "test", UID: "test-uid", Controller: ptr.To(true)},
+			},
+		},
+	}
+	sub := &olmv1alpha1.Subscription{ObjectMeta: metav1.ObjectMeta{Name: "tempo-product", Namespace: "openshift-tempo-operator"}}
+	managedSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "coo-test-tempo",
+			Namespace: "test-ns",
+			Labels:    map[string]string{retainedByLabel: "test-uid"},
+			OwnerReferences: []metav1.OwnerReference{
+				{Kind: "ObservabilityInstaller", Name: "test", UID: "test-uid", Controller: ptr.To(true)},
+			},
+		},
+	}
+
+	otelcol := &otelv1beta1.OpenTelemetryCollector{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns"}}
+	clusterRole := &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "coo-test-otelcol"}}
+	plugin := uiPlugin()
+
+	scheme := getScheme()
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tempo, sub, managedSecret, otelcol, clusterRole, plugin).Build()
+
+	retained, err := retainedResources(context.Background(), k8sClient, instance)
+	require.NoError(t, err)
+	assert.Empty(t, retained)
+
+	for _, obj := range []client.Object{tempo, sub, otelcol, clusterRole, plugin} {
+		require.NoError(t, newRetainer(obj, instance).Reconcile(context.Background(), k8sClient, scheme, reconciler.EventRecorder{}))
+	}
+
+	got := &tempov1alpha1.TempoStack{}
+	require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKeyFromObject(tempo), got))
+	assert.Empty(t, got.OwnerReferences)
+	assert.Equal(t, "test-uid", got.Labels[retainedByLabel])
+
+	retained, err = retainedResources(context.Background(), k8sClient, instance)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{
+		"TempoStack test-ns/test",
+		"Subscription openshift-tempo-operator/tempo-product",
+		"OpenTelemetryCollector test-ns/test",
+		"ClusterRole coo-test-otelcol",
+		"UIPlugin " + plugin.Name,
+	}, retained)
+
+	setRetainedCondition(instance, retained)
+	cond := meta.FindStatusCondition(instance.Status.Conditions, conditionTypeResourcesRetained)
+	require.NotNil(t, cond)
+	assert.Equal(t, metav1.ConditionTrue, cond.Status)
+
+	setRetainedCondition(instance, nil)
+	assert.Nil(t, meta.FindStatusCondition(instance.Status.Conditions, conditionTypeResourcesRetained))
+}
+
+func TestRetainableKinds(t *testing.T) {
+	instance := &obsv1alpha1.ObservabilityInstaller{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "test",
+			Namespace:         "test-ns",
+			UID:               "test-uid",
+			DeletionTimestamp: ptr.To(metav1.Now()),
+			Finalizers:        []string{finalizerName},
+		},
+		Spec: obsv1alpha1.ObservabilityInstallerSpec{
+			Capabilities: &obsv1alpha1.CapabilitiesSpec{
+				Tracing: &obsv1alpha1.TracingSpec{
+					CommonCapabilitiesSpec: obsv1alpha1.CommonCapabilitiesSpec{
+						Enabled:        true,
+						DeletionPolicy: obsv1alpha1.DeletionPolicyOrphan,
+					},
+				},
+			},
+		},
+	}
+	k8sClient := fake.NewClientBuilder().WithScheme(getScheme()).Build()
+
+	plan, err := getReconcilePlan(context.Background(), k8sClient, k8sClient, instance, Options{}, operatorsStatus{})
+	require.NoError(t, err)
+
+	var kinds []string
+	for _, k := range retainableKinds {
+		kinds = append(kinds, k.kind)
+	}
+	retainers := 0
+	for _, r := range plan.Reconcilers() {
+		retainer, ok := r.(ownerReferenceRemover)
+		if !ok {
+			continue
+		}
+		retainers++
+		assert.Contains(t, kinds, retainer.resource.GetObjectKind().GroupVersionKind().Kind, "retained %s isn't reported", retainer)
+	}
+	assert.NotZero(t, retainers)
+}
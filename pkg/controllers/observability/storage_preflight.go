@@ -0,0 +1,365 @@
+package observability
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"net/url"
+	"strings"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	obsv1alpha1 "github.com/rhobs/observability-operator/pkg/apis/observability/v1alpha1"
+)
+
+const (
+	conditionTypeStorageReady = "StorageReady"
+
+	storageReasonValidated        = "StorageValidated"
+	storageReasonValidationFailed = "StorageValidationFailed"
+	storageReasonProbePending     = "ProbePending"
+	storageReasonProbeSucceeded   = "ProbeSucceeded"
+	storageReasonProbeFailed      = "ProbeFailed"
+
+	// DefaultStorageProbeImage is the image of the object storage probe. It
+	// only needs a shell and curl.
+	DefaultStorageProbeImage = "registry.access.redhat.com/ubi9/ubi-minimal:9.6"
+
+	// storageProbeHashAnnotation holds the hash of the probe pod template.
+	// The Job template is immutable, the Job is recreated when the hash changes.
+	storageProbeHashAnnotation = "observability.openshift.io/storage-probe-hash"
+
+	storageProbeCAMountPath   = "/etc/storage-probe/ca"
+	storageProbeCertMountPath = "/etc/storage-probe/cert"
+)
+
+var tempoTLSVersions = map[string]bool{
+	"VersionTLS10": true,
+	"VersionTLS11": true,
+	"VersionTLS12": true,
+	"VersionTLS13": true,
+}
+
+func storageProbeJobName(name string) string {
+	return fmt.Sprintf("coo-%s-storage-probe", name)
+}
+
+func storageProbeEnabled(instance *obsv1alpha1.ObservabilityInstaller) bool {
+	if adoptedTempoStackName(instance) != "" {
+		// The storage of an adopted TempoStack is not managed by the installer.
+		return false
+	}
+	preflight := instance.Spec.GetCapabilities().GetTracing().GetStorage().GetPreflight()
+	return preflight != nil && preflight.Probe
+}
+
+// validateObjectStorage checks that the Secret and ConfigMap keys referenced
+// by the object storage configuration exist, that the endpoint URL is valid
+// and that the TLS material can be parsed.
+func validateObjectStorage(ctx context.Context, k8sReader client.Reader, instance *obsv1alpha1.ObservabilityInstaller) error {
+	storageSpec := instance.Spec.GetCapabilities().GetTracing().GetStorage().GetObjectStorageSpec()
+	if storageSpec == nil {
+		return nil
+	}
+
+	var errs []error
+	switch {
+	case storageSpec.S3 != nil:
+		if err := validateEndpoint(storageSpec.S3.Endpoint); err != nil {
+			errs = append(errs, err)
+		}
+		if _, err := secretValue(ctx, k8sReader, instance.Namespace, storageSpec.S3.AccessKeySecret); err != nil {
+			errs = append(errs, err)
+		}
+	case storageSpec.Azure != nil:
+		if _, err := secretValue(ctx, k8sReader, instance.Namespace, storageSpec.Azure.AccountKeySecret); err != nil {
+			errs = append(errs, err)
+		}
+	case storageSpec.GCS != nil:
+		if err := validateGCSKey(ctx, k8sReader, instance.Namespace, storageSpec.GCS.KeyJSONSecret); err != nil {
+			errs = append(errs, err)
+		}
+	case storageSpec.GCSWIF != nil:
+		if err := validateGCSKey(ctx, k8sReader, instance.Namespace, storageSpec.GCSWIF.KeyJSONSecret); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if tlsSpec := storageSpec.GetTLS(); tlsSpec != nil {
+		if err := validateStorageTLS(ctx, k8sReader, instance.Namespace, tlsSpec); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func validateEndpoint(endpoint string) error {
+	parsed, err := url.Parse(strings.TrimSpace(endpoint))
+	if err != nil {
+		return fmt.Errorf("invalid endpoint %q: %w", endpoint, err)
+	}
+	if !strings.EqualFold(parsed.Scheme, "http") && !strings.EqualFold(parsed.Scheme, "https") {
+		return fmt.Errorf("invalid endpoint %q: scheme must be http or https", endpoint)
+	}
+	if parsed.Host == "" {
+		return fmt.Errorf("invalid endpoint %q: missing host", endpoint)
+	}
+	return nil
+}
+
+func validateGCSKey(ctx context.Context, k8sReader client.Reader, namespace string, selector obsv1alpha1.SecretKeySelector) error {
+	keyJSON, err := secretValue(ctx, k8sReader, namespace, selector)
+	if err != nil {
+		return err
+	}
+	if !json.Valid(keyJSON) {
+		return fmt.Errorf("key %q in secret %s is not valid JSON", selector.Key, selector.Name)
+	}
+	return nil
+}
+
+func validateStorageTLS(ctx context.Context, k8sReader client.Reader, namespace string, tlsSpec *obsv1alpha1.TLSSpec) error {
+	var errs []error
+
+	if tlsSpec.CAConfigMap != nil {
+		ca, err := configMapValue(ctx, k8sReader, namespace, *tlsSpec.CAConfigMap)
+		if err != nil {
+			errs = append(errs, err)
+		} else if !x509.NewCertPool().AppendCertsFromPEM([]byte(ca)) {
+			errs = append(errs, fmt.Errorf("key %q in configmap %s does not contain a PEM encoded certificate", tlsSpec.CAConfigMap.Key, tlsSpec.CAConfigMap.Name))
+		}
+	}
+
+	if tlsSpec.CertSecret != nil && tlsSpec.KeySecret != nil {
+		cert, certErr := secretValue(ctx, k8sReader, namespace, *tlsSpec.CertSecret)
+		if certErr != nil {
+			errs = append(errs, certErr)
+		}
+		key, keyErr := secretValue(ctx, k8sReader, namespace, *tlsSpec.KeySecret)
+		if keyErr != nil {
+			errs = append(errs, keyErr)
+		}
+		if certErr == nil && keyErr == nil {
+			if _, err := tls.X509KeyPair(cert, key); err != nil {
+				errs = append(errs, fmt.Errorf("invalid client certificate and key: %w", err))
+			}
+		}
+	}
+
+	if tlsSpec.MinVersion != "" && !tempoTLSVersions[tlsSpec.MinVersion] {
+		errs = append(errs, fmt.Errorf("unsupported TLS min version %q", tlsSpec.MinVersion))
+	}
+
+	return errors.Join(errs...)
+}
+
+func secretValue(ctx context.Context, k8sReader client.Reader, namespace string, selector obsv1alpha1.SecretKeySelector) ([]byte, error) {
+	secret := &corev1.Secret{}
+	if err := k8sReader.Get(ctx, client.ObjectKey{Namespace: namespace, Name: selector.Name}, secret); err != nil {
+		return nil, fmt.Errorf("failed to get secret %s: %w", selector.Name, err)
+	}
+	value, ok := secret.Data[selector.Key]
+	if !ok || len(value) == 0 {
+		return nil, fmt.Errorf("key %q not found in secret %s", selector.Key, selector.Name)
+	}
+	return value, nil
+}
+
+func configMapValue(ctx context.Context, k8sReader client.Reader, namespace string, selector obsv1alpha1.ConfigMapKeySelector) (string, error) {
+	configMap := &corev1.ConfigMap{}
+	if err := k8sReader.Get(ctx, client.ObjectKey{Namespace: namespace, Name: selector.Name}, configMap); err != nil {
+		return "", fmt.Errorf("failed to get configmap %s: %w", selector.Name, err)
+	}
+	value, ok := configMap.Data[selector.Key]
+	if !ok || value == "" {
+		return "", fmt.Errorf("key %q not found in configmap %s", selector.Key, selector.Name)
+	}
+	return value, nil
+}
+
+// storageProbeURL returns the URL requested by the probe Job and whether a
+// 404 response means that the bucket does not exist.
+func storageProbeURL(storageSpec *obsv1alpha1.TracingObjectStorageSpec) (string, bool) {
+	switch {
+	case storageSpec == nil:
+		return "", false
+	case storageSpec.S3 != nil:
+		return strings.TrimSuffix(strings.TrimSpace(storageSpec.S3.Endpoint), "/") + "/" + storageSpec.S3.Bucket, true
+	case storageSpec.S3STS != nil:
+		return s3RegionalURL(storageSpec.S3STS.Region, storageSpec.S3STS.Bucket), true
+	case storageSpec.S3CCO != nil:
+		return s3RegionalURL(storageSpec.S3CCO.Region, storageSpec.S3CCO.Bucket), true
+	case storageSpec.Azure != nil:
+		// Azure answers 404 for private containers to anonymous requests,
+		// only the reachability of the account can be checked.
+		return fmt.Sprintf("https://%s.blob.core.windows.net/%s", storageSpec.Azure.AccountName, storageSpec.Azure.Container), false
+	case storageSpec.AzureWIF != nil:
+		return fmt.Sprintf("https://%s.blob.core.windows.net/%s", storageSpec.AzureWIF.AccountName, storageSpec.AzureWIF.Container), false
+	case storageSpec.GCS != nil:
+		return "https://storage.googleapis.com/" + storageSpec.GCS.Bucket, true
+	case storageSpec.GCSWIF != nil:
+		return "https://storage.googleapis.com/" + storageSpec.GCSWIF.Bucket, true
+	}
+	return "", false
+}
+
+func s3RegionalURL(region, bucket string) string {
+	if region == "" {
+		return "https://s3.amazonaws.com/" + bucket
+	}
+	return fmt.Sprintf("https://s3.%s.amazonaws.com/%s", region, bucket)
+}
+
+// storageProbeJob returns a Job which sends an anonymous HEAD request to the
+// object storage. Any HTTP response proves that the endpoint is reachable and
+// that the TLS handshake succeeds; a 404 means that the bucket is missing.
+// The image of the preflight configuration takes precedence over the
+// operator's one.
+func storageProbeJob(instance *obsv1alpha1.ObservabilityInstaller, image string) *batchv1.Job {
+	storageSpec := instance.Spec.GetCapabilities().GetTracing().GetStorage().GetObjectStorageSpec()
+	probeURL, checkBucket := storageProbeURL(storageSpec)
+
+	if preflight := instance.Spec.GetCapabilities().GetTracing().GetStorage().GetPreflight(); preflight != nil && preflight.Image != "" {
+		image = preflight.Image
+	}
+
+	curlArgs := []string{"--silent", "--show-error", "--head", "--max-time", "10", "--output", "/dev/null", "--write-out", "%{http_code}"}
+	var volumes []corev1.Volume
+	var volumeMounts []corev1.VolumeMount
+	if tlsSpec := storageSpec.GetTLS(); tlsSpec != nil {
+		if tlsSpec.CAConfigMap != nil {
+			curlArgs = append(curlArgs, "--cacert", storageProbeCAMountPath+"/service-ca.crt")
+			volumes = append(volumes, corev1.Volume{
+				Name: "ca",
+				VolumeSource: corev1.VolumeSource{
+					ConfigMap: &corev1.ConfigMapVolumeSource{
+						LocalObjectReference: corev1.LocalObjectReference{Name: tempoStorageCAConfigMapName(instance.Name)},
+					},
+				},
+			})
+			volumeMounts = append(volumeMounts, corev1.VolumeMount{Name: "ca", MountPath: storageProbeCAMountPath, ReadOnly: true})
+		}
+		if tlsSpec.CertSecret != nil && tlsSpec.KeySecret != nil {
+			curlArgs = append(curlArgs,
+				"--cert", storageProbeCertMountPath+"/tls.crt",
+				"--key", storageProbeCertMountPath+"/tls.key",
+			)
+			volumes = append(volumes, corev1.Volume{
+				Name: "cert",
+				VolumeSource: corev1.VolumeSource{
+					Secret: &corev1.SecretVolumeSource{SecretName: tempoStorageSecretName(instance.Name)},
+				},
+			})
+			volumeMounts = append(volumeMounts, corev1.VolumeMount{Name: "cert", MountPath: storageProbeCertMountPath, ReadOnly: true})
+		}
+	}
+
+	// The curl arguments, including the URL built from the user's
+	// configuration, are passed as positional parameters of the script and
+	// never interpreted by the shell.
+	curlArgs = append(curlArgs, probeURL)
+	script := `code=$(curl "$@") || exit 1
+echo "HTTP status: ${code}"
+`
+	if checkBucket {
+		script += `[ "${code}" != "404" ] || { echo "bucket not found"; exit 1; }
+`
+	}
+
+	podSpec := corev1.PodSpec{
+		RestartPolicy: corev1.RestartPolicyNever,
+		Containers: []corev1.Container{
+			{
+				Name:         "probe",
+				Image:        image,
+				Command:      append([]string{"/bin/sh", "-c", script, "storage-probe"}, curlArgs...),
+				VolumeMounts: volumeMounts,
+				SecurityContext: &corev1.SecurityContext{
+					AllowPrivilegeEscalation: ptr.To(false),
+					// The user is assigned by the security context
+					// constraints on OpenShift.
+					RunAsNonRoot: ptr.To(true),
+					Capabilities: &corev1.Capabilities{
+						Drop: []corev1.Capability{"ALL"},
+					},
+					SeccompProfile: &corev1.SeccompProfile{
+						Type: corev1.SeccompProfileTypeRuntimeDefault,
+					},
+				},
+			},
+		},
+		Volumes: volumes,
+	}
+
+	return &batchv1.Job{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Job",
+			APIVersion: batchv1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      storageProbeJobName(instance.Name),
+			Namespace: instance.Namespace,
+			Annotations: map[string]string{
+				storageProbeHashAnnotation: podSpecHash(podSpec),
+			},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:          ptr.To(int32(2)),
+			ActiveDeadlineSeconds: ptr.To(int64(120)),
+			// The finished Jobs are garbage collected when the preflight
+			// can't delete them, the reconcilers recreate the Job to probe
+			// the storage again.
+			TTLSecondsAfterFinished: ptr.To(int32(3600)),
+			Template: corev1.PodTemplateSpec{
+				Spec: podSpec,
+			},
+		},
+	}
+}
+
+func podSpecHash(spec corev1.PodSpec) string {
+	b, _ := json.Marshal(spec)
+	h := fnv.New32a()
+	_, _ = h.Write(b)
+	return fmt.Sprintf("%x", h.Sum32())
+}
+
+// storageProbeCondition converts the state of the probe Job into a StorageReady condition.
+func storageProbeCondition(job *batchv1.Job) metav1.Condition {
+	for _, c := range job.Status.Conditions {
+		if c.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch c.Type {
+		case batchv1.JobComplete:
+			return metav1.Condition{
+				Type:    conditionTypeStorageReady,
+				Status:  metav1.ConditionTrue,
+				Reason:  storageReasonProbeSucceeded,
+				Message: "Object storage is reachable",
+			}
+		case batchv1.JobFailed:
+			return metav1.Condition{
+				Type:    conditionTypeStorageReady,
+				Status:  metav1.ConditionFalse,
+				Reason:  storageReasonProbeFailed,
+				Message: fmt.Sprintf("Object storage probe job %s failed: %s", job.Name, c.Message),
+			}
+		}
+	}
+	return metav1.Condition{
+		Type:    conditionTypeStorageReady,
+		Status:  metav1.ConditionUnknown,
+		Reason:  storageReasonProbePending,
+		Message: fmt.Sprintf("Object storage probe job %s has not completed yet", job.Name),
+	}
+}
@@ -0,0 +1,390 @@
+package observability
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	obsv1alpha1 "github.com/rhobs/observability-operator/pkg/apis/observability/v1alpha1"
+)
+
+func TestValidateObjectStorage(t *testing.T) {
+	certPEM, keyPEM := selfSignedCert(t)
+
+	tests := []struct {
+		name        string
+		storage     *obsv1alpha1.TracingObjectStorageSpec
+		objects     []client.Object
+		wantErrMsgs []string
+	}{
+		{
+			name: "no object storage",
+		},
+		{
+			name: "valid S3 storage",
+			storage: &obsv1alpha1.TracingObjectStorageSpec{
+				S3: &obsv1alpha1.S3Spec{
+					Bucket:          "tempo",
+					Endpoint:        "https://minio:9000",
+					AccessKeySecret: obsv1alpha1.SecretKeySelector{Name: "s3", Key: "secret"},
+				},
+			},
+			objects: []client.Object{
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "s3", Namespace: "test-ns"},
+					Data:       map[string][]byte{"secret": []byte("value")},
+				},
+			},
+		},
+		{
+			name: "S3 storage with invalid endpoint and missing secret",
+			storage: &obsv1alpha1.TracingObjectStorageSpec{
+				S3: &obsv1alpha1.S3Spec{
+					Bucket:          "tempo",
+					Endpoint:        "minio:9000",
+					AccessKeySecret: obsv1alpha1.SecretKeySelector{Name: "s3", Key: "secret"},
+				},
+			},
+			wantErrMsgs: []string{
+				`invalid endpoint "minio:9000"`,
+				"failed to get secret s3",
+			},
+		},
+		{
+			name: "Azure storage with missing key",
+			storage: &obsv1alpha1.TracingObjectStorageSpec{
+				Azure: &obsv1alpha1.AzureSpec{
+					Container:        "tempo",
+					AccountName:      "account",
+					AccountKeySecret: obsv1alpha1.SecretKeySelector{Name: "azure", Key: "key"},
+				},
+			},
+			objects: []client.Object{
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "azure", Namespace: "test-ns"},
+					Data:       map[string][]byte{"other": []byte("value")},
+				},
+			},
+			wantErrMsgs: []string{`key "key" not found in secret azure`},
+		},
+		{
+			name: "GCS storage with invalid key.json",
+			storage: &obsv1alpha1.TracingObjectStorageSpec{
+				GCS: &obsv1alpha1.GCSSpec{
+					Bucket:        "tempo",
+					KeyJSONSecret: obsv1alpha1.SecretKeySelector{Name: "gcs", Key: "key.json"},
+				},
+			},
+			objects: []client.Object{
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "gcs", Namespace: "test-ns"},
+					Data:       map[string][]byte{"key.json": []byte("{not json")},
+				},
+			},
+			wantErrMsgs: []string{`key "key.json" in secret gcs is not valid JSON`},
+		},
+		{
+			name: "valid TLS material",
+			storage: &obsv1alpha1.TracingObjectStorageSpec{
+				S3STS: &obsv1alpha1.S3STSpec{Bucket: "tempo", RoleARN: "arn"},
+				TLS: &obsv1alpha1.TLSSpec{
+					CAConfigMap: &obsv1alpha1.ConfigMapKeySelector{Name: "ca", Key: "ca.crt"},
+					CertSecret:  &obsv1alpha1.SecretKeySelector{Name: "tls", Key: "tls.crt"},
+					KeySecret:   &obsv1alpha1.SecretKeySelector{Name: "tls", Key: "tls.key"},
+					MinVersion:  "VersionTLS12",
+				},
+			},
+			objects: []client.Object{
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: "ca", Namespace: "test-ns"},
+					Data:       map[string]string{"ca.crt": string(certPEM)},
+				},
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "tls", Namespace: "test-ns"},
+					Data:       map[string][]byte{"tls.crt": certPEM, "tls.key": keyPEM},
+				},
+			},
+		},
+		{
+			name: "invalid TLS material",
+			storage: &obsv1alpha1.TracingObjectStorageSpec{
+				S3STS: &obsv1alpha1.S3STSpec{Bucket: "tempo", RoleARN: "arn"},
+				TLS: &obsv1alpha1.TLSSpec{
+					CAConfigMap: &obsv1alpha1.ConfigMapKeySelector{Name: "ca", Key: "ca.crt"},
+					CertSecret:  &obsv1alpha1.SecretKeySelector{Name: "tls", Key: "tls.crt"},
+					KeySecret:   &obsv1alpha1.SecretKeySelector{Name: "tls", Key: "tls.key"},
+					MinVersion:  "1.2",
+				},
+			},
+			objects: []client.Object{
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: "ca", Namespace: "test-ns"},
+					Data:       map[string]string{"ca.crt": "garbage"},
+				},
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "tls", Namespace: "test-ns"},
+					Data:       map[string][]byte{"tls.crt": certPEM, "tls.key": []byte("garbage")},
+				},
+			},
+			wantErrMsgs: []string{
+				`key "ca.crt" in configmap ca does not contain a PEM encoded certificate`,
+				"invalid client certificate and key",
+				`unsupported TLS min version "1.2"`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			require.NoError(t, clientgoscheme.AddToScheme(scheme))
+			k8sReader := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tt.objects...).Build()
+
+			err := validateObjectStorage(context.Background(), k8sReader, storageInstance(tt.storage, nil))
+			if len(tt.wantErrMsgs) == 0 {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			for _, msg := range tt.wantErrMsgs {
+				assert.Contains(t, err.Error(), msg)
+			}
+		})
+	}
+}
+
+func TestStorageProbeJob(t *testing.T) {
+	tests := []struct {
+		name       string
+		storage    *obsv1alpha1.TracingObjectStorageSpec
+		preflight  *obsv1alpha1.StoragePreflightSpec
+		wantURL    string
+		wantBucket bool
+		wantImage  string
+		wantMounts int
+	}{
+		{
+			name: "S3 with CA and client certificate",
+			storage: &obsv1alpha1.TracingObjectStorageSpec{
+				S3: &obsv1alpha1.S3Spec{Bucket: "tempo", Endpoint: "https://minio:9000/"},
+				TLS: &obsv1alpha1.TLSSpec{
+					CAConfigMap: &obsv1alpha1.ConfigMapKeySelector{Name: "ca", Key: "ca.crt"},
+					CertSecret:  &obsv1alpha1.SecretKeySelector{Name: "tls", Key: "tls.crt"},
+					KeySecret:   &obsv1alpha1.SecretKeySelector{Name: "tls", Key: "tls.key"},
+				},
+			},
+			preflight:  &obsv1alpha1.StoragePreflightSpec{Probe: true},
+			wantURL:    "https://minio:9000/tempo",
+			wantBucket: true,
+			wantImage:  DefaultStorageProbeImage,
+			wantMounts: 2,
+		},
+		{
+			name: "S3 STS uses the regional endpoint",
+			storage: &obsv1alpha1.TracingObjectStorageSpec{
+				S3STS: &obsv1alpha1.S3STSpec{Bucket: "tempo", Region: "eu-west-1"},
+			},
+			preflight:  &obsv1alpha1.StoragePreflightSpec{Probe: true, Image: "curl:latest"},
+			wantURL:    "https://s3.eu-west-1.amazonaws.com/tempo",
+			wantBucket: true,
+			wantImage:  "curl:latest",
+		},
+		{
+			name: "Azure only checks reachability",
+			storage: &obsv1alpha1.TracingObjectStorageSpec{
+				Azure: &obsv1alpha1.AzureSpec{Container: "tempo", AccountName: "account"},
+			},
+			preflight: &obsv1alpha1.StoragePreflightSpec{Probe: true},
+			wantURL:   "https://account.blob.core.windows.net/tempo",
+			wantImage: DefaultStorageProbeImage,
+		},
+		{
+			name: "S3 bucket with shell characters",
+			storage: &obsv1alpha1.TracingObjectStorageSpec{
+				S3: &obsv1alpha1.S3Spec{Bucket: "tempo'; touch /tmp/pwned; '", Endpoint: "https://minio:9000"},
+			},
+			preflight:  &obsv1alpha1.StoragePreflightSpec{Probe: true},
+			wantURL:    "https://minio:9000/tempo'; touch /tmp/pwned; '",
+			wantBucket: true,
+			wantImage:  DefaultStorageProbeImage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			instance := storageInstance(tt.storage, tt.preflight)
+			require.True(t, storageProbeEnabled(instance))
+
+			job := storageProbeJob(instance, DefaultStorageProbeImage)
+			assert.Equal(t, "coo-test-storage-probe", job.Name)
+			assert.Equal(t, "test-ns", job.Namespace)
+			assert.NotEmpty(t, job.Annotations[storageProbeHashAnnotation])
+
+			container := job.Spec.Template.Spec.Containers[0]
+			assert.Equal(t, tt.wantImage, container.Image)
+			// The user is assigned by the security context constraints.
+			assert.Nil(t, container.SecurityContext.RunAsUser)
+			assert.True(t, *container.SecurityContext.RunAsNonRoot)
+			// The URL is passed as an argument, not in the script.
+			assert.Equal(t, tt.wantURL, container.Command[len(container.Command)-1])
+			assert.NotContains(t, container.Command[2], tt.wantURL)
+			assert.Equal(t, tt.wantBucket, strings.Contains(container.Command[2], "bucket not found"))
+			assert.Len(t, container.VolumeMounts, tt.wantMounts)
+			assert.Len(t, job.Spec.Template.Spec.Volumes, tt.wantMounts)
+			assert.NotNil(t, job.Spec.TTLSecondsAfterFinished)
+		})
+	}
+}
+
+func TestStorageProbeJobHashChanges(t *testing.T) {
+	storage := &obsv1alpha1.TracingObjectStorageSpec{
+		S3: &obsv1alpha1.S3Spec{Bucket: "tempo", Endpoint: "http://minio:9000"},
+	}
+	job := storageProbeJob(storageInstance(storage, &obsv1alpha1.StoragePreflightSpec{Probe: true}), DefaultStorageProbeImage)
+	same := storageProbeJob(storageInstance(storage, &obsv1alpha1.StoragePreflightSpec{Probe: true}), DefaultStorageProbeImage)
+	assert.Equal(t, job.Annotations[storageProbeHashAnnotation], same.Annotations[storageProbeHashAnnotation])
+
+	storage.S3.Bucket = "other"
+	changed := storageProbeJob(storageInstance(storage, &obsv1alpha1.StoragePreflightSpec{Probe: true}), DefaultStorageProbeImage)
+	assert.NotEqual(t, job.Annotations[storageProbeHashAnnotation], changed.Annotations[storageProbeHashAnnotation])
+}
+
+func TestStorageProbeCondition(t *testing.T) {
+	tests := []struct {
+		name       string
+		conditions []batchv1.JobCondition
+		wantStatus metav1.ConditionStatus
+		wantReason string
+	}{
+		{
+			name:       "running",
+			wantStatus: metav1.ConditionUnknown,
+			wantReason: storageReasonProbePending,
+		},
+		{
+			name: "complete",
+			conditions: []batchv1.JobCondition{
+				{Type: batchv1.JobComplete, Status: corev1.ConditionTrue},
+			},
+			wantStatus: metav1.ConditionTrue,
+			wantReason: storageReasonProbeSucceeded,
+		},
+		{
+			name: "failed",
+			conditions: []batchv1.JobCondition{
+				{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Message: "BackoffLimitExceeded"},
+			},
+			wantStatus: metav1.ConditionFalse,
+			wantReason: storageReasonProbeFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := &batchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{Name: "probe"},
+				Status:     batchv1.JobStatus{Conditions: tt.conditions},
+			}
+			cond := storageProbeCondition(job)
+			assert.Equal(t, conditionTypeStorageReady, cond.Type)
+			assert.Equal(t, tt.wantStatus, cond.Status)
+			assert.Equal(t, tt.wantReason, cond.Reason)
+		})
+	}
+}
+
+func TestReconcileReportsMissingStorageSecret(t *testing.T) {
+	instance := storageInstance(&obsv1alpha1.TracingObjectStorageSpec{
+		S3: &obsv1alpha1.S3Spec{
+			Bucket:          "tempo",
+			Endpoint:        "https://minio:9000",
+			AccessKeySecret: obsv1alpha1.SecretKeySelector{Name: "missing", Key: "secret"},
+		},
+	}, nil)
+	k8sClient := fake.NewClientBuilder().
+		WithScheme(getScheme()).
+		WithObjects(instance).
+		WithStatusSubresource(instance).
+		Build()
+	o := observabilityInstallerController{
+		client:    k8sClient,
+		apiReader: k8sClient,
+		scheme:    getScheme(),
+		logger:    logr.Discard(),
+	}
+
+	_, err := o.Reconcile(context.Background(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(instance)})
+	require.ErrorContains(t, err, "failed to get S3 access key secret missing")
+
+	// The conditions are saved along with the error.
+	saved := &obsv1alpha1.ObservabilityInstaller{}
+	require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKeyFromObject(instance), saved))
+	storageReady := meta.FindStatusCondition(saved.Status.Conditions, conditionTypeStorageReady)
+	require.NotNil(t, storageReady)
+	assert.Equal(t, metav1.ConditionFalse, storageReady.Status)
+	assert.Equal(t, storageReasonValidationFailed, storageReady.Reason)
+	assert.Contains(t, storageReady.Message, "failed to get secret missing")
+	reconciled := meta.FindStatusCondition(saved.Status.Conditions, conditionTypeReconciled)
+	require.NotNil(t, reconciled)
+	assert.Equal(t, metav1.ConditionFalse, reconciled.Status)
+}
+
+func storageInstance(storage *obsv1alpha1.TracingObjectStorageSpec, preflight *obsv1alpha1.StoragePreflightSpec) *obsv1alpha1.ObservabilityInstaller {
+	return &obsv1alpha1.ObservabilityInstaller{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns"},
+		Spec: obsv1alpha1.ObservabilityInstallerSpec{
+			Capabilities: &obsv1alpha1.CapabilitiesSpec{
+				Tracing: &obsv1alpha1.TracingSpec{
+					CommonCapabilitiesSpec: obsv1alpha1.CommonCapabilitiesSpec{Enabled: true},
+					Storage: &obsv1alpha1.TracingStorageSpec{
+						ObjectStorageSpec: storage,
+						Preflight:         preflight,
+					},
+				},
+			},
+		},
+	}
+}
+
+func selfSignedCert(t *testing.T) ([]byte, []byte) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "storage"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+		IsCA:         true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
@@ -115,6 +115,33 @@ func s3hasHTTPSEndpoint(storageSpec obsv1alpha1.TracingObjectStorageSpec) bool {
 	return strings.EqualFold(parsed.Scheme, "https")
 }
 
+// tempoStackStorageObjects returns the storage Secrets and ConfigMap which the
+// installer may have created for its TempoStack. They are data-bearing: when
+// the TempoStack is replaced or adopted, they are released along with it
+// since the retained or adopted TempoStack may still reference them.
+func tempoStackStorageObjects(instance *obsv1alpha1.ObservabilityInstaller) []client.Object {
+	secret := func(name string) *corev1.Secret {
+		return &corev1.Secret{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "Secret",
+				APIVersion: corev1.SchemeGroupVersion.String(),
+			},
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: instance.Namespace},
+		}
+	}
+	return []client.Object{
+		secret(tempoSecretName(instance.Name)),
+		secret(tempoStorageSecretName(instance.Name)),
+		&corev1.ConfigMap{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "ConfigMap",
+				APIVersion: corev1.SchemeGroupVersion.String(),
+			},
+			ObjectMeta: metav1.ObjectMeta{Name: tempoStorageCAConfigMapName(instance.Name), Namespace: instance.Namespace},
+		},
+	}
+}
+
 type tempoSecrets struct {
 	objectStorage *corev1.Secret
 
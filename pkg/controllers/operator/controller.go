@@ -25,16 +25,20 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/events"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/rhobs/observability-operator/pkg/reconciler"
 )
 
 type resourceManager struct {
 	k8sClient client.Client
 	scheme    *runtime.Scheme
 	logger    logr.Logger
+	recorder  events.EventRecorder
 	namespace string
 }
 
@@ -46,12 +50,16 @@ type resourceManager struct {
 //+kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles;rolebindings,verbs=list;create;update;patch
 //+kubebuilder:rbac:groups="",resources=services/finalizers,verbs=update;patch
 
+// RBAC for recording events
+//+kubebuilder:rbac:groups=events.k8s.io,resources=events,verbs=create;patch
+
 // RegisterWithManager registers the controller with Manager
 func RegisterWithManager(mgr ctrl.Manager, namespace string) error {
 	rm := &resourceManager{
 		k8sClient: mgr.GetClient(),
 		scheme:    mgr.GetScheme(),
 		logger:    ctrl.Log.WithName(name),
+		recorder:  mgr.GetEventRecorder("observability-operator"),
 		namespace: namespace,
 	}
 	// We only want to trigger a reconciliation when the generation
@@ -86,8 +94,9 @@ func (rm resourceManager) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 	}
 
 	reconcilers := operatorComponentReconcilers(op, rm.namespace)
+	recorder := reconciler.NewEventRecorder(rm.recorder, op)
 	for _, reconciler := range reconcilers {
-		err := reconciler.Reconcile(ctx, rm.k8sClient, rm.scheme)
+		err := reconciler.Reconcile(ctx, rm.k8sClient, rm.scheme, recorder)
 		// handle create / update errors that can happen due to a stale cache by
 		// retrying after some time.
 		if errors.IsAlreadyExists(err) || errors.IsConflict(err) {
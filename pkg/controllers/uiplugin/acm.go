@@ -0,0 +1,206 @@
+package uiplugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	uiv1alpha1 "github.com/rhobs/observability-operator/pkg/apis/uiplugin/v1alpha1"
+)
+
+const (
+	// acmObservabilityNamespace is the namespace in which ACM deploys the
+	// hub components of its MultiClusterObservability.
+	acmObservabilityNamespace = "open-cluster-management-observability"
+
+	acmAlertmanagerService = "alertmanager"
+	acmAlertmanagerPort    = 9095
+	acmQueryProxyService   = "rbac-query-proxy"
+	acmQueryProxyPort      = 8443
+
+	// acmServiceCAConfigMap is the ConfigMap injected by OpenShift in every
+	// namespace with the CA of the serving certificates of the services.
+	acmServiceCAConfigMap = "openshift-service-ca.crt"
+	acmServiceCAKey       = "service-ca.crt"
+
+	// acmCASuffix is the suffix of the ConfigMap holding the CA of the hub
+	// endpoints in the plugin namespace, mounted on acmCAMountPath.
+	acmCASuffix    = "-acm-ca"
+	acmCAMountPath = "/etc/acm-ca"
+
+	servingCertSecretAnnotation = "service.beta.openshift.io/serving-cert-secret-name"
+)
+
+var (
+	multiClusterObservabilityResource = schema.GroupVersionResource{
+		Group:    "observability.open-cluster-management.io",
+		Version:  "v1beta2",
+		Resource: "multiclusterobservabilities",
+	}
+)
+
+// discoversACMEndpoints returns true if the plugin discovers the hub
+// endpoints of ACM.
+func discoversACMEndpoints(plugin *uiv1alpha1.UIPlugin) bool {
+	config := plugin.Spec.Monitoring
+	return config != nil && config.ACM != nil && config.ACM.Enabled && config.ACM.Discover
+}
+
+// resolveACMEndpoints returns the hub endpoints used by the ACM alerting of
+// the plugin and the CA certificates verifying the discovered ones. The URLs
+// missing from the configuration are discovered from the
+// MultiClusterObservability resource when discovery is enabled. The
+// Services and ConfigMaps of the hub are read with k which mustn't be
+// limited to the resources managed by the operator.
+//
+// It returns nil when the ACM alerting isn't enabled. Discovery failures are
+// reported in the returned status rather than as errors since the other
+// features of the plugin don't depend on ACM.
+func resolveACMEndpoints(ctx context.Context, k client.Reader, dk dynamic.Interface, config *uiv1alpha1.AdvancedClusterManagementReference) (*uiv1alpha1.ACMStatus, string) {
+	if config == nil || !config.Enabled {
+		return nil, ""
+	}
+
+	status := &uiv1alpha1.ACMStatus{
+		AlertmanagerURL:  config.Alertmanager.Url,
+		ThanosQuerierURL: config.ThanosQuerier.Url,
+	}
+	if status.AlertmanagerURL != "" && status.ThanosQuerierURL != "" {
+		return status, ""
+	}
+	if !config.Discover {
+		status.Error = "the alertmanager and thanosQuerier URLs are required unless discover is true"
+		return status, ""
+	}
+
+	discovered, caBundle, err := discoverACMEndpoints(ctx, k, dk)
+	if err != nil {
+		status.Error = err.Error()
+		return status, ""
+	}
+	status.MultiClusterObservability = discovered.MultiClusterObservability
+	status.CAConfigMap = discovered.CAConfigMap
+	if status.AlertmanagerURL == "" {
+		status.AlertmanagerURL = discovered.AlertmanagerURL
+	}
+	if status.ThanosQuerierURL == "" {
+		status.ThanosQuerierURL = discovered.ThanosQuerierURL
+	}
+	return status, caBundle
+}
+
+// discoverACMEndpoints derives the hub endpoints from the
+// MultiClusterObservability resource of ACM. The hub Alertmanager and the
+// observatorium query proxy are served with certificates of the OpenShift
+// service CA, the returned CA bundle is read from the service CA ConfigMap of
+// the hub namespace.
+func discoverACMEndpoints(ctx context.Context, k client.Reader, dk dynamic.Interface) (*uiv1alpha1.ACMStatus, string, error) {
+	list, err := dk.Resource(multiClusterObservabilityResource).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return nil, "", errors.New("ACM observability isn't installed: MultiClusterObservability isn't served by the cluster")
+		}
+		return nil, "", fmt.Errorf("failed to list MultiClusterObservability resources: %w", err)
+	}
+	switch len(list.Items) {
+	case 0:
+		return nil, "", errors.New("no MultiClusterObservability found")
+	case 1:
+	default:
+		return nil, "", fmt.Errorf("found %d MultiClusterObservability resources, expected one", len(list.Items))
+	}
+
+	mco := &list.Items[0]
+	if !isMultiClusterObservabilityReady(mco) {
+		return nil, "", fmt.Errorf("MultiClusterObservability %s isn't ready", mco.GetName())
+	}
+
+	status := &uiv1alpha1.ACMStatus{
+		MultiClusterObservability: mco.GetName(),
+	}
+	if status.AlertmanagerURL, err = acmServiceURL(ctx, k, acmAlertmanagerService, acmAlertmanagerPort); err != nil {
+		return nil, "", err
+	}
+	if status.ThanosQuerierURL, err = acmServiceURL(ctx, k, acmQueryProxyService, acmQueryProxyPort); err != nil {
+		return nil, "", err
+	}
+
+	cm := &corev1.ConfigMap{}
+	if err := k.Get(ctx, client.ObjectKey{Name: acmServiceCAConfigMap, Namespace: acmObservabilityNamespace}, cm); err != nil {
+		return nil, "", fmt.Errorf("failed to get the service CA ConfigMap %s/%s: %w", acmObservabilityNamespace, acmServiceCAConfigMap, err)
+	}
+	caBundle := cm.Data[acmServiceCAKey]
+	if caBundle == "" {
+		return nil, "", fmt.Errorf("the service CA ConfigMap %s/%s has no %s key", acmObservabilityNamespace, acmServiceCAConfigMap, acmServiceCAKey)
+	}
+	status.CAConfigMap = acmObservabilityNamespace + "/" + acmServiceCAConfigMap
+
+	return status, caBundle, nil
+}
+
+// isMultiClusterObservabilityReady returns true if the Ready condition of
+// the MultiClusterObservability is true.
+func isMultiClusterObservabilityReady(mco *unstructured.Unstructured) bool {
+	conditions, _, _ := unstructured.NestedSlice(mco.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]any)
+		if !ok {
+			continue
+		}
+		if condition["type"] == "Ready" {
+			return condition["status"] == string(metav1.ConditionTrue)
+		}
+	}
+	return false
+}
+
+// acmServiceURL returns the URL of a hub service of ACM. The port named
+// https is used when the service defines one, defaultPort otherwise.
+func acmServiceURL(ctx context.Context, k client.Reader, name string, defaultPort int32) (string, error) {
+	svc := &corev1.Service{}
+	if err := k.Get(ctx, client.ObjectKey{Name: name, Namespace: acmObservabilityNamespace}, svc); err != nil {
+		return "", fmt.Errorf("failed to get service %s/%s: %w", acmObservabilityNamespace, name, err)
+	}
+
+	if _, found := svc.Annotations[servingCertSecretAnnotation]; !found {
+		return "", fmt.Errorf("service %s/%s isn't served with a certificate of the OpenShift service CA", acmObservabilityNamespace, name)
+	}
+
+	port := defaultPort
+	for _, p := range svc.Spec.Ports {
+		if p.Name == "https" {
+			port = p.Port
+		}
+	}
+
+	return fmt.Sprintf("https://%s.%s.svc:%d", name, acmObservabilityNamespace, port), nil
+}
+
+// newACMCAConfigMap returns the ConfigMap holding the CA certificates of the
+// discovered hub endpoints of ACM. It's mounted in the pods of the plugin
+// whose alerting proxies connect to the hub.
+func newACMCAConfigMap(name, namespace, caBundle string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: corev1.SchemeGroupVersion.String(),
+			Kind:       "ConfigMap",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name + acmCASuffix,
+			Namespace: namespace,
+			Labels:    componentLabels(name),
+		},
+		Data: map[string]string{
+			acmServiceCAKey: caBundle,
+		},
+	}
+}
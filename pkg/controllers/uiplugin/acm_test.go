@@ -0,0 +1,199 @@
+package uiplugin
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	uiv1alpha1 "github.com/rhobs/observability-operator/pkg/apis/uiplugin/v1alpha1"
+)
+
+func newMultiClusterObservability(ready string) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{Object: map[string]any{
+		"status": map[string]any{
+			"conditions": []any{
+				map[string]any{"type": "Ready", "status": ready},
+			},
+		},
+	}}
+	u.SetAPIVersion("observability.open-cluster-management.io/v1beta2")
+	u.SetKind("MultiClusterObservability")
+	u.SetName("observability")
+	return u
+}
+
+func newACMService(name string, port int32) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   acmObservabilityNamespace,
+			Annotations: map[string]string{servingCertSecretAnnotation: name + "-tls"},
+		},
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{
+				{Name: "metrics", Port: 8080},
+				{Name: "https", Port: port},
+			},
+		},
+	}
+}
+
+func newACMServiceCA() *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      acmServiceCAConfigMap,
+			Namespace: acmObservabilityNamespace,
+		},
+		Data: map[string]string{acmServiceCAKey: "service-ca"},
+	}
+}
+
+func TestResolveACMEndpoints(t *testing.T) {
+	newDynamicClient := func(objects ...runtime.Object) *dynamicfake.FakeDynamicClient {
+		return dynamicfake.NewSimpleDynamicClientWithCustomListKinds(
+			runtime.NewScheme(),
+			map[schema.GroupVersionResource]string{multiClusterObservabilityResource: "MultiClusterObservabilityList"},
+			objects...,
+		)
+	}
+	// The Services and the ConfigMap of the hub are read with the typed
+	// client, the MultiClusterObservability with the dynamic one.
+	k := fake.NewClientBuilder().WithObjects(
+		newACMService(acmAlertmanagerService, 9095),
+		newACMService(acmQueryProxyService, 8443),
+		newACMServiceCA(),
+	).Build()
+	hub := []runtime.Object{newMultiClusterObservability("True")}
+
+	for _, tc := range []struct {
+		name     string
+		config   *uiv1alpha1.AdvancedClusterManagementReference
+		objects  []runtime.Object
+		status   *uiv1alpha1.ACMStatus
+		caBundle string
+	}{
+		{
+			name:   "disabled",
+			config: &uiv1alpha1.AdvancedClusterManagementReference{Discover: true},
+		},
+		{
+			name: "configured URLs",
+			config: &uiv1alpha1.AdvancedClusterManagementReference{
+				Enabled:       true,
+				Discover:      true,
+				Alertmanager:  uiv1alpha1.AlertmanagerReference{Url: "https://alertmanager.example.com"},
+				ThanosQuerier: uiv1alpha1.ThanosQuerierReference{Url: "https://thanos.example.com"},
+			},
+			status: &uiv1alpha1.ACMStatus{
+				AlertmanagerURL:  "https://alertmanager.example.com",
+				ThanosQuerierURL: "https://thanos.example.com",
+			},
+		},
+		{
+			name: "missing URL without discovery",
+			config: &uiv1alpha1.AdvancedClusterManagementReference{
+				Enabled:      true,
+				Alertmanager: uiv1alpha1.AlertmanagerReference{Url: "https://alertmanager.example.com"},
+			},
+			objects: hub,
+			status: &uiv1alpha1.ACMStatus{
+				AlertmanagerURL: "https://alertmanager.example.com",
+				Error:           "the alertmanager and thanosQuerier URLs are required unless discover is true",
+			},
+		},
+		{
+			name:    "discovered",
+			config:  &uiv1alpha1.AdvancedClusterManagementReference{Enabled: true, Discover: true},
+			objects: hub,
+			status: &uiv1alpha1.ACMStatus{
+				MultiClusterObservability: "observability",
+				AlertmanagerURL:           "https://alertmanager.open-cluster-management-observability.svc:9095",
+				ThanosQuerierURL:          "https://rbac-query-proxy.open-cluster-management-observability.svc:8443",
+				CAConfigMap:               "open-cluster-management-observability/openshift-service-ca.crt",
+			},
+			caBundle: "service-ca",
+		},
+		{
+			name: "configured URL takes precedence",
+			config: &uiv1alpha1.AdvancedClusterManagementReference{
+				Enabled:       true,
+				Discover:      true,
+				ThanosQuerier: uiv1alpha1.ThanosQuerierReference{Url: "https://thanos.example.com"},
+			},
+			objects: hub,
+			status: &uiv1alpha1.ACMStatus{
+				MultiClusterObservability: "observability",
+				AlertmanagerURL:           "https://alertmanager.open-cluster-management-observability.svc:9095",
+				ThanosQuerierURL:          "https://thanos.example.com",
+				CAConfigMap:               "open-cluster-management-observability/openshift-service-ca.crt",
+			},
+			caBundle: "service-ca",
+		},
+		{
+			name:   "no MultiClusterObservability",
+			config: &uiv1alpha1.AdvancedClusterManagementReference{Enabled: true, Discover: true},
+			status: &uiv1alpha1.ACMStatus{
+				Error: "no MultiClusterObservability found",
+			},
+		},
+		{
+			name:    "MultiClusterObservability not ready",
+			config:  &uiv1alpha1.AdvancedClusterManagementReference{Enabled: true, Discover: true},
+			objects: []runtime.Object{newMultiClusterObservability("False")},
+			status: &uiv1alpha1.ACMStatus{
+				Error: "MultiClusterObservability observability isn't ready",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			status, caBundle := resolveACMEndpoints(context.Background(), k, newDynamicClient(tc.objects...), tc.config)
+			assert.DeepEqual(t, status, tc.status)
+			assert.Equal(t, caBundle, tc.caBundle)
+		})
+	}
+}
+
+func TestCreateMonitoringPluginInfoWithDiscoveredACM(t *testing.T) {
+	acm := &uiv1alpha1.ACMStatus{
+		MultiClusterObservability: "observability",
+		AlertmanagerURL:           "https://alertmanager.open-cluster-management-observability.svc:9095",
+		ThanosQuerierURL:          "https://rbac-query-proxy.open-cluster-management-observability.svc:8443",
+	}
+	plugin := newMonitoringPlugin(&uiv1alpha1.MonitoringConfig{
+		ACM: &uiv1alpha1.AdvancedClusterManagementReference{Enabled: true, Discover: true},
+	})
+
+	info, err := createMonitoringPluginInfo(plugin, "openshift-operators", plugin.Name, "quay.io/monitoring:latest", nil, "v4.19", "", "", acm)
+	assert.NilError(t, err)
+	assert.Equal(t, info.ACMStatus, acm)
+	assert.Assert(t, slices.Contains(info.ExtraArgs, "-alertmanager="+acm.AlertmanagerURL))
+	assert.Assert(t, slices.Contains(info.ExtraArgs, "-thanos-querier="+acm.ThanosQuerierURL))
+}
+
+func TestNewDeploymentWithACMCA(t *testing.T) {
+	info := UIPluginInfo{Name: "monitoring", Image: "quay.io/monitoring:latest", ACMCABundle: "service-ca"}
+	deploy := newDeployment(info, "openshift-operators", nil)
+
+	container := deploy.Spec.Template.Spec.Containers[0]
+	assert.DeepEqual(t, container.Env, []corev1.EnvVar{{Name: "SSL_CERT_DIR", Value: acmCAMountPath}})
+	assert.Equal(t, container.VolumeMounts[len(container.VolumeMounts)-1].MountPath, acmCAMountPath)
+
+	volumes := deploy.Spec.Template.Spec.Volumes
+	assert.Equal(t, volumes[len(volumes)-1].ConfigMap.Name, newACMCAConfigMap(info.Name, "openshift-operators", info.ACMCABundle).Name)
+	assert.Assert(t, deploy.Spec.Template.Annotations[annotationPrefix+"acm-ca-hash"] != "")
+
+	// The CA isn't mounted when the URLs are configured.
+	info.ACMCABundle = ""
+	deploy = newDeployment(info, "openshift-operators", nil)
+	assert.Equal(t, len(deploy.Spec.Template.Spec.Containers[0].Env), 0)
+	assert.Equal(t, deploy.Spec.Template.Annotations[annotationPrefix+"acm-ca-hash"], "")
+}
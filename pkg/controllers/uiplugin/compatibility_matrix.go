@@ -2,11 +2,15 @@ package uiplugin
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
+	"slices"
 	"strings"
 
 	"golang.org/x/mod/semver"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
 
 	uiv1alpha1 "github.com/rhobs/observability-operator/pkg/apis/uiplugin/v1alpha1"
 )
@@ -20,15 +24,20 @@ var (
 	Experimental_SSA    SupportLevel = "Experimental-SSA"
 )
 
+var supportLevels = []SupportLevel{DevPreview, TechPreview, GeneralAvailability, Experimental_SSA}
+
 type CompatibilityEntry struct {
-	PluginType uiv1alpha1.UIPluginType
+	PluginType uiv1alpha1.UIPluginType `json:"pluginType"`
 	// Minimal OpenShift version supporting this plugin (inclusive).
-	MinClusterVersion string
+	MinClusterVersion string `json:"minClusterVersion"`
 	// Maximal OpenShift version supporting this plugin (exclusive).
-	MaxClusterVersion string
-	ImageKey          string
-	SupportLevel      SupportLevel
-	Features          []string
+	MaxClusterVersion string `json:"maxClusterVersion,omitempty"`
+	ImageKey          string `json:"imageKey,omitempty"`
+	// Image overrides the image referenced by ImageKey, e.g. to deploy a
+	// hotfix image without a new operator build.
+	Image        string       `json:"image,omitempty"`
+	SupportLevel SupportLevel `json:"supportLevel"`
+	Features     []string     `json:"features,omitempty"`
 }
 
 type ListFunction func(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error
@@ -179,7 +188,94 @@ var compatibilityMatrix = []CompatibilityEntry{
 	},
 }
 
-func lookupImageAndFeatures(pluginType uiv1alpha1.UIPluginType, clusterVersion string) (CompatibilityEntry, error) {
+// LoadCompatibilityMatrix returns the compatibility matrix read from the
+// YAML file at path, or the built-in matrix when path is empty.
+//
+// Both matrices are validated and their image keys checked against the
+// images known by the operator. Unknown keys are returned as warnings rather
+// than errors since they only prevent the deployment of the plugins using
+// them, which report it in their status.
+func LoadCompatibilityMatrix(path string, images map[string]string) ([]CompatibilityEntry, []string, error) {
+	matrix, source := compatibilityMatrix, "built-in compatibility matrix"
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read the compatibility matrix: %w", err)
+		}
+		matrix, source = nil, "compatibility matrix "+path
+		if err := yaml.UnmarshalStrict(data, &matrix); err != nil {
+			return nil, nil, fmt.Errorf("invalid %s: %w", source, err)
+		}
+	}
+
+	if err := validateCompatibilityMatrix(matrix); err != nil {
+		return nil, nil, fmt.Errorf("invalid %s: %w", source, err)
+	}
+	return matrix, unknownImageKeys(matrix, images), nil
+}
+
+// unknownImageKeys returns a warning for each entry of the matrix whose image
+// key isn't known by the operator.
+func unknownImageKeys(matrix []CompatibilityEntry, images map[string]string) []string {
+	var warnings []string
+	for i, entry := range matrix {
+		if entry.Image != "" {
+			continue
+		}
+		if _, found := images[entry.ImageKey]; !found {
+			warnings = append(warnings, fmt.Sprintf("entry %d: unknown image key %q, the %s plugins can't be deployed on cluster versions from %s", i, entry.ImageKey, entry.PluginType, entry.MinClusterVersion))
+		}
+	}
+	return warnings
+}
+
+// validateCompatibilityMatrix checks that the entries are well-formed and
+// that the version ranges of a plugin type don't overlap.
+func validateCompatibilityMatrix(matrix []CompatibilityEntry) error {
+	if len(matrix) == 0 {
+		return errors.New("no entries defined")
+	}
+
+	ranges := map[uiv1alpha1.UIPluginType][]CompatibilityEntry{}
+	for i, entry := range matrix {
+		if _, found := pluginTypeToConsoleName[entry.PluginType]; !found {
+			return fmt.Errorf("entry %d: unknown plugin type %q", i, entry.PluginType)
+		}
+		if !semver.IsValid(entry.MinClusterVersion) {
+			return fmt.Errorf("entry %d: invalid minClusterVersion %q", i, entry.MinClusterVersion)
+		}
+		if entry.MaxClusterVersion != "" {
+			if !semver.IsValid(entry.MaxClusterVersion) {
+				return fmt.Errorf("entry %d: invalid maxClusterVersion %q", i, entry.MaxClusterVersion)
+			}
+			if semver.Compare(entry.MinClusterVersion, entry.MaxClusterVersion) >= 0 {
+				return fmt.Errorf("entry %d: minClusterVersion %s isn't lower than maxClusterVersion %s", i, entry.MinClusterVersion, entry.MaxClusterVersion)
+			}
+		}
+		if !slices.Contains(supportLevels, entry.SupportLevel) {
+			return fmt.Errorf("entry %d: unknown support level %q", i, entry.SupportLevel)
+		}
+		if entry.Image == "" && entry.ImageKey == "" {
+			return fmt.Errorf("entry %d: imageKey or image is required", i)
+		}
+		ranges[entry.PluginType] = append(ranges[entry.PluginType], entry)
+	}
+
+	for pluginType, entries := range ranges {
+		slices.SortFunc(entries, func(a, b CompatibilityEntry) int {
+			return semver.Compare(a.MinClusterVersion, b.MinClusterVersion)
+		})
+		for i := 1; i < len(entries); i++ {
+			previous, entry := entries[i-1], entries[i]
+			if previous.MaxClusterVersion == "" || semver.Compare(previous.MaxClusterVersion, entry.MinClusterVersion) > 0 {
+				return fmt.Errorf("plugin %q: the range starting at %s overlaps with the range starting at %s", pluginType, entry.MinClusterVersion, previous.MinClusterVersion)
+			}
+		}
+	}
+	return nil
+}
+
+func lookupImageAndFeatures(matrix []CompatibilityEntry, pluginType uiv1alpha1.UIPluginType, clusterVersion string) (CompatibilityEntry, error) {
 	if !strings.HasPrefix(clusterVersion, "v") {
 		clusterVersion = "v" + clusterVersion
 	}
@@ -189,7 +285,7 @@ func lookupImageAndFeatures(pluginType uiv1alpha1.UIPluginType, clusterVersion s
 		return CompatibilityEntry{}, fmt.Errorf("dynamic plugins not supported before 4.11")
 	}
 
-	for _, entry := range compatibilityMatrix {
+	for _, entry := range matrix {
 		if entry.PluginType != pluginType {
 			continue
 		}
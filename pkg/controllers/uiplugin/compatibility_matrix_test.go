@@ -2,6 +2,8 @@ package uiplugin
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"golang.org/x/mod/semver"
@@ -376,7 +378,7 @@ func TestLookupImageAndFeatures(t *testing.T) {
 		},
 	} {
 		t.Run(fmt.Sprintf("%s/%s", tc.pluginType, tc.clusterVersion), func(t *testing.T) {
-			info, err := lookupImageAndFeatures(tc.pluginType, tc.clusterVersion)
+			info, err := lookupImageAndFeatures(compatibilityMatrix, tc.pluginType, tc.clusterVersion)
 
 			if tc.expectedErr != nil {
 				assert.Error(t, err, tc.expectedErr.Error())
@@ -396,3 +398,127 @@ func TestLookupImageAndFeatures(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateCompatibilityMatrix(t *testing.T) {
+	assert.NilError(t, validateCompatibilityMatrix(compatibilityMatrix))
+
+	entry := func(min, max string) CompatibilityEntry {
+		return CompatibilityEntry{
+			PluginType:        uiv1alpha1.TypeLogging,
+			MinClusterVersion: min,
+			MaxClusterVersion: max,
+			ImageKey:          "ui-logging",
+			SupportLevel:      GeneralAvailability,
+		}
+	}
+
+	for _, tc := range []struct {
+		name   string
+		matrix []CompatibilityEntry
+		err    string
+	}{
+		{
+			name:   "contiguous ranges",
+			matrix: []CompatibilityEntry{entry("v4.22", ""), entry("v4.15", "v4.22")},
+		},
+		{
+			name:   "gap between ranges",
+			matrix: []CompatibilityEntry{entry("v4.15", "v4.18"), entry("v4.19", "")},
+		},
+		{
+			name:   "empty",
+			matrix: []CompatibilityEntry{},
+			err:    "no entries defined",
+		},
+		{
+			name:   "overlapping ranges",
+			matrix: []CompatibilityEntry{entry("v4.15", "v4.20"), entry("v4.19", "")},
+			err:    `plugin "Logging": the range starting at v4.19 overlaps with the range starting at v4.15`,
+		},
+		{
+			name:   "two open ranges",
+			matrix: []CompatibilityEntry{entry("v4.15", ""), entry("v4.19", "")},
+			err:    `plugin "Logging": the range starting at v4.19 overlaps with the range starting at v4.15`,
+		},
+		{
+			name:   "invalid version",
+			matrix: []CompatibilityEntry{entry("4.15", "")},
+			err:    `entry 0: invalid minClusterVersion "4.15"`,
+		},
+		{
+			name:   "empty range",
+			matrix: []CompatibilityEntry{entry("v4.15", "v4.15")},
+			err:    "entry 0: minClusterVersion v4.15 isn't lower than maxClusterVersion v4.15",
+		},
+		{
+			name:   "no image",
+			matrix: []CompatibilityEntry{{PluginType: uiv1alpha1.TypeLogging, MinClusterVersion: "v4.15", SupportLevel: GeneralAvailability}},
+			err:    "entry 0: imageKey or image is required",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateCompatibilityMatrix(tc.matrix)
+			if tc.err == "" {
+				assert.NilError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tc.err)
+		})
+	}
+}
+
+func TestLoadCompatibilityMatrix(t *testing.T) {
+	// The built-in matrix is validated and its image keys checked.
+	images := map[string]string{}
+	for _, entry := range compatibilityMatrix {
+		if entry.ImageKey != "" {
+			images[entry.ImageKey] = "quay.io/" + entry.ImageKey
+		}
+	}
+	matrix, warnings, err := LoadCompatibilityMatrix("", images)
+	assert.NilError(t, err)
+	assert.Equal(t, len(warnings), 0)
+	assert.DeepEqual(t, matrix, compatibilityMatrix)
+
+	delete(images, "ui-logging")
+	_, warnings, err = LoadCompatibilityMatrix("", images)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, warnings, []string{
+		`entry 11: unknown image key "ui-logging", the Logging plugins can't be deployed on cluster versions from v4.22`,
+	})
+
+	path := filepath.Join(t.TempDir(), "matrix.yaml")
+	assert.NilError(t, os.WriteFile(path, []byte(`
+- pluginType: Monitoring
+  minClusterVersion: v4.19
+  maxClusterVersion: v4.22
+  imageKey: ui-monitoring-pf6
+  supportLevel: GeneralAvailability
+- pluginType: Monitoring
+  minClusterVersion: v4.22
+  image: quay.io/openshift-observability-ui/monitoring-console-plugin:v1.0.1
+  supportLevel: TechPreview
+`), 0o600))
+
+	matrix, warnings, err = LoadCompatibilityMatrix(path, map[string]string{"ui-monitoring-pf6": "quay.io/monitoring"})
+	assert.NilError(t, err)
+	assert.Equal(t, len(warnings), 0)
+	assert.Equal(t, len(matrix), 2)
+
+	// Unknown image keys don't prevent the matrix from being loaded.
+	matrix, warnings, err = LoadCompatibilityMatrix(path, map[string]string{"ui-monitoring": "quay.io/monitoring"})
+	assert.NilError(t, err)
+	assert.Equal(t, len(matrix), 2)
+	assert.DeepEqual(t, warnings, []string{
+		`entry 0: unknown image key "ui-monitoring-pf6", the Monitoring plugins can't be deployed on cluster versions from v4.19`,
+	})
+
+	info, err := lookupImageAndFeatures(matrix, uiv1alpha1.TypeMonitoring, "v4.23.1")
+	assert.NilError(t, err)
+	assert.Equal(t, info.Image, "quay.io/openshift-observability-ui/monitoring-console-plugin:v1.0.1")
+	assert.Equal(t, info.SupportLevel, TechPreview)
+
+	assert.NilError(t, os.WriteFile(path, []byte("- pluginType: Monitoring\n  minVersion: v4.19\n"), 0o600))
+	_, _, err = LoadCompatibilityMatrix(path, nil)
+	assert.ErrorContains(t, err, `unknown field "minVersion"`)
+}
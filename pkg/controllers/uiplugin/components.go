@@ -1,14 +1,10 @@
 package uiplugin
 
 import (
-	"bytes"
-	"embed"
 	"fmt"
 	"hash/fnv"
-	"io"
 	"sort"
 	"strings"
-	"text/template"
 
 	"github.com/go-logr/logr"
 	osv1 "github.com/openshift/api/console/v1"
@@ -17,6 +13,7 @@ import (
 	"golang.org/x/mod/semver"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -47,9 +44,6 @@ var (
 	}
 
 	hashSeparator = []byte("\n")
-
-	//go:embed config/korrel8r.yaml
-	korrel8rConfigYAMLTmplFile embed.FS
 )
 
 func IsVersionAheadOrEqual(currentVersion, version string) bool {
@@ -65,13 +59,17 @@ func IsVersionAheadOrEqual(currentVersion, version string) bool {
 	return semver.Compare(currentVersion, canonicalMinVersion) >= 0
 }
 
-func pluginComponentReconcilers(plugin *uiv1alpha1.UIPlugin, pluginInfo UIPluginInfo, clusterVersion string, logger logr.Logger) []reconciler.Reconciler {
+// pluginComponentReconcilers returns the reconcilers of the resources of the
+// plugin. An error is returned when a resource can't be rendered rather than
+// leaving it out, which would prune it.
+func pluginComponentReconcilers(plugin *uiv1alpha1.UIPlugin, pluginInfo UIPluginInfo, clusterVersion string, logger logr.Logger) ([]reconciler.Reconciler, error) {
 	namespace := pluginInfo.ResourceNamespace
 
 	components := []reconciler.Reconciler{
 		reconciler.NewUpdater(newServiceAccount(pluginInfo.Name, namespace), plugin),
 		reconciler.NewUpdater(newDeployment(pluginInfo, namespace, plugin.Spec.Deployment), plugin),
 		reconciler.NewUpdater(newService(pluginInfo, namespace), plugin),
+		reconciler.NewOptionalUpdater(newPodDisruptionBudget(pluginInfo.Name, namespace, componentLabels(pluginInfo.Name), plugin.Spec.Deployment), plugin, hasPodDisruptionBudget(plugin.Spec.Deployment)),
 	}
 
 	if IsVersionAheadOrEqual(clusterVersion, "v4.19") {
@@ -90,6 +88,14 @@ func pluginComponentReconcilers(plugin *uiv1alpha1.UIPlugin, pluginInfo UIPlugin
 		components = append(components, reconciler.NewUpdater(newRoleBinding(pluginInfo), plugin))
 	}
 
+	for _, role := range pluginInfo.Roles {
+		components = append(components, reconciler.NewUpdater(role, plugin))
+	}
+
+	for _, roleBinding := range pluginInfo.RoleBindings {
+		components = append(components, reconciler.NewUpdater(roleBinding, plugin))
+	}
+
 	if pluginInfo.ConfigMap != nil {
 		components = append(components, reconciler.NewUpdater(pluginInfo.ConfigMap, plugin))
 	}
@@ -109,74 +115,181 @@ func pluginComponentReconcilers(plugin *uiv1alpha1.UIPlugin, pluginInfo UIPlugin
 	if plugin.Spec.Type == uiv1alpha1.TypeTroubleshootingPanel && pluginInfo.Korrel8rImage != "" {
 		components = append(components, reconciler.NewUpdater(newKorrel8rService(korrel8rName, namespace), plugin))
 		korrel8rCm, err := newKorrel8rConfigMap(korrel8rName, namespace, pluginInfo)
-		if err == nil && korrel8rCm != nil {
-			components = append(components, reconciler.NewUpdater(korrel8rCm, plugin))
-			components = append(components, reconciler.NewUpdater(newKorrel8rDeployment(korrel8rName, namespace, pluginInfo), plugin))
+		if err != nil {
+			return nil, fmt.Errorf("failed to render the korrel8r configuration: %w", err)
+		}
+		var deploymentConfig *uiv1alpha1.DeploymentConfig
+		if plugin.Spec.TroubleshootingPanel != nil && plugin.Spec.TroubleshootingPanel.Korrel8r != nil {
+			deploymentConfig = plugin.Spec.TroubleshootingPanel.Korrel8r.Deployment
+		}
+		korrel8rDeployment := newKorrel8rDeployment(korrel8rName, namespace, pluginInfo)
+		applyDeploymentConfig(korrel8rDeployment, deploymentConfig)
+
+		components = append(components,
+			reconciler.NewUpdater(korrel8rCm, plugin),
+			reconciler.NewUpdater(korrel8rDeployment, plugin),
+			reconciler.NewOptionalUpdater(newPodDisruptionBudget(korrel8rName, namespace, componentLabels(korrel8rName), deploymentConfig), plugin, hasPodDisruptionBudget(deploymentConfig)),
+		)
+	}
+
+	if router := pluginInfo.LokiStackRouter; plugin.Spec.Type == uiv1alpha1.TypeLogging && router != nil {
+		routerCm, err := router.configMap(namespace)
+		if err != nil {
+			return nil, err
 		}
+		components = append(components,
+			reconciler.NewUpdater(routerCm, plugin),
+			reconciler.NewUpdater(router.deployment(namespace, routerCm), plugin),
+			reconciler.NewUpdater(router.service(namespace), plugin),
+		)
 	}
 
 	// Only add monitoring-specific components for monitoring plugins to prevent conflicts
 	// with other plugin types that shouldn't manage these resources
 	if plugin.Spec.Type == uiv1alpha1.TypeMonitoring {
-		monitoringConfig := plugin.Spec.Monitoring
-		serviceAccountName := plugin.Name + serviceAccountSuffix
-		incidentsEnabled := monitoringConfig != nil &&
-			monitoringConfig.Incidents != nil &&
-			monitoringConfig.Incidents.Enabled &&
-			pluginInfo.HealthAnalyzerImage != ""
+		components = append(components, reconciler.NewOptionalUpdater(newACMCAConfigMap(pluginInfo.Name, namespace, pluginInfo.ACMCABundle), plugin, pluginInfo.ACMCABundle != ""))
+		components = append(components, healthAnalyzerComponentReconcilers(plugin, pluginInfo, true)...)
+		components = append(components, persesComponentReconcilers(plugin, pluginInfo, true, logger)...)
+	}
 
-		healthAnalyzerEnabled := monitoringConfig != nil &&
-			monitoringConfig.ClusterHealthAnalyzer != nil &&
-			monitoringConfig.ClusterHealthAnalyzer.Enabled &&
-			pluginInfo.HealthAnalyzerImage != ""
+	return components, nil
+}
 
-		deployHealthAnalyzer := incidentsEnabled || healthAnalyzerEnabled
+// healthAnalyzerComponentReconcilers returns the reconcilers of the cluster
+// health analyzer of the Monitoring plugin. Without OpenShift, there is no
+// platform monitoring to analyze and to scrape the analyzer, nor service CA
+// to issue its serving certificate: the analyzer must target a workload of
+// the operator and serves a self-signed certificate.
+func healthAnalyzerComponentReconcilers(plugin *uiv1alpha1.UIPlugin, pluginInfo UIPluginInfo, openshift bool) []reconciler.Reconciler {
+	monitoringConfig := plugin.Spec.Monitoring
+	namespace := pluginInfo.ResourceNamespace
+	serviceAccountName := plugin.Name + serviceAccountSuffix
+	// The incidents are only shown by the console.
+	incidentsEnabled := openshift &&
+		monitoringConfig != nil &&
+		monitoringConfig.Incidents != nil &&
+		monitoringConfig.Incidents.Enabled &&
+		pluginInfo.HealthAnalyzerImage != ""
+
+	healthAnalyzerEnabled := monitoringConfig != nil &&
+		monitoringConfig.ClusterHealthAnalyzer != nil &&
+		monitoringConfig.ClusterHealthAnalyzer.Enabled &&
+		pluginInfo.HealthAnalyzerImage != ""
+
+	// The analyzer resources are left as they are when its target can't be
+	// resolved rather than falling back to the platform monitoring or
+	// deleting the analyzer.
+	if pluginInfo.HealthAnalyzerTargetErr != nil {
+		return nil
+	}
+
+	analyzePlatform := openshift && !hasHealthAnalyzerTarget(plugin)
+	deployHealthAnalyzer := (incidentsEnabled || healthAnalyzerEnabled) &&
+		(analyzePlatform || pluginInfo.HealthAnalyzerTarget != nil)
+	hasTargetCA := !analyzePlatform && pluginInfo.HealthAnalyzerTarget != nil && pluginInfo.HealthAnalyzerTarget.caBundle != ""
+
+	var healthAnalyzerDeploymentConfig *uiv1alpha1.DeploymentConfig
+	if monitoringConfig != nil && monitoringConfig.ClusterHealthAnalyzer != nil {
+		healthAnalyzerDeploymentConfig = monitoringConfig.ClusterHealthAnalyzer.Deployment
+	}
+	healthAnalyzerDeployment := newHealthAnalyzerDeployment(namespace, serviceAccountName, pluginInfo)
+	if !openshift {
+		removeHealthAnalyzerServingCert(healthAnalyzerDeployment)
+	}
+	applyDeploymentConfig(healthAnalyzerDeployment, healthAnalyzerDeploymentConfig)
+
+	var components []reconciler.Reconciler
+	// On OpenShift, the service account is shared with the plugin.
+	if !openshift {
+		components = append(components, reconciler.NewOptionalUpdater(newServiceAccount(plugin.Name, namespace), plugin, deployHealthAnalyzer))
+	}
+
+	components = append(components,
+		reconciler.NewOptionalUpdater(componentsHealthClusterRole("components-health-view"), plugin, deployHealthAnalyzer),
+		reconciler.NewOptionalUpdater(newClusterRoleBinding(namespace, serviceAccountName, "components-health-view", plugin.Name+"-"+"components-health-view"), plugin, deployHealthAnalyzer),
+		reconciler.NewOptionalUpdater(newComponentHealthConfig(namespace, pluginInfo.HealthAnalyzerComponents), plugin, deployHealthAnalyzer),
+		reconciler.NewOptionalUpdater(newClusterRoleBinding(namespace, serviceAccountName, "system:auth-delegator", serviceAccountName+"-system-auth-delegator"), plugin, deployHealthAnalyzer),
+		reconciler.NewOptionalUpdater(newHealthAnalyzerService(namespace), plugin, deployHealthAnalyzer),
+		reconciler.NewOptionalUpdater(newHealthAnalyzerTargetCA(namespace, pluginInfo.HealthAnalyzerTarget), plugin, deployHealthAnalyzer && hasTargetCA),
+		reconciler.NewOptionalUpdater(healthAnalyzerDeployment, plugin, deployHealthAnalyzer),
+		reconciler.NewOptionalUpdater(newPodDisruptionBudget(name, namespace, healthAnalyzerDeployment.Spec.Selector.MatchLabels, healthAnalyzerDeploymentConfig),
+			plugin, deployHealthAnalyzer && hasPodDisruptionBudget(healthAnalyzerDeploymentConfig)),
+	)
+
+	if !openshift {
+		return components
+	}
+
+	return append(components,
+		// The analyzer only needs access to the platform monitoring when
+		// it doesn't target a workload of the operator.
+		reconciler.NewOptionalUpdater(newClusterRoleBinding(namespace, serviceAccountName, "cluster-monitoring-view", plugin.Name+"cluster-monitoring-view"), plugin, deployHealthAnalyzer && analyzePlatform),
+		reconciler.NewOptionalUpdater(newAlertManagerViewRoleBinding(serviceAccountName, namespace), plugin, deployHealthAnalyzer && analyzePlatform),
+		// The platform monitoring scrapes the metrics of the analyzer.
+		reconciler.NewOptionalUpdater(newHealthAnalyzerPrometheusRole(namespace), plugin, deployHealthAnalyzer),
+		reconciler.NewOptionalUpdater(newHealthAnalyzerPrometheusRoleBinding(namespace), plugin, deployHealthAnalyzer),
+		reconciler.NewOptionalUpdater(newHealthAnalyzerServiceMonitor(namespace), plugin, deployHealthAnalyzer),
+	)
+}
 
-		components = append(components,
-			reconciler.NewOptionalUpdater(componentsHealthClusterRole("components-health-view"), plugin, deployHealthAnalyzer),
-			reconciler.NewOptionalUpdater(newClusterRoleBinding(namespace, serviceAccountName, "components-health-view", plugin.Name+"-"+"components-health-view"), plugin, deployHealthAnalyzer),
-			reconciler.NewOptionalUpdater(newComponentHealthConfig(namespace), plugin, deployHealthAnalyzer),
-		)
+// persesComponentReconcilers returns the reconcilers of the Perses instance
+// of the Monitoring plugin with its datasources and dashboards.
+func persesComponentReconcilers(plugin *uiv1alpha1.UIPlugin, pluginInfo UIPluginInfo, openshift bool, logger logr.Logger) []reconciler.Reconciler {
+	namespace := pluginInfo.ResourceNamespace
+	monitoringConfig := plugin.Spec.Monitoring
+	persesServiceAccountName := "perses" + serviceAccountSuffix
+	persesEnabled := monitoringConfig != nil && monitoringConfig.Perses != nil && monitoringConfig.Perses.Enabled &&
+		pluginInfo.PersesImage != ""
 
-		components = append(components,
-			reconciler.NewOptionalUpdater(newClusterRoleBinding(namespace, serviceAccountName, "cluster-monitoring-view", plugin.Name+"cluster-monitoring-view"), plugin, deployHealthAnalyzer),
-			reconciler.NewOptionalUpdater(newClusterRoleBinding(namespace, serviceAccountName, "system:auth-delegator", serviceAccountName+"-system-auth-delegator"), plugin, deployHealthAnalyzer),
-			reconciler.NewOptionalUpdater(newAlertManagerViewRoleBinding(serviceAccountName, namespace), plugin, deployHealthAnalyzer),
-			reconciler.NewOptionalUpdater(newHealthAnalyzerPrometheusRole(namespace), plugin, deployHealthAnalyzer),
-			reconciler.NewOptionalUpdater(newHealthAnalyzerPrometheusRoleBinding(namespace), plugin, deployHealthAnalyzer),
-			reconciler.NewOptionalUpdater(newHealthAnalyzerService(namespace), plugin, deployHealthAnalyzer),
-			reconciler.NewOptionalUpdater(newHealthAnalyzerDeployment(namespace, serviceAccountName, pluginInfo),
-				plugin, deployHealthAnalyzer),
-			reconciler.NewOptionalUpdater(newHealthAnalyzerServiceMonitor(namespace), plugin, deployHealthAnalyzer),
-		)
+	var persesConfig uiv1alpha1.PersesReference
+	if monitoringConfig != nil && monitoringConfig.Perses != nil {
+		persesConfig = *monitoringConfig.Perses
+	}
 
-		persesServiceAccountName := "perses" + serviceAccountSuffix
-		persesEnabled := monitoringConfig != nil && monitoringConfig.Perses != nil && monitoringConfig.Perses.Enabled
-		components = append(components,
-			reconciler.NewOptionalUpdater(newServiceAccount("perses", namespace), plugin, persesEnabled),
-			reconciler.NewOptionalUpdater(newClusterRoleBinding(namespace, persesServiceAccountName, "system:auth-delegator", persesServiceAccountName+"-system-auth-delegator"), plugin, persesEnabled),
-			reconciler.NewOptionalUpdater(newPersesClusterRole(), plugin, persesEnabled),
-			reconciler.NewOptionalUpdater(newClusterRoleBinding(namespace, persesServiceAccountName, "perses-cr", persesServiceAccountName+"-perses-cr"), plugin, persesEnabled),
-			reconciler.NewOptionalUpdater(newPerses(namespace, pluginInfo.PersesImage), plugin, persesEnabled),
-			reconciler.NewOptionalUpdater(newPrometheusGlobalDatasource(), plugin, persesEnabled),
-			reconciler.NewOptionalUpdater(newAcceleratorsDatasource(namespace), plugin, persesEnabled),
-		)
+	components := []reconciler.Reconciler{
+		reconciler.NewOptionalUpdater(newServiceAccount("perses", namespace), plugin, persesEnabled),
+		reconciler.NewOptionalUpdater(newClusterRoleBinding(namespace, persesServiceAccountName, "system:auth-delegator", persesServiceAccountName+"-system-auth-delegator"), plugin, persesEnabled),
+		reconciler.NewOptionalUpdater(newPersesClusterRole(), plugin, persesEnabled),
+		reconciler.NewOptionalUpdater(newClusterRoleBinding(namespace, persesServiceAccountName, "perses-cr", persesServiceAccountName+"-perses-cr"), plugin, persesEnabled),
+		reconciler.NewOptionalUpdater(newPersesSQLConfigMap(namespace, persesConfig.Storage),
+			plugin, persesEnabled && persesConfig.Storage != nil && persesConfig.Storage.SQL != nil),
+		reconciler.NewOptionalUpdater(newPerses(namespace, pluginInfo.PersesImage, persesConfig, openshift), plugin, persesEnabled),
+		reconciler.NewOptionalUpdater(newPodDisruptionBudget(persesServiceName, namespace, persesPodSelector, persesConfig.Deployment),
+			plugin, persesEnabled && hasPodDisruptionBudget(persesConfig.Deployment)),
+		persesIngressReconciler(plugin, namespace, persesConfig.Ingress, persesEnabled),
+		reconciler.NewOptionalUpdater(newPrometheusGlobalDatasource(persesConfig.PrometheusURL, persesConfig.DatasourceProxy), plugin, persesEnabled),
+		reconciler.NewOptionalUpdater(newAcceleratorsDatasource(namespace), plugin, persesEnabled),
+	}
+
+	acceleratorsDashboard, err := newAcceleratorsDashboard(namespace)
+	if err != nil {
+		logger.Error(err, "Cannot build Accelerators dashboard")
+	} else {
+		components = append(components, reconciler.NewOptionalUpdater(acceleratorsDashboard, plugin, persesEnabled))
+	}
 
-		acceleratorsDashboard, err := newAcceleratorsDashboard(namespace)
-		if err != nil {
-			logger.Error(err, "Cannot build Accelerators dashboard")
-		} else {
-			components = append(components, reconciler.NewOptionalUpdater(acceleratorsDashboard, plugin, persesEnabled))
-		}
+	apmDashboard, err := newAPMDashboard(namespace)
+	if err != nil {
+		logger.Error(err, "Cannot build APM dashboard")
+	} else {
+		components = append(components, reconciler.NewOptionalUpdater(apmDashboard, plugin, persesEnabled))
+	}
 
-		apmDashboard, err := newAPMDashboard(namespace)
-		if err != nil {
-			logger.Error(err, "Cannot build APM dashboard")
-		} else {
-			components = append(components, reconciler.NewOptionalUpdater(apmDashboard, plugin, persesEnabled))
+	// The resources of the definitions which aren't loaded anymore are
+	// pruned by the inventory.
+	if persesEnabled && pluginInfo.PersesDefinitions != nil {
+		for _, datasource := range pluginInfo.PersesDefinitions.datasources {
+			components = append(components, reconciler.NewUpdater(datasource, plugin))
+		}
+		for _, dashboard := range pluginInfo.PersesDefinitions.dashboards {
+			components = append(components, reconciler.NewUpdater(dashboard, plugin))
 		}
 	}
 
+	if persesEnabled && pluginInfo.OperatorWorkloads != nil {
+		components = append(components, operatorWorkloadsReconcilers(plugin, pluginInfo.OperatorWorkloads, pluginInfo.PersesDefinitions, logger)...)
+	}
+
 	return components
 }
 
@@ -373,6 +486,29 @@ func newDeployment(info UIPluginInfo, namespace string, config *uiv1alpha1.Deplo
 		})
 	}
 
+	var env []corev1.EnvVar
+	if info.ACMCABundle != "" {
+		// The alerting proxies verify the discovered hub endpoints of ACM
+		// with their CA in addition to the system CAs.
+		podAnnotations[annotationPrefix+"acm-ca-hash"] = computeConfigMapHash(newACMCAConfigMap(info.Name, namespace, info.ACMCABundle))
+		volumes = append(volumes, corev1.Volume{
+			Name: "acm-ca",
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: info.Name + acmCASuffix,
+					},
+				},
+			},
+		})
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      "acm-ca",
+			ReadOnly:  true,
+			MountPath: acmCAMountPath,
+		})
+		env = append(env, corev1.EnvVar{Name: "SSL_CERT_DIR", Value: acmCAMountPath})
+	}
+
 	nodeSelector, tolerations := createNodeSelectorAndTolerations(config)
 
 	plugin := &appsv1.Deployment{
@@ -421,6 +557,7 @@ func newDeployment(info UIPluginInfo, namespace string, config *uiv1alpha1.Deplo
 								},
 							},
 							VolumeMounts: volumeMounts,
+							Env:          env,
 							Args:         pluginArgs,
 						},
 					},
@@ -441,9 +578,75 @@ func newDeployment(info UIPluginInfo, namespace string, config *uiv1alpha1.Deplo
 		},
 	}
 
+	applyDeploymentConfig(plugin, config)
+
 	return plugin
 }
 
+// applyDeploymentConfig customizes the pods of a deployment. The first
+// container of the pods is the main container.
+func applyDeploymentConfig(deployment *appsv1.Deployment, config *uiv1alpha1.DeploymentConfig) {
+	if config == nil {
+		return
+	}
+
+	if config.Replicas != nil {
+		deployment.Spec.Replicas = ptr.To(*config.Replicas)
+	}
+
+	pod := &deployment.Spec.Template.Spec
+	if config.NodeSelector != nil {
+		pod.NodeSelector = config.NodeSelector
+	}
+	if len(config.Tolerations) > 0 {
+		pod.Tolerations = config.Tolerations
+	}
+	pod.Affinity = config.Affinity
+	pod.PriorityClassName = config.PriorityClassName
+
+	container := &pod.Containers[0]
+	if config.Resources != nil {
+		container.Resources = *config.Resources
+	}
+	container.Env = append(container.Env, config.Env...)
+}
+
+func hasPodDisruptionBudget(config *uiv1alpha1.DeploymentConfig) bool {
+	return config != nil && config.PodDisruptionBudget != nil
+}
+
+// newPodDisruptionBudget returns the PodDisruptionBudget of the pods
+// matching the selector. Without configuration, at most one pod can be
+// unavailable.
+func newPodDisruptionBudget(name, namespace string, selector map[string]string, config *uiv1alpha1.DeploymentConfig) *policyv1.PodDisruptionBudget {
+	pdb := &policyv1.PodDisruptionBudget{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: policyv1.SchemeGroupVersion.String(),
+			Kind:       "PodDisruptionBudget",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    componentLabels(name),
+		},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: selector,
+			},
+		},
+	}
+
+	if hasPodDisruptionBudget(config) {
+		pdb.Spec.MinAvailable = config.PodDisruptionBudget.MinAvailable
+		pdb.Spec.MaxUnavailable = config.PodDisruptionBudget.MaxUnavailable
+	}
+	if pdb.Spec.MinAvailable == nil && pdb.Spec.MaxUnavailable == nil {
+		pdb.Spec.MaxUnavailable = ptr.To(intstr.FromInt32(1))
+	}
+
+	return pdb
+}
+
 func computeConfigMapHash(cm *corev1.ConfigMap) string {
 	keys := make([]string, 0, len(cm.Data))
 	for k := range cm.Data {
@@ -591,6 +794,11 @@ func newKorrel8rDeployment(name string, namespace string, info UIPluginInfo) *ap
 		MountPath: Korrel8rConfigMountDir,
 	})
 
+	if info.Korrel8r != nil {
+		volumes = append(volumes, info.Korrel8r.volumes...)
+		volumeMounts = append(volumeMounts, info.Korrel8r.volumeMounts...)
+	}
+
 	deploy := &appsv1.Deployment{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: appsv1.SchemeGroupVersion.String(),
@@ -645,6 +853,11 @@ func newKorrel8rDeployment(name string, namespace string, info UIPluginInfo) *ap
 			},
 		},
 	}
+	if info.Korrel8r != nil && info.Korrel8r.configMapsHash != "" {
+		deploy.Spec.Template.Annotations = map[string]string{
+			annotationPrefix + "korrel8r-configmaps-hash": info.Korrel8r.configMapsHash,
+		}
+	}
 	return deploy
 }
 
@@ -680,36 +893,15 @@ func newKorrel8rService(name string, namespace string) *corev1.Service {
 }
 
 func newKorrel8rConfigMap(name string, namespace string, info UIPluginInfo) (*corev1.ConfigMap, error) {
-	korrel8rData := map[string]string{
-		"Metric":      "thanos-querier",
-		"MetricAlert": "alertmanager-main",
-		"Log":         "logging-loki-gateway-http",
-		"Netflow":     "loki-gateway-http", "Trace": "tempo-platform-gateway",
-		"MonitoringNs": reconciler.OpenshiftMonitoringNamespace,
-		"LoggingNs":    OpenshiftLoggingNs,
-		"NetobservNs":  OpenshiftNetobservNs,
-		"TracingNs":    OpenshiftTracingNs,
+	if info.Korrel8r == nil {
+		return nil, fmt.Errorf("no korrel8r configuration resolved for plugin %s", info.Name)
 	}
 
-	if info.LokiServiceNames[OpenshiftLoggingNs] != "" {
-		korrel8rData["Log"] = info.LokiServiceNames[OpenshiftLoggingNs]
-	}
-	if info.LokiServiceNames[OpenshiftNetobservNs] != "" {
-		korrel8rData["Netflow"] = info.LokiServiceNames[OpenshiftNetobservNs]
-	}
-	if info.TempoServiceNames[OpenshiftTracingNs] != "" {
-		korrel8rData["Trace"] = info.TempoServiceNames[OpenshiftTracingNs]
-	}
-
-	var korrel8rConfigYAMLTmpl = template.Must(template.ParseFS(korrel8rConfigYAMLTmplFile, "config/korrel8r.yaml"))
-	w := bytes.NewBuffer(nil)
-	err := korrel8rConfigYAMLTmpl.Execute(w, korrel8rData)
+	cfg, err := marshalKorrel8rConfig(info.Korrel8r.config)
 	if err != nil {
 		return nil, err
 	}
 
-	cfg, _ := io.ReadAll(w)
-
 	return &corev1.ConfigMap{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "ConfigMap",
@@ -721,7 +913,7 @@ func newKorrel8rConfigMap(name string, namespace string, info UIPluginInfo) (*co
 			Labels:    componentLabels(name),
 		},
 		Data: map[string]string{
-			Korrel8rConfigFileName: string(cfg),
+			Korrel8rConfigFileName: cfg,
 		},
 	}, nil
 }
@@ -3,7 +3,14 @@ package uiplugin
 import (
 	"testing"
 
+	"github.com/go-logr/logr"
 	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+
+	uiv1alpha1 "github.com/rhobs/observability-operator/pkg/apis/uiplugin/v1alpha1"
 )
 
 func TestIsVersionAheadOrEqual(t *testing.T) {
@@ -249,3 +256,59 @@ func indexOfArg(args []string, target string) int {
 	}
 	return -1
 }
+
+func TestApplyDeploymentConfig(t *testing.T) {
+	info := UIPluginInfo{Name: "monitoring", Image: "quay.io/monitoring:latest"}
+
+	deployment := newDeployment(info, "openshift-operators", nil)
+	assert.Equal(t, *deployment.Spec.Replicas, int32(1))
+	assert.Assert(t, deployment.Spec.Template.Spec.Affinity == nil)
+
+	resources := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("64Mi")},
+	}
+	deployment = newDeployment(info, "openshift-operators", &uiv1alpha1.DeploymentConfig{
+		Replicas:          ptr.To(int32(2)),
+		Resources:         &resources,
+		PriorityClassName: "system-cluster-critical",
+		Env:               []corev1.EnvVar{{Name: "LOG_LEVEL", Value: "debug"}},
+		NodeSelector:      map[string]string{"node-role.kubernetes.io/infra": ""},
+	})
+	pod := deployment.Spec.Template.Spec
+	assert.Equal(t, *deployment.Spec.Replicas, int32(2))
+	assert.Equal(t, pod.PriorityClassName, "system-cluster-critical")
+	assert.DeepEqual(t, pod.NodeSelector, map[string]string{"node-role.kubernetes.io/infra": ""})
+	assert.DeepEqual(t, pod.Containers[0].Resources, resources)
+	assert.DeepEqual(t, pod.Containers[0].Env, []corev1.EnvVar{{Name: "LOG_LEVEL", Value: "debug"}})
+}
+
+func TestNewPodDisruptionBudget(t *testing.T) {
+	selector := componentLabels("monitoring")
+
+	pdb := newPodDisruptionBudget("monitoring", "openshift-operators", selector, &uiv1alpha1.DeploymentConfig{
+		PodDisruptionBudget: &uiv1alpha1.PodDisruptionBudgetConfig{},
+	})
+	assert.DeepEqual(t, pdb.Spec.Selector.MatchLabels, selector)
+	assert.Assert(t, pdb.Spec.MinAvailable == nil)
+	assert.DeepEqual(t, *pdb.Spec.MaxUnavailable, intstr.FromInt32(1))
+
+	pdb = newPodDisruptionBudget("monitoring", "openshift-operators", selector, &uiv1alpha1.DeploymentConfig{
+		PodDisruptionBudget: &uiv1alpha1.PodDisruptionBudgetConfig{MinAvailable: ptr.To(intstr.FromString("50%"))},
+	})
+	assert.DeepEqual(t, *pdb.Spec.MinAvailable, intstr.FromString("50%"))
+	assert.Assert(t, pdb.Spec.MaxUnavailable == nil)
+}
+
+func TestPluginComponentReconcilersRenderError(t *testing.T) {
+	plugin := newTroubleshootingPanelPlugin(nil)
+	info := UIPluginInfo{
+		Name:              plugin.Name,
+		Image:             "quay.io/tp-test:latest",
+		ResourceNamespace: "openshift-operators",
+		Korrel8rImage:     "quay.io/korrel8r:latest",
+	}
+
+	// The korrel8r resources aren't left out, which would prune them.
+	_, err := pluginComponentReconcilers(plugin, info, "v4.19", logr.Discard())
+	assert.ErrorContains(t, err, "failed to render the korrel8r configuration")
+}
@@ -2,6 +2,7 @@ package uiplugin
 
 import (
 	"context"
+	"fmt"
 	"slices"
 	"time"
 
@@ -14,7 +15,10 @@ import (
 	persesv1alpha2 "github.com/rhobs/perses-operator/api/v1alpha2"
 	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metaerrors "k8s.io/apimachinery/pkg/api/meta"
@@ -22,13 +26,20 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/events"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
+	msoapi "github.com/rhobs/observability-operator/pkg/apis/monitoring/v1alpha1"
 	uiv1alpha1 "github.com/rhobs/observability-operator/pkg/apis/uiplugin/v1alpha1"
+	"github.com/rhobs/observability-operator/pkg/reconciler"
 )
 
 type resourceManager struct {
@@ -36,34 +47,69 @@ type resourceManager struct {
 	k8sDynamicClient dynamic.Interface
 	scheme           *runtime.Scheme
 	logger           logr.Logger
+	recorder         events.EventRecorder
 	pluginConf       UIPluginsConfiguration
 	clusterVersion   string
 	apiReader        client.Reader
+	// persesDefinitionsReader reads the ConfigMaps holding Perses
+	// definitions from a cache limited to the ConfigMaps with the Perses
+	// definitions label.
+	persesDefinitionsReader client.Reader
+	// compatibilityMatrix maps the plugin types and the cluster versions to
+	// the images and the features of the plugins.
+	compatibilityMatrix []CompatibilityEntry
+	// compatibilityMatrixErr is set when the compatibility matrix file
+	// can't be loaded.
+	compatibilityMatrixErr error
+	// openshift is false on Kubernetes clusters without the OpenShift
+	// console where only the Perses and the health analyzer components of
+	// the Monitoring plugin are deployed.
+	openshift bool
+	// persesWatcher watches the Perses resources on Kubernetes once the
+	// Perses CRDs are installed. It's nil on OpenShift where the Perses
+	// CRDs are always installed.
+	persesWatcher *persesWatcher
 }
 
 type UIPluginsConfiguration struct {
 	Images             map[string]string
 	ResourcesNamespace string
 	TLSProfile         configv1.TLSProfileSpec
+	// CompatibilityMatrixFile is the YAML file holding the compatibility
+	// matrix. The built-in matrix is used when empty.
+	CompatibilityMatrixFile string
 }
 
 type Options struct {
 	PluginsConf    UIPluginsConfiguration
 	ClusterVersion string
+	OpenShift      bool
 }
 
 const (
 	AvailableReason         = "UIPluginAvailable"
 	ReconciledReason        = "UIPluginReconciled"
 	FailedToReconcileReason = "UIPluginFailedToReconcile"
+	TargetNotResolvedReason = "HealthAnalyzerTargetNotResolved"
 	ReconciledMessage       = "Plugin reconciled successfully"
 	NoReason                = "None"
+
+	CapabilitiesDetectedReason       = "CapabilitiesDetected"
+	FailedToDetectCapabilitiesReason = "FailedToDetectCapabilities"
+	CapabilitiesDetectedMessage      = "The capabilities of the LokiStacks are detected"
+
+	DeploymentNotFoundReason  = "DeploymentNotFound"
+	DeploymentNotReadyReason  = "DeploymentNotReady"
+	DeploymentNotFoundMessage = "The plugin deployment doesn't exist"
 )
 
 // RBAC for managing UIPlugins
 // +kubebuilder:rbac:groups=observability.openshift.io,resources=uiplugins,verbs=get;list;watch;create;update;delete;patch
 // +kubebuilder:rbac:groups=observability.openshift.io,resources=uiplugins/status;uiplugins/finalizers,verbs=get;update
 
+// RBAC for recording events
+// +kubebuilder:rbac:groups=events.k8s.io,resources=events,verbs=create;patch
+
 // RBAC for managing observability ui plugin objects
 //+kubebuilder:rbac:groups=apps,resources=deployments,verbs=list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles;rolebindings,verbs=list;watch;create;update;delete;patch
@@ -79,13 +125,25 @@ const (
 // RBAC for reading TLS security profile from APIServer
 // +kubebuilder:rbac:groups=config.openshift.io,resources=apiservers,verbs=get;list;watch
 
+// RBAC for the Ingress of Perses
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=get;list;watch;create;update;delete;patch
+
+// RBAC for the PodDisruptionBudgets of the plugin workloads
+// +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;delete;patch
+
 // RBAC for distributed tracing
-// +kubebuilder:rbac:groups=tempo.grafana.com,resources=tempostacks;tempomonolithics,verbs=list
+// +kubebuilder:rbac:groups=tempo.grafana.com,resources=tempostacks;tempomonolithics,verbs=get;list
 
 // RBAC for logging view plugin
 // +kubebuilder:rbac:groups=loki.grafana.com,resources=application;infrastructure;audit,verbs=get
 // +kubebuilder:rbac:groups=loki.grafana.com,resources=lokistacks,verbs=list;get
 
+// RBAC for the discovery of the ACM hub endpoints
+// +kubebuilder:rbac:groups=observability.open-cluster-management.io,resources=multiclusterobservabilities,verbs=get;list
+
+// RBAC for the dashboards of the MonitoringStacks and ThanosQueriers
+// +kubebuilder:rbac:groups=monitoring.rhobs,resources=monitoringstacks;thanosqueriers,verbs=list;watch
+
 // RBAC for perses
 // +kubebuilder:rbac:groups=perses.dev,resources=perses;persesdatasources;persesdashboards;persesglobaldatasources,verbs=get;list;watch;create;update;delete;patch
 // +kubebuilder:rbac:groups=perses.dev,resources=perses/status;persesdatasources/status;persesglobaldatasources/status;persesdashboards/status,verbs=get;patch;update
@@ -124,34 +182,105 @@ const finalizerName = "uiplugin.observability.openshift.io/finalizer"
 func RegisterWithManager(mgr ctrl.Manager, opts Options) error {
 	logger := ctrl.Log.WithName("observability-ui")
 
+	// An invalid matrix is reported in the status of the plugins rather than
+	// preventing the operator from starting.
+	matrix, warnings, matrixErr := LoadCompatibilityMatrix(opts.PluginsConf.CompatibilityMatrixFile, opts.PluginsConf.Images)
+	if matrixErr != nil {
+		logger.Error(matrixErr, "failed to load the compatibility matrix, the UIPlugins won't be reconciled")
+	} else {
+		setCompatibilityInfo(matrix, opts.PluginsConf.CompatibilityMatrixFile)
+	}
+	for _, warning := range warnings {
+		logger.Info("compatibility matrix warning", "warning", warning)
+	}
+
 	dynamicClient, err := dynamic.NewForConfig(mgr.GetConfig())
 	if err != nil {
 		return err
 	}
 
+	// The ConfigMaps holding Perses definitions may be in any namespace and
+	// aren't managed by the operator, they're cached apart from the
+	// manager's cache which only holds the managed ConfigMaps.
+	definitionsCache, err := cache.New(mgr.GetConfig(), cache.Options{
+		Scheme: mgr.GetScheme(),
+		Mapper: mgr.GetRESTMapper(),
+		ByObject: map[client.Object]cache.ByObject{
+			&v1.ConfigMap{}: {Label: persesDefinitionsSelector},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if err := mgr.Add(definitionsCache); err != nil {
+		return err
+	}
+
 	rm := &resourceManager{
 		k8sClient:        mgr.GetClient(),
 		k8sDynamicClient: dynamicClient,
 		scheme:           mgr.GetScheme(),
 		logger:           logger,
+		recorder:         mgr.GetEventRecorder("observability-operator"),
 		pluginConf:       opts.PluginsConf,
 		clusterVersion:   opts.ClusterVersion,
 		apiReader:        mgr.GetAPIReader(),
+		openshift:        opts.OpenShift,
+
+		persesDefinitionsReader: definitionsCache,
+
+		compatibilityMatrix:    matrix,
+		compatibilityMatrixErr: matrixErr,
 	}
 
 	generationChanged := builder.WithPredicates(predicate.GenerationChangedPredicate{})
 
 	ctrlBuilder := ctrl.NewControllerManagedBy(mgr).
 		For(&uiv1alpha1.UIPlugin{}).
-		Owns(&appsv1.Deployment{}, generationChanged).
+		// The status reports the readiness of the plugin deployment.
+		Owns(&appsv1.Deployment{}, builder.WithPredicates(predicate.Or(predicate.GenerationChangedPredicate{}, deploymentReadinessChanged))).
 		Owns(&v1.Service{}, generationChanged).
 		Owns(&v1.ServiceAccount{}, generationChanged).
 		Owns(&rbacv1.Role{}, generationChanged).
 		Owns(&rbacv1.RoleBinding{}, generationChanged).
-		Owns(&persesv1alpha2.Perses{}, generationChanged).
-		Owns(&persesv1alpha2.PersesDashboard{}, generationChanged).
-		Owns(&persesv1alpha2.PersesDatasource{}, generationChanged).
-		Owns(&persesv1alpha2.PersesGlobalDatasource{}, generationChanged)
+		Owns(&policyv1.PodDisruptionBudget{}, generationChanged).
+		Owns(&networkingv1.Ingress{}, generationChanged).
+		// The Monitoring plugin creates dashboards for the MonitoringStacks
+		// and ThanosQueriers.
+		// The labels of the MonitoringStacks are watched since they're
+		// matched by the selector of the Perses configuration.
+		Watches(&msoapi.MonitoringStack{}, handler.EnqueueRequestsFromMapFunc(rm.monitoringPluginRequests),
+			builder.WithPredicates(predicate.Or(predicate.GenerationChangedPredicate{}, predicate.LabelChangedPredicate{}))).
+		Watches(&msoapi.ThanosQuerier{}, handler.EnqueueRequestsFromMapFunc(rm.monitoringPluginRequests), generationChanged).
+		// The Perses resources are updated when the ConfigMaps holding
+		// their definitions change.
+		WatchesRawSource(source.Kind[client.Object](definitionsCache, &v1.ConfigMap{},
+			handler.EnqueueRequestsFromMapFunc(rm.persesDefinitionsPluginRequests)))
+
+	// On Kubernetes, the Perses resources can't be watched until the Perses
+	// operator is installed.
+	if !rm.openshift {
+		rm.persesWatcher = &persesWatcher{mapper: mgr.GetRESTMapper()}
+		c, err := ctrlBuilder.Build(rm)
+		if err != nil {
+			return err
+		}
+		rm.persesWatcher.watch = func() error {
+			for _, obj := range persesOwnedObjects() {
+				if err := c.Watch(source.Kind(mgr.GetCache(), obj,
+					handler.EnqueueRequestForOwner(mgr.GetScheme(), mgr.GetRESTMapper(), &uiv1alpha1.UIPlugin{}, handler.OnlyControllerOwner()),
+					predicate.GenerationChangedPredicate{})); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		return nil
+	}
+
+	for _, obj := range persesOwnedObjects() {
+		ctrlBuilder.Owns(obj, generationChanged)
+	}
 
 	if IsVersionAheadOrEqual(rm.clusterVersion, "v4.19") {
 		ctrlBuilder.Owns(&osv1.ConsolePlugin{}, generationChanged)
@@ -164,6 +293,82 @@ func RegisterWithManager(mgr ctrl.Manager, opts Options) error {
 	return ctrlBuilder.Complete(rm)
 }
 
+// persesOwnedObjects returns the types of the Perses resources owned by the
+// plugins.
+func persesOwnedObjects() []client.Object {
+	return []client.Object{
+		&persesv1alpha2.Perses{},
+		&persesv1alpha2.PersesDashboard{},
+		&persesv1alpha2.PersesDatasource{},
+		&persesv1alpha2.PersesGlobalDatasource{},
+	}
+}
+
+// deploymentReadinessChanged triggers a reconciliation when the number of
+// replicas or ready replicas of a deployment changes.
+var deploymentReadinessChanged = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldDeployment, ok := e.ObjectOld.(*appsv1.Deployment)
+		if !ok {
+			return false
+		}
+		newDeployment, ok := e.ObjectNew.(*appsv1.Deployment)
+		if !ok {
+			return false
+		}
+		return oldDeployment.Status.Replicas != newDeployment.Status.Replicas ||
+			oldDeployment.Status.ReadyReplicas != newDeployment.Status.ReadyReplicas
+	},
+}
+
+// monitoringPluginRequests returns the requests to reconcile the Monitoring
+// plugins with Perses enabled or whose health analyzer targets obj.
+func (rm resourceManager) monitoringPluginRequests(ctx context.Context, obj client.Object) []ctrl.Request {
+	plugins := &uiv1alpha1.UIPluginList{}
+	if err := rm.k8sClient.List(ctx, plugins); err != nil {
+		rm.logger.Error(err, "failed to list UIPlugins")
+		return nil
+	}
+
+	var requests []ctrl.Request
+	for _, plugin := range plugins.Items {
+		config := plugin.Spec.Monitoring
+		if plugin.Spec.Type != uiv1alpha1.TypeMonitoring || config == nil {
+			continue
+		}
+		persesEnabled := config.Perses != nil && config.Perses.Enabled
+		targeted := hasHealthAnalyzerTarget(&plugin) &&
+			config.ClusterHealthAnalyzer.Target.Name == obj.GetName() &&
+			config.ClusterHealthAnalyzer.Target.Namespace == obj.GetNamespace()
+		if !persesEnabled && !targeted {
+			continue
+		}
+		requests = append(requests, ctrl.Request{NamespacedName: types.NamespacedName{Name: plugin.Name}})
+	}
+
+	return requests
+}
+
+// persesDefinitionsPluginRequests returns the requests to reconcile the
+// Monitoring plugins which reference or select the ConfigMap for their Perses
+// definitions.
+func (rm resourceManager) persesDefinitionsPluginRequests(ctx context.Context, obj client.Object) []ctrl.Request {
+	plugins := &uiv1alpha1.UIPluginList{}
+	if err := rm.k8sClient.List(ctx, plugins); err != nil {
+		rm.logger.Error(err, "failed to list UIPlugins")
+		return nil
+	}
+
+	var requests []ctrl.Request
+	for _, plugin := range plugins.Items {
+		if selectsPersesDefinitions(&plugin, obj) {
+			requests = append(requests, ctrl.Request{NamespacedName: types.NamespacedName{Name: plugin.Name}})
+		}
+	}
+
+	return requests
+}
+
 func (rm resourceManager) consolePluginCapabilityEnabled(ctx context.Context, name types.NamespacedName, clusterVersion string) bool {
 	var err error
 
@@ -181,7 +386,7 @@ func (rm resourceManager) consolePluginCapabilityEnabled(ctx context.Context, na
 func (rm resourceManager) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	logger := rm.logger.WithValues("plugin", req.NamespacedName)
 
-	if !rm.consolePluginCapabilityEnabled(ctx, req.NamespacedName, rm.clusterVersion) {
+	if rm.openshift && !rm.consolePluginCapabilityEnabled(ctx, req.NamespacedName, rm.clusterVersion) {
 		logger.Info("Cluster console plugin not supported or not accessible. Skipping observability UI plugin reconciliation")
 		return ctrl.Result{}, nil
 	}
@@ -201,9 +406,11 @@ func (rm resourceManager) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 
 	// Check if the plugin is being deleted
 	if !plugin.ObjectMeta.DeletionTimestamp.IsZero() {
-		logger.V(6).Info("deregistering plugin from the console")
-		if err := rm.deregisterPluginFromConsole(ctx, pluginTypeToConsoleName[plugin.Spec.Type]); err != nil {
-			logger.V(3).Info("best-effort console deregistration failed during deletion", "error", err)
+		if rm.openshift {
+			logger.V(6).Info("deregistering plugin from the console")
+			if err := rm.deregisterPluginFromConsole(ctx, pluginTypeToConsoleName[plugin.Spec.Type]); err != nil {
+				logger.V(3).Info("best-effort console deregistration failed during deletion", "error", err)
+			}
 		}
 
 		if err := rm.removeLegacyFinalizer(ctx, plugin); err != nil {
@@ -230,10 +437,20 @@ func (rm resourceManager) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 		return ctrl.Result{}, err
 	}
 
-	compatibilityInfo, err := lookupImageAndFeatures(plugin.Spec.Type, rm.clusterVersion)
+	if !rm.openshift {
+		return rm.reconcileKubernetesPlugin(ctx, req, plugin)
+	}
+
+	observed := &pluginObservation{}
+	if rm.compatibilityMatrixErr != nil {
+		// The matrix is only loaded at startup, retrying doesn't help.
+		return rm.updateStatus(ctx, req, plugin, observed, rm.compatibilityMatrixErr), nil
+	}
+	compatibilityInfo, err := lookupImageAndFeatures(rm.compatibilityMatrix, plugin.Spec.Type, rm.clusterVersion)
 	if err != nil {
-		return rm.updateStatus(ctx, req, plugin, err), err
+		return rm.updateStatus(ctx, req, plugin, observed, err), err
 	}
+	observed.compatibility = &compatibilityInfo
 
 	if plugin.Annotations == nil {
 		plugin.Annotations = map[string]string{}
@@ -254,93 +471,197 @@ func (rm resourceManager) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 		return ctrl.Result{}, nil
 	}
 
-	pluginInfo, pluginInfoErr := PluginInfoBuilder(ctx, rm.k8sClient, rm.k8sDynamicClient, plugin, rm.pluginConf, compatibilityInfo, rm.clusterVersion, rm.logger)
+	pluginInfo, pluginInfoErr := PluginInfoBuilder(ctx, rm.k8sClient, rm.apiReader, rm.k8sDynamicClient, plugin, rm.pluginConf, compatibilityInfo, rm.clusterVersion, rm.logger)
+	if pluginInfo != nil && pluginInfoErr == nil {
+		// The ConfigMaps aren't managed by the operator, they are read from
+		// the cache of the labelled Perses definition ConfigMaps.
+		pluginInfo.PersesDefinitions, pluginInfoErr = loadPersesDefinitions(ctx, rm.persesDefinitionsReader, plugin)
+	}
+	if pluginInfo != nil && pluginInfoErr == nil {
+		pluginInfo.OperatorWorkloads, pluginInfoErr = listOperatorWorkloads(ctx, rm.k8sClient, plugin)
+	}
+	if pluginInfo != nil && pluginInfoErr == nil {
+		pluginInfo.HealthAnalyzerComponents, pluginInfoErr = loadHealthAnalyzerComponents(ctx, rm.apiReader, plugin)
+	}
+	if pluginInfo != nil && pluginInfoErr == nil {
+		// The health analyzer keeps analyzing the last resolved target
+		// rather than failing the reconciliation of the plugin.
+		pluginInfo.HealthAnalyzerTarget, pluginInfo.HealthAnalyzerTargetErr = loadHealthAnalyzerTarget(ctx, rm.k8sClient, plugin)
+	}
+	observed.info = pluginInfo
 
 	if pluginInfo != nil {
-		reconcilers := pluginComponentReconcilers(plugin, *pluginInfo, rm.clusterVersion, rm.logger)
-		for _, reconciler := range reconcilers {
-			err := reconciler.Reconcile(ctx, rm.k8sClient, rm.scheme)
-			// handle creation / updation errors that can happen due to a stale cache by
-			// retrying after some time.
-			if apierrors.IsAlreadyExists(err) || apierrors.IsConflict(err) {
-				logger.V(8).Info("skipping reconcile error", "err", err)
-				return ctrl.Result{RequeueAfter: 2 * time.Second}, nil
-			}
-			if err != nil {
-				return rm.updateStatus(ctx, req, plugin, err), err
-			}
+		// Nothing is reconciled, nor pruned, when a resource can't be
+		// rendered.
+		reconcilers, err := pluginComponentReconcilers(plugin, *pluginInfo, rm.clusterVersion, rm.logger)
+		if err != nil {
+			return rm.updateStatus(ctx, req, plugin, observed, err), err
+		}
+		plan := reconciler.NewPlan()
+		components := plan.AddAll(reconcilers)
+		// Failed overrides are reported in the status and don't prevent the
+		// reconciliation of the resources.
+		observed.overridesErr = reconciler.ApplyOverrides(plan.Reconcilers(), plugin.Spec.Overrides)
+		if observed.overridesErr != nil {
+			logger.Info("failed to apply overrides", "err", observed.overridesErr)
+		}
+		// Prune the resources which were applied by a previous reconciliation
+		// but aren't desired anymore. The plugin information is incomplete
+		// when an error occurred or the health analyzer target isn't
+		// resolved, so nothing is pruned.
+		if pluginInfoErr == nil && pluginInfo.HealthAnalyzerTargetErr == nil {
+			plan.Add(reconciler.NewInventory(plugin, pluginInfo.ResourceNamespace, plan.Reconcilers()), components...)
+		}
+		// The components which don't depend on a failing one are still
+		// reconciled but the plugin isn't registered with the console.
+		err = plan.Reconcile(ctx, rm.k8sClient, rm.scheme, reconciler.NewEventRecorder(rm.recorder, plugin))
+		// handle creation / updation errors that can happen due to a stale cache by
+		// retrying after some time.
+		if reconciler.IsStaleCacheError(err) {
+			logger.V(8).Info("skipping reconcile error", "err", err)
+			return ctrl.Result{RequeueAfter: 2 * time.Second}, nil
+		}
+		if err != nil {
+			return rm.updateStatus(ctx, req, plugin, observed, err), err
 		}
 		if pluginInfo.AreMonitoringFeatsDisabled {
 			// prevents double rendering of monitoring console-tabs
 			if err := rm.deregisterPluginFromConsole(ctx, pluginTypeToConsoleName[plugin.Spec.Type]); err != nil {
-				return rm.updateStatus(ctx, req, plugin, err), err
+				return rm.updateStatus(ctx, req, plugin, observed, err), err
 			}
 		}
 	}
 
 	if pluginInfoErr != nil {
 		// If features are disabled allow pluginComponentReconcilers to remove uiplugin-related components before status update
-		return rm.updateStatus(ctx, req, plugin, pluginInfoErr), pluginInfoErr
+		return rm.updateStatus(ctx, req, plugin, observed, pluginInfoErr), pluginInfoErr
 	}
 
 	if err := rm.registerPluginWithConsole(ctx, pluginInfo); err != nil {
-		return rm.updateStatus(ctx, req, plugin, err), err
+		return rm.updateStatus(ctx, req, plugin, observed, err), err
 	}
 
-	return rm.updateStatus(ctx, req, plugin, nil), nil
+	return withResync(plugin, rm.updateStatus(ctx, req, plugin, observed, nil)), nil
+}
+
+// resyncPeriod is the period at which the plugins depending on resources
+// which aren't watched are reconciled again.
+const resyncPeriod = 5 * time.Minute
+
+// withResync requeues the plugin after resyncPeriod when it depends on
+// resources which aren't watched: the ConfigMap holding additional health
+// analyzer components, the CA certificates of the health analyzer target
+// which may be renewed, the hub endpoints of ACM and the ConfigMaps holding
+// the korrel8r rules and CAs.
+func withResync(plugin *uiv1alpha1.UIPlugin, result ctrl.Result) ctrl.Result {
+	if result.RequeueAfter != 0 {
+		return result
+	}
+	if hasHealthAnalyzerComponents(plugin) || hasHealthAnalyzerTarget(plugin) || discoversACMEndpoints(plugin) ||
+		len(korrel8rConfigMaps(plugin)) > 0 {
+		result.RequeueAfter = resyncPeriod
+	}
+	return result
 }
 
-func (rm resourceManager) updateStatus(ctx context.Context, req ctrl.Request, pl *uiv1alpha1.UIPlugin, recError error) ctrl.Result {
+// pluginObservation holds what is known about the plugin at the end of the
+// reconciliation to report it in the status.
+type pluginObservation struct {
+	compatibility *CompatibilityEntry
+	info          *UIPluginInfo
+	overridesErr  error
+}
+
+func (rm resourceManager) updateStatus(ctx context.Context, req ctrl.Request, pl *uiv1alpha1.UIPlugin, observed *pluginObservation, recError error) ctrl.Result {
 	logger := rm.logger.WithValues("plugin", req.NamespacedName)
 
-	var changed bool
+	previous := pl.Status.DeepCopy()
+
+	if observed.compatibility != nil {
+		pl.Status.SupportLevel = string(observed.compatibility.SupportLevel)
+	}
+
+	var deployment *appsv1.Deployment
+	if info := observed.info; info != nil {
+		pl.Status.Image = info.Image
+		pl.Status.Backends = info.Backends
+		pl.Status.Korrel8rStores = nil
+		if info.Korrel8r != nil {
+			pl.Status.Korrel8rStores = info.Korrel8r.stores
+		}
+		pl.Status.Logging = info.LoggingStatus
+		pl.Status.ACM = info.ACMStatus
+		pl.Status.Features = info.Features
+		pl.Status.UnsupportedFeatures = info.UnsupportedFeatures
+		pl.Status.PersesDefinitions = nil
+		if info.PersesDefinitions != nil {
+			pl.Status.PersesDefinitions = info.PersesDefinitions.status
+		}
+
+		// No console plugin nor plugin deployment exist on Kubernetes.
+		if rm.openshift {
+			enabled, err := rm.consolePluginEnabled(ctx, info.ConsoleName)
+			if err != nil {
+				logger.Info("Failed to get the console configuration", "err", err)
+				if pl.Status.ConsolePlugin != nil {
+					enabled = pl.Status.ConsolePlugin.Enabled
+				}
+			}
+			pl.Status.ConsolePlugin = &uiv1alpha1.ConsolePluginStatus{
+				Name:    info.ConsoleName,
+				Enabled: enabled,
+			}
+
+			deployment = &appsv1.Deployment{}
+			if err := rm.k8sClient.Get(ctx, client.ObjectKey{Name: info.Name, Namespace: info.ResourceNamespace}, deployment); err != nil {
+				if !apierrors.IsNotFound(err) {
+					logger.Info("Failed to get the plugin deployment", "err", err)
+				}
+				deployment = nil
+			}
+		}
+		pl.Status.Replicas, pl.Status.ReadyReplicas = 0, 0
+		if deployment != nil {
+			pl.Status.Replicas = deployment.Status.Replicas
+			pl.Status.ReadyReplicas = deployment.Status.ReadyReplicas
+		}
+
+		setOverridesCondition(pl, observed.overridesErr)
+		setLoggingCapabilitiesCondition(pl, info)
+	}
 
 	if recError != nil {
-		changed = meta.SetStatusCondition(&pl.Status.Conditions, metav1.Condition{
+		meta.SetStatusCondition(&pl.Status.Conditions, metav1.Condition{
 			Type:               string(uiv1alpha1.DegradedCondition),
 			Status:             metav1.ConditionTrue,
 			Reason:             FailedToReconcileReason,
 			Message:            recError.Error(),
 			ObservedGeneration: pl.Generation,
-		}) || changed
-		changed = meta.SetStatusCondition(&pl.Status.Conditions, metav1.Condition{
+		})
+		meta.SetStatusCondition(&pl.Status.Conditions, metav1.Condition{
 			Type:               string(uiv1alpha1.ReconciledCondition),
 			Status:             metav1.ConditionFalse,
 			Reason:             FailedToReconcileReason,
 			Message:            recError.Error(),
 			ObservedGeneration: pl.Generation,
-		}) || changed
-
-		changed = meta.SetStatusCondition(&pl.Status.Conditions, metav1.Condition{
-			Type:               string(uiv1alpha1.AvailableCondition),
-			Status:             metav1.ConditionFalse,
-			Reason:             FailedToReconcileReason,
-			ObservedGeneration: pl.Generation,
-		}) || changed
+		})
 	} else {
-		changed = meta.SetStatusCondition(&pl.Status.Conditions, metav1.Condition{
+		meta.SetStatusCondition(&pl.Status.Conditions, metav1.Condition{
 			Type:               string(uiv1alpha1.ReconciledCondition),
 			Status:             metav1.ConditionTrue,
 			Reason:             ReconciledReason,
 			Message:            ReconciledMessage,
 			ObservedGeneration: pl.Generation,
-		}) || changed
-		changed = meta.SetStatusCondition(&pl.Status.Conditions, metav1.Condition{
-			Type:               string(uiv1alpha1.DegradedCondition),
-			Status:             metav1.ConditionFalse,
-			Reason:             ReconciledReason,
-			ObservedGeneration: pl.Generation,
-		}) || changed
-
-		changed = meta.SetStatusCondition(&pl.Status.Conditions, metav1.Condition{
-			Type:               string(uiv1alpha1.AvailableCondition),
-			Status:             metav1.ConditionTrue,
-			Reason:             AvailableReason,
-			ObservedGeneration: pl.Generation,
-		}) || changed
+		})
+		meta.SetStatusCondition(&pl.Status.Conditions, degradedCondition(pl, observed.info))
+	}
+	if rm.openshift {
+		meta.SetStatusCondition(&pl.Status.Conditions, availableCondition(pl, observed.info, deployment, recError))
+	} else {
+		meta.SetStatusCondition(&pl.Status.Conditions, rm.kubernetesAvailableCondition(ctx, pl, observed.info, recError))
 	}
 
-	if !changed {
+	if equality.Semantic.DeepEqual(previous, &pl.Status) {
 		return ctrl.Result{}
 	}
 
@@ -353,6 +674,118 @@ func (rm resourceManager) updateStatus(ctx context.Context, req ctrl.Request, pl
 	return ctrl.Result{}
 }
 
+// degradedCondition returns the Degraded condition of a reconciled plugin.
+// The plugin is degraded when its health analyzer runs with the last
+// resolved target.
+func degradedCondition(pl *uiv1alpha1.UIPlugin, info *UIPluginInfo) metav1.Condition {
+	if info != nil && info.HealthAnalyzerTargetErr != nil {
+		return metav1.Condition{
+			Type:               string(uiv1alpha1.DegradedCondition),
+			Status:             metav1.ConditionTrue,
+			Reason:             TargetNotResolvedReason,
+			Message:            info.HealthAnalyzerTargetErr.Error(),
+			ObservedGeneration: pl.Generation,
+		}
+	}
+	return metav1.Condition{
+		Type:               string(uiv1alpha1.DegradedCondition),
+		Status:             metav1.ConditionFalse,
+		Reason:             ReconciledReason,
+		ObservedGeneration: pl.Generation,
+	}
+}
+
+// availableCondition returns the Available condition of the plugin which
+// reflects the readiness of the pods of the plugin deployment. The deployment
+// is nil when it doesn't exist.
+func availableCondition(pl *uiv1alpha1.UIPlugin, info *UIPluginInfo, deployment *appsv1.Deployment, recError error) metav1.Condition {
+	condition := metav1.Condition{
+		Type:               string(uiv1alpha1.AvailableCondition),
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: pl.Generation,
+	}
+
+	switch {
+	case info == nil && recError != nil:
+		// The plugin resources couldn't be computed.
+		condition.Reason = FailedToReconcileReason
+	case deployment == nil:
+		condition.Reason = DeploymentNotFoundReason
+		condition.Message = DeploymentNotFoundMessage
+	case deployment.Status.ReadyReplicas == 0:
+		condition.Reason = DeploymentNotReadyReason
+		condition.Message = fmt.Sprintf("%d/%d replicas are ready", deployment.Status.ReadyReplicas, deployment.Status.Replicas)
+	default:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = AvailableReason
+		condition.Message = fmt.Sprintf("%d/%d replicas are ready", deployment.Status.ReadyReplicas, deployment.Status.Replicas)
+	}
+	return condition
+}
+
+// setOverridesCondition sets the OverridesApplied condition of the plugin
+// when it has overrides and removes it otherwise.
+func setOverridesCondition(pl *uiv1alpha1.UIPlugin, overridesErr error) {
+	if len(pl.Spec.Overrides) == 0 {
+		meta.RemoveStatusCondition(&pl.Status.Conditions, string(uiv1alpha1.OverridesAppliedCondition))
+		return
+	}
+	if overridesErr != nil {
+		meta.SetStatusCondition(&pl.Status.Conditions, metav1.Condition{
+			Type:               string(uiv1alpha1.OverridesAppliedCondition),
+			Status:             metav1.ConditionFalse,
+			Reason:             reconciler.FailedToApplyOverridesReason,
+			Message:            overridesErr.Error(),
+			ObservedGeneration: pl.Generation,
+		})
+		return
+	}
+	meta.SetStatusCondition(&pl.Status.Conditions, metav1.Condition{
+		Type:               string(uiv1alpha1.OverridesAppliedCondition),
+		Status:             metav1.ConditionTrue,
+		Reason:             reconciler.OverridesAppliedReason,
+		Message:            reconciler.OverridesAppliedMessage,
+		ObservedGeneration: pl.Generation,
+	})
+}
+
+// setLoggingCapabilitiesCondition reports whether the capabilities of the
+// LokiStacks could be detected. The features which depend on undetected
+// capabilities, such as the alerts, are disabled unless explicitly enabled.
+func setLoggingCapabilitiesCondition(pl *uiv1alpha1.UIPlugin, info *UIPluginInfo) {
+	if info.LoggingStatus == nil {
+		meta.RemoveStatusCondition(&pl.Status.Conditions, string(uiv1alpha1.LoggingCapabilitiesDetectedCondition))
+		return
+	}
+	if info.LoggingCapabilitiesErr != nil {
+		meta.SetStatusCondition(&pl.Status.Conditions, metav1.Condition{
+			Type:               string(uiv1alpha1.LoggingCapabilitiesDetectedCondition),
+			Status:             metav1.ConditionFalse,
+			Reason:             FailedToDetectCapabilitiesReason,
+			Message:            info.LoggingCapabilitiesErr.Error(),
+			ObservedGeneration: pl.Generation,
+		})
+		return
+	}
+	meta.SetStatusCondition(&pl.Status.Conditions, metav1.Condition{
+		Type:               string(uiv1alpha1.LoggingCapabilitiesDetectedCondition),
+		Status:             metav1.ConditionTrue,
+		Reason:             CapabilitiesDetectedReason,
+		Message:            CapabilitiesDetectedMessage,
+		ObservedGeneration: pl.Generation,
+	})
+}
+
+// consolePluginEnabled returns true if the console plugin is enabled in the
+// Console operator configuration.
+func (rm resourceManager) consolePluginEnabled(ctx context.Context, pluginConsoleName string) (bool, error) {
+	cluster := &operatorv1.Console{}
+	if err := rm.apiReader.Get(ctx, client.ObjectKey{Name: "cluster"}, cluster); err != nil {
+		return false, err
+	}
+	return slices.Contains(cluster.Spec.Plugins, pluginConsoleName), nil
+}
+
 func (rm resourceManager) registerPluginWithConsole(ctx context.Context, pluginInfo *UIPluginInfo) error {
 	cluster := &operatorv1.Console{}
 	if err := rm.apiReader.Get(ctx, client.ObjectKey{Name: "cluster"}, cluster); err != nil {
@@ -0,0 +1,142 @@
+package uiplugin
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	uiv1alpha1 "github.com/rhobs/observability-operator/pkg/apis/uiplugin/v1alpha1"
+)
+
+func TestAvailableCondition(t *testing.T) {
+	plugin := &uiv1alpha1.UIPlugin{ObjectMeta: metav1.ObjectMeta{Name: "logging", Generation: 2}}
+	newDeployment := func(replicas, ready int32) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			Status: appsv1.DeploymentStatus{Replicas: replicas, ReadyReplicas: ready},
+		}
+	}
+
+	for _, tc := range []struct {
+		name       string
+		info       *UIPluginInfo
+		deployment *appsv1.Deployment
+		recError   error
+		status     metav1.ConditionStatus
+		reason     string
+		message    string
+	}{
+		{
+			name:     "plugin information not available",
+			recError: errors.New("no compatible image"),
+			status:   metav1.ConditionFalse,
+			reason:   FailedToReconcileReason,
+		},
+		{
+			name:    "deployment not found",
+			info:    &UIPluginInfo{},
+			status:  metav1.ConditionFalse,
+			reason:  DeploymentNotFoundReason,
+			message: DeploymentNotFoundMessage,
+		},
+		{
+			name:       "no ready replicas",
+			info:       &UIPluginInfo{},
+			deployment: newDeployment(2, 0),
+			status:     metav1.ConditionFalse,
+			reason:     DeploymentNotReadyReason,
+			message:    "0/2 replicas are ready",
+		},
+		{
+			name:       "ready replicas",
+			info:       &UIPluginInfo{},
+			deployment: newDeployment(2, 1),
+			status:     metav1.ConditionTrue,
+			reason:     AvailableReason,
+			message:    "1/2 replicas are ready",
+		},
+		{
+			name:       "ready replicas with a reconciliation error",
+			info:       &UIPluginInfo{},
+			deployment: newDeployment(1, 1),
+			recError:   errors.New("failed to register the plugin"),
+			status:     metav1.ConditionTrue,
+			reason:     AvailableReason,
+			message:    "1/1 replicas are ready",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			condition := availableCondition(plugin, tc.info, tc.deployment, tc.recError)
+			assert.Equal(t, condition.Type, string(uiv1alpha1.AvailableCondition))
+			assert.Equal(t, condition.Status, tc.status)
+			assert.Equal(t, condition.Reason, tc.reason)
+			assert.Equal(t, condition.Message, tc.message)
+			assert.Equal(t, condition.ObservedGeneration, int64(2))
+		})
+	}
+}
+
+func TestDegradedCondition(t *testing.T) {
+	plugin := &uiv1alpha1.UIPlugin{ObjectMeta: metav1.ObjectMeta{Name: "monitoring", Generation: 3}}
+
+	condition := degradedCondition(plugin, &UIPluginInfo{})
+	assert.Equal(t, condition.Status, metav1.ConditionFalse)
+	assert.Equal(t, condition.Reason, ReconciledReason)
+
+	condition = degradedCondition(plugin, &UIPluginInfo{
+		HealthAnalyzerTargetErr: errors.New("health analyzer target MonitoringStack team-a/team-a not found"),
+	})
+	assert.Equal(t, condition.Type, string(uiv1alpha1.DegradedCondition))
+	assert.Equal(t, condition.Status, metav1.ConditionTrue)
+	assert.Equal(t, condition.Reason, TargetNotResolvedReason)
+	assert.Equal(t, condition.Message, "health analyzer target MonitoringStack team-a/team-a not found")
+	assert.Equal(t, condition.ObservedGeneration, int64(3))
+}
+
+func TestWithResync(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		config   *uiv1alpha1.MonitoringConfig
+		result   ctrl.Result
+		expected ctrl.Result
+	}{
+		{
+			name:   "nothing to resync",
+			config: &uiv1alpha1.MonitoringConfig{},
+		},
+		{
+			name: "health analyzer components",
+			config: &uiv1alpha1.MonitoringConfig{
+				ClusterHealthAnalyzer: &uiv1alpha1.ClusterHealthAnalyzerReference{
+					Enabled:    true,
+					Components: &uiv1alpha1.NamespacedConfigMapReference{Name: "components", Namespace: "team-a"},
+				},
+			},
+			expected: ctrl.Result{RequeueAfter: resyncPeriod},
+		},
+		{
+			name: "ACM discovery",
+			config: &uiv1alpha1.MonitoringConfig{
+				ACM: &uiv1alpha1.AdvancedClusterManagementReference{Enabled: true, Discover: true},
+			},
+			expected: ctrl.Result{RequeueAfter: resyncPeriod},
+		},
+		{
+			name: "earlier requeue",
+			config: &uiv1alpha1.MonitoringConfig{
+				ACM: &uiv1alpha1.AdvancedClusterManagementReference{Enabled: true, Discover: true},
+			},
+			result:   ctrl.Result{RequeueAfter: time.Second},
+			expected: ctrl.Result{RequeueAfter: time.Second},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			plugin := newMonitoringPlugin(tc.config)
+			assert.Equal(t, withResync(plugin, tc.result), tc.expected)
+		})
+	}
+}
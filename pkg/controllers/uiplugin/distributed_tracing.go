@@ -2,18 +2,26 @@ package uiplugin
 
 import (
 	"bytes"
+	"context"
 	"fmt"
+	"slices"
 	"strings"
 
+	"github.com/go-logr/logr"
 	"gopkg.in/yaml.v3"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
 
 	uiv1alpha1 "github.com/rhobs/observability-operator/pkg/apis/uiplugin/v1alpha1"
 )
 
-func createDistributedTracingPluginInfo(plugin *uiv1alpha1.UIPlugin, namespace, name, image string, features []string) (*UIPluginInfo, error) {
+func createDistributedTracingPluginInfo(plugin *uiv1alpha1.UIPlugin, namespace, name, image string, features []string, instances []tempoInstance) (*UIPluginInfo, error) {
 	distributedTracingConfig := plugin.Spec.DistributedTracing
 
 	configYaml, err := marshalDistributedTracingPluginConfig(distributedTracingConfig)
@@ -25,10 +33,12 @@ func createDistributedTracingPluginInfo(plugin *uiv1alpha1.UIPlugin, namespace,
 		"-plugin-config-path=/etc/plugin/config/config.yaml",
 	}
 
+	features, unsupported := pluginFeatures(plugin, features, features)
 	if len(features) > 0 {
 		extraArgs = append(extraArgs, fmt.Sprintf("-features=%s", strings.Join(features, ",")))
 	}
 
+	serviceAccountName := plugin.Name + serviceAccountSuffix
 	pluginInfo := &UIPluginInfo{
 		Image:             image,
 		Name:              plugin.Name,
@@ -58,7 +68,12 @@ func createDistributedTracingPluginInfo(plugin *uiv1alpha1.UIPlugin, namespace,
 				"config.yaml": configYaml,
 			},
 		},
-		ClusterRoles: []*rbacv1.ClusterRole{
+	}
+	pluginInfo.Features = features
+	pluginInfo.UnsupportedFeatures = unsupported
+
+	if !restrictsTempoInstances(distributedTracingConfig) {
+		pluginInfo.ClusterRoles = []*rbacv1.ClusterRole{
 			{
 				TypeMeta: metav1.TypeMeta{
 					APIVersion: rbacv1.SchemeGroupVersion.String(),
@@ -76,8 +91,8 @@ func createDistributedTracingPluginInfo(plugin *uiv1alpha1.UIPlugin, namespace,
 					},
 				},
 			},
-		},
-		ClusterRoleBindings: []*rbacv1.ClusterRoleBinding{
+		}
+		pluginInfo.ClusterRoleBindings = []*rbacv1.ClusterRoleBinding{
 			{
 				TypeMeta: metav1.TypeMeta{
 					APIVersion: rbacv1.SchemeGroupVersion.String(),
@@ -90,7 +105,7 @@ func createDistributedTracingPluginInfo(plugin *uiv1alpha1.UIPlugin, namespace,
 				Subjects: []rbacv1.Subject{{
 					APIGroup:  corev1.SchemeGroupVersion.Group,
 					Kind:      "ServiceAccount",
-					Name:      plugin.Name + "-sa",
+					Name:      serviceAccountName,
 					Namespace: namespace,
 				}},
 				RoleRef: rbacv1.RoleRef{
@@ -99,12 +114,89 @@ func createDistributedTracingPluginInfo(plugin *uiv1alpha1.UIPlugin, namespace,
 					Name:     plugin.Name + "-cr",
 				},
 			},
-		},
+		}
+		return pluginInfo, nil
 	}
 
+	// The plugin lists the Tempo instances with its service account and
+	// queries them through its backend: restricting the service account to
+	// the selected instances restricts the instances shown by the plugin.
+	for _, instance := range instances {
+		serviceName, servicePort := instance.service()
+		pluginInfo.Backends = append(pluginInfo.Backends, uiv1alpha1.UIPluginBackend{
+			Type:      uiv1alpha1.BackendType(instance.Kind),
+			Name:      instance.Name,
+			Namespace: instance.Namespace,
+			URL:       fmt.Sprintf("https://%s.%s.svc:%d", serviceName, instance.Namespace, servicePort),
+		})
+	}
+	pluginInfo.Roles, pluginInfo.RoleBindings = tempoInstancesRBAC(plugin.Name, serviceAccountName, namespace, instances)
+
 	return pluginInfo, nil
 }
 
+// tempoInstancesRBAC returns the roles, one per namespace, which grant the
+// service account of the plugin read access to the selected instances. The
+// list verb can't be restricted by name: the plugin can read all the
+// instances of the same kind in the namespaces of the selected instances.
+func tempoInstancesRBAC(pluginName, serviceAccountName, namespace string, instances []tempoInstance) ([]*rbacv1.Role, []*rbacv1.RoleBinding) {
+	var (
+		roles        []*rbacv1.Role
+		roleBindings []*rbacv1.RoleBinding
+		byNamespace  = map[string]*rbacv1.Role{}
+		roleName     = pluginName + "-tempo-view"
+	)
+	for _, instance := range instances {
+		role, found := byNamespace[instance.Namespace]
+		if !found {
+			role = &rbacv1.Role{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: rbacv1.SchemeGroupVersion.String(),
+					Kind:       "Role",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      roleName,
+					Namespace: instance.Namespace,
+				},
+			}
+			byNamespace[instance.Namespace] = role
+			roles = append(roles, role)
+			roleBindings = append(roleBindings, &rbacv1.RoleBinding{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: rbacv1.SchemeGroupVersion.String(),
+					Kind:       "RoleBinding",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      roleName,
+					Namespace: instance.Namespace,
+				},
+				Subjects: []rbacv1.Subject{{
+					APIGroup:  corev1.SchemeGroupVersion.Group,
+					Kind:      "ServiceAccount",
+					Name:      serviceAccountName,
+					Namespace: namespace,
+				}},
+				RoleRef: rbacv1.RoleRef{
+					APIGroup: rbacv1.SchemeGroupVersion.Group,
+					Kind:     "Role",
+					Name:     roleName,
+				},
+			})
+		}
+
+		resource := tempoResourceOf(instance.Kind).Resource
+		if !slices.ContainsFunc(role.Rules, func(r rbacv1.PolicyRule) bool { return r.Resources[0] == resource }) {
+			role.Rules = append(role.Rules, rbacv1.PolicyRule{
+				APIGroups: []string{"tempo.grafana.com"},
+				Resources: []string{resource},
+				Verbs:     []string{"get", "list"},
+			})
+		}
+	}
+
+	return roles, roleBindings
+}
+
 func marshalDistributedTracingPluginConfig(cfg *uiv1alpha1.DistributedTracingConfig) (string, error) {
 	if cfg == nil {
 		return "", nil
@@ -127,3 +219,128 @@ func marshalDistributedTracingPluginConfig(cfg *uiv1alpha1.DistributedTracingCon
 
 	return buf.String(), nil
 }
+
+// restrictsTempoInstances returns true when the plugin is restricted to
+// a set of Tempo instances.
+func restrictsTempoInstances(cfg *uiv1alpha1.DistributedTracingConfig) bool {
+	return cfg != nil && (len(cfg.Instances) > 0 || cfg.InstanceSelector != nil)
+}
+
+// tempoInstance is a Tempo instance the plugin is restricted to.
+type tempoInstance struct {
+	uiv1alpha1.TempoInstanceReference
+	// gateway is true when the instance is served by the multi-tenancy gateway.
+	gateway bool
+}
+
+// service returns the service serving the queries of the instance.
+func (t tempoInstance) service() (string, int32) {
+	switch {
+	case t.gateway:
+		return fmt.Sprintf("tempo-%s-gateway", t.Name), 8080
+	case t.Kind == uiv1alpha1.TempoStackKind:
+		return fmt.Sprintf("tempo-%s-query-frontend", t.Name), 3200
+	default:
+		return fmt.Sprintf("tempo-%s", t.Name), 3200
+	}
+}
+
+var tempoResources = []struct {
+	kind        uiv1alpha1.TempoInstanceKind
+	backendType uiv1alpha1.BackendType
+	resource    schema.GroupVersionResource
+	// gatewayField is the path of the field enabling the gateway.
+	gatewayField []string
+}{
+	{uiv1alpha1.TempoStackKind, uiv1alpha1.BackendTypeTempoStack, schema.GroupVersionResource{Group: "tempo.grafana.com", Version: "v1alpha1", Resource: "tempostacks"}, []string{"spec", "template", "gateway", "enabled"}},
+	{uiv1alpha1.TempoMonolithicKind, uiv1alpha1.BackendTypeTempoMonolithic, schema.GroupVersionResource{Group: "tempo.grafana.com", Version: "v1alpha1", Resource: "tempomonolithics"}, []string{"spec", "multitenancy", "enabled"}},
+}
+
+func tempoResourceOf(kind uiv1alpha1.TempoInstanceKind) schema.GroupVersionResource {
+	for _, tempo := range tempoResources {
+		if tempo.kind == kind {
+			return tempo.resource
+		}
+	}
+	return schema.GroupVersionResource{}
+}
+
+// getTempoInstances returns the Tempo instances the plugin is restricted to.
+// The listed instances must exist.
+func getTempoInstances(ctx context.Context, client dynamic.Interface, cfg *uiv1alpha1.DistributedTracingConfig) ([]tempoInstance, error) {
+	if !restrictsTempoInstances(cfg) {
+		return nil, nil
+	}
+
+	var (
+		instances []tempoInstance
+		seen      = map[uiv1alpha1.TempoInstanceReference]struct{}{}
+	)
+	add := func(kind uiv1alpha1.TempoInstanceKind, gatewayField []string, item *unstructured.Unstructured) {
+		ref := uiv1alpha1.TempoInstanceReference{Kind: kind, Name: item.GetName(), Namespace: item.GetNamespace()}
+		if _, found := seen[ref]; found {
+			return
+		}
+		seen[ref] = struct{}{}
+		gateway, _, _ := unstructured.NestedBool(item.Object, gatewayField...)
+		instances = append(instances, tempoInstance{TempoInstanceReference: ref, gateway: gateway})
+	}
+
+	for _, ref := range cfg.Instances {
+		for _, tempo := range tempoResources {
+			if tempo.kind != ref.Kind {
+				continue
+			}
+			item, err := client.Resource(tempo.resource).Namespace(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+			if err != nil {
+				return nil, fmt.Errorf("failed to get %s %s in namespace %s: %w", ref.Kind, ref.Name, ref.Namespace, err)
+			}
+			add(tempo.kind, tempo.gatewayField, item)
+		}
+	}
+
+	if cfg.InstanceSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(cfg.InstanceSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid instance selector: %w", err)
+		}
+
+		for _, tempo := range tempoResources {
+			list, err := client.Resource(tempo.resource).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+			if err != nil {
+				// The Tempo operator may not serve both kinds.
+				if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+					continue
+				}
+				return nil, fmt.Errorf("failed to list %s instances: %w", tempo.kind, err)
+			}
+			for i := range list.Items {
+				add(tempo.kind, tempo.gatewayField, &list.Items[i])
+			}
+		}
+	}
+
+	return instances, nil
+}
+
+// getTempoBackends returns the Tempo instances of the cluster which the
+// plugin discovers by itself. The instances which can't be listed are
+// ignored since they don't prevent the plugin from working.
+func getTempoBackends(ctx context.Context, client dynamic.Interface, logger logr.Logger) []uiv1alpha1.UIPluginBackend {
+	var backends []uiv1alpha1.UIPluginBackend
+	for _, tempo := range tempoResources {
+		list, err := client.Resource(tempo.resource).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			logger.V(1).Info("failed to list Tempo instances", "resource", tempo.resource.Resource, "error", err.Error())
+			continue
+		}
+		for _, item := range list.Items {
+			backends = append(backends, uiv1alpha1.UIPluginBackend{
+				Type:      tempo.backendType,
+				Name:      item.GetName(),
+				Namespace: item.GetNamespace(),
+			})
+		}
+	}
+	return backends
+}
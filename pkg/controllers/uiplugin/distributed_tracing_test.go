@@ -0,0 +1,115 @@
+package uiplugin
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	uiv1alpha1 "github.com/rhobs/observability-operator/pkg/apis/uiplugin/v1alpha1"
+)
+
+func newTempoInstance(kind, name, namespace string, labels map[string]string, spec map[string]any) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{Object: map[string]any{}}
+	if spec != nil {
+		u.Object["spec"] = spec
+	}
+	u.SetAPIVersion("tempo.grafana.com/v1alpha1")
+	u.SetKind(kind)
+	u.SetName(name)
+	u.SetNamespace(namespace)
+	u.SetLabels(labels)
+	return u
+}
+
+func newDistributedTracingPlugin(cfg *uiv1alpha1.DistributedTracingConfig) *uiv1alpha1.UIPlugin {
+	plugin := &uiv1alpha1.UIPlugin{
+		Spec: uiv1alpha1.UIPluginSpec{
+			Type:               uiv1alpha1.TypeDistributedTracing,
+			DistributedTracing: cfg,
+		},
+	}
+	plugin.Name = "distributed-tracing"
+	return plugin
+}
+
+func TestCreateDistributedTracingPluginInfo(t *testing.T) {
+	dk := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(
+		runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{
+			tempoResourceOf(uiv1alpha1.TempoStackKind):      "TempoStackList",
+			tempoResourceOf(uiv1alpha1.TempoMonolithicKind): "TempoMonolithicList",
+		},
+		newTempoInstance("TempoStack", "platform", "tracing", nil, map[string]any{
+			"template": map[string]any{"gateway": map[string]any{"enabled": true}},
+		}),
+		newTempoInstance("TempoStack", "team-a", "team-a", map[string]string{"team": "a"}, nil),
+		newTempoInstance("TempoMonolithic", "team-a", "team-a", map[string]string{"team": "a"}, nil),
+		newTempoInstance("TempoStack", "team-b", "team-b", map[string]string{"team": "b"}, nil),
+	)
+
+	t.Run("all instances", func(t *testing.T) {
+		plugin := newDistributedTracingPlugin(nil)
+		instances, err := getTempoInstances(context.Background(), dk, plugin.Spec.DistributedTracing)
+		assert.NilError(t, err)
+		assert.Assert(t, instances == nil)
+
+		info, err := createDistributedTracingPluginInfo(plugin, "openshift-operators", plugin.Name, "quay.io/tracing:latest", nil, instances)
+		assert.NilError(t, err)
+
+		assert.Equal(t, len(info.Proxies), 1)
+		assert.Equal(t, len(info.ClusterRoles), 1)
+		assert.Equal(t, len(info.Roles), 0)
+		assert.Equal(t, info.ConfigMap.Data["config.yaml"], "")
+	})
+
+	t.Run("restricted instances", func(t *testing.T) {
+		plugin := newDistributedTracingPlugin(&uiv1alpha1.DistributedTracingConfig{
+			Instances: []uiv1alpha1.TempoInstanceReference{
+				{Kind: uiv1alpha1.TempoStackKind, Name: "platform", Namespace: "tracing"},
+			},
+			InstanceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}},
+			Timeout:          "30s",
+		})
+		instances, err := getTempoInstances(context.Background(), dk, plugin.Spec.DistributedTracing)
+		assert.NilError(t, err)
+		assert.Equal(t, len(instances), 3)
+
+		info, err := createDistributedTracingPluginInfo(plugin, "openshift-operators", plugin.Name, "quay.io/tracing:latest", nil, instances)
+		assert.NilError(t, err)
+
+		assert.Equal(t, len(info.ClusterRoles), 0)
+		assert.Equal(t, len(info.ClusterRoleBindings), 0)
+
+		assert.Equal(t, len(info.Proxies), 1)
+		assert.Equal(t, len(info.Backends), 3)
+		assert.Equal(t, info.Backends[0].URL, "https://tempo-platform-gateway.tracing.svc:8080")
+		assert.Equal(t, info.Backends[1].URL, "https://tempo-team-a-query-frontend.team-a.svc:3200")
+		assert.Equal(t, info.Backends[2].URL, "https://tempo-team-a.team-a.svc:3200")
+
+		assert.Equal(t, len(info.Roles), 2)
+		assert.Equal(t, len(info.RoleBindings), 2)
+		assert.DeepEqual(t, info.Roles[1].Rules, []rbacv1.PolicyRule{
+			{APIGroups: []string{"tempo.grafana.com"}, Resources: []string{"tempostacks"}, Verbs: []string{"get", "list"}},
+			{APIGroups: []string{"tempo.grafana.com"}, Resources: []string{"tempomonolithics"}, Verbs: []string{"get", "list"}},
+		})
+		assert.Equal(t, info.RoleBindings[1].Subjects[0].Name, "distributed-tracing-sa")
+
+		assert.Equal(t, info.ConfigMap.Data["config.yaml"], "timeout: 30s\n")
+	})
+
+	t.Run("missing instance", func(t *testing.T) {
+		_, err := getTempoInstances(context.Background(), dk, &uiv1alpha1.DistributedTracingConfig{
+			Instances: []uiv1alpha1.TempoInstanceReference{
+				{Kind: uiv1alpha1.TempoMonolithicKind, Name: "platform", Namespace: "tracing"},
+			},
+		})
+		assert.ErrorContains(t, err, "failed to get TempoMonolithic platform in namespace tracing")
+	})
+}
@@ -0,0 +1,36 @@
+package uiplugin
+
+import (
+	"slices"
+
+	uiv1alpha1 "github.com/rhobs/observability-operator/pkg/apis/uiplugin/v1alpha1"
+)
+
+// pluginFeatures applies the feature overrides of the plugin to the features
+// derived by the operator from the compatibility matrix and the plugin
+// configuration. Only the features of the compatibility entry, listed in
+// supported, and the derived features can be enabled. The other features
+// requested by the plugin are returned as unsupported.
+func pluginFeatures(plugin *uiv1alpha1.UIPlugin, supported, derived []string) ([]string, []string) {
+	features := slices.Clone(derived)
+	overrides := plugin.Spec.Features
+	if overrides == nil {
+		return features, nil
+	}
+
+	var unsupported []string
+	for _, f := range overrides.Enabled {
+		switch {
+		case slices.Contains(features, f):
+		case slices.Contains(supported, f):
+			features = append(features, f)
+		default:
+			unsupported = append(unsupported, f)
+		}
+	}
+
+	features = slices.DeleteFunc(features, func(f string) bool {
+		return slices.Contains(overrides.Disabled, f)
+	})
+	return features, unsupported
+}
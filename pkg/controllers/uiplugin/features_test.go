@@ -0,0 +1,88 @@
+package uiplugin
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	uiv1alpha1 "github.com/rhobs/observability-operator/pkg/apis/uiplugin/v1alpha1"
+)
+
+func TestPluginFeatures(t *testing.T) {
+	supported := []string{"dev-console", "alerts", "dev-alerts"}
+	derived := []string{"dev-console"}
+
+	for _, tc := range []struct {
+		name        string
+		overrides   *uiv1alpha1.UIPluginFeatures
+		features    []string
+		unsupported []string
+	}{
+		{
+			name:     "no overrides",
+			features: []string{"dev-console"},
+		},
+		{
+			name:      "enable supported feature",
+			overrides: &uiv1alpha1.UIPluginFeatures{Enabled: []string{"alerts", "dev-console"}},
+			features:  []string{"dev-console", "alerts"},
+		},
+		{
+			name:        "enable unsupported feature",
+			overrides:   &uiv1alpha1.UIPluginFeatures{Enabled: []string{"agent-navigation"}},
+			features:    []string{"dev-console"},
+			unsupported: []string{"agent-navigation"},
+		},
+		{
+			name:      "disable feature",
+			overrides: &uiv1alpha1.UIPluginFeatures{Disabled: []string{"dev-console"}},
+			features:  []string{},
+		},
+		{
+			name: "disabled takes precedence",
+			overrides: &uiv1alpha1.UIPluginFeatures{
+				Enabled:  []string{"alerts", "dev-alerts"},
+				Disabled: []string{"alerts"},
+			},
+			features: []string{"dev-console", "dev-alerts"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			plugin := &uiv1alpha1.UIPlugin{Spec: uiv1alpha1.UIPluginSpec{Features: tc.overrides}}
+
+			features, unsupported := pluginFeatures(plugin, supported, derived)
+			assert.DeepEqual(t, features, tc.features)
+			assert.DeepEqual(t, unsupported, tc.unsupported)
+		})
+	}
+
+	// The derived features aren't modified.
+	assert.DeepEqual(t, derived, []string{"dev-console"})
+}
+
+func TestMonitoringPluginFeatures(t *testing.T) {
+	plugin := newMonitoringPlugin(&uiv1alpha1.MonitoringConfig{
+		Perses: &uiv1alpha1.PersesReference{Enabled: true},
+	})
+	plugin.Spec.Features = &uiv1alpha1.UIPluginFeatures{
+		Enabled:  []string{"acm-alerting"},
+		Disabled: []string{"mcp-overview"},
+	}
+
+	info, err := getPluginInfo(plugin, []string{}, "v4.19")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, info.Features, []string{"perses-dashboards"})
+	assert.DeepEqual(t, info.UnsupportedFeatures, []string{"acm-alerting"})
+	assert.Equal(t, info.ExtraArgs[len(info.ExtraArgs)-1], "-features=perses-dashboards")
+	assert.Equal(t, info.PersesImage, persesImage)
+
+	// The components of the disabled features aren't deployed.
+	plugin.Spec.Features = &uiv1alpha1.UIPluginFeatures{Disabled: []string{"perses-dashboards"}}
+	info, err = getPluginInfo(plugin, []string{}, "v4.19")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, info.Features, []string{"mcp-overview"})
+	assert.Equal(t, info.PersesImage, "")
+	for _, proxy := range info.Proxies {
+		assert.Assert(t, proxy.Alias != "perses")
+	}
+}
@@ -0,0 +1,551 @@
+package uiplugin
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/perses/perses/go-sdk/dashboard"
+	"github.com/perses/perses/go-sdk/panel"
+	panelgroup "github.com/perses/perses/go-sdk/panel-group"
+	listvariable "github.com/perses/perses/go-sdk/variable/list-variable"
+	textvariable "github.com/perses/perses/go-sdk/variable/text-variable"
+	"github.com/perses/plugins/prometheus/sdk/go/query"
+	labelvalues "github.com/perses/plugins/prometheus/sdk/go/variable/label-values"
+	table "github.com/perses/plugins/table/sdk/go"
+	timeseries "github.com/perses/plugins/timeserieschart/sdk/go"
+	persescommon "github.com/perses/spec/go/common"
+	persesdashboard "github.com/perses/spec/go/dashboard"
+	"github.com/perses/spec/go/plugin"
+	persesv1alpha2 "github.com/rhobs/perses-operator/api/v1alpha2"
+	persesv1 "github.com/rhobs/perses/pkg/model/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	uiv1alpha1 "github.com/rhobs/observability-operator/pkg/apis/uiplugin/v1alpha1"
+)
+
+var (
+	// grafanaLabelValuesRegexp matches the label_values() queries of the
+	// Prometheus datasource: label_values(label) and
+	// label_values(selector, label).
+	grafanaLabelValuesRegexp = regexp.MustCompile(`^\s*label_values\(\s*(?:(.+?)\s*,\s*)?([a-zA-Z_][a-zA-Z0-9_]*)\s*\)\s*$`)
+
+	invalidPersesNameCharsRegexp = regexp.MustCompile(`[^a-z0-9.-]+`)
+)
+
+// grafanaDashboard is the subset of the JSON model of Grafana dashboards
+// which is converted into Perses dashboards.
+type grafanaDashboard struct {
+	UID         string `json:"uid"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Time        struct {
+		From string `json:"from"`
+		To   string `json:"to"`
+	} `json:"time"`
+	// Refresh is either a duration or false.
+	Refresh    json.RawMessage   `json:"refresh"`
+	Panels     []grafanaPanel    `json:"panels"`
+	Rows       []json.RawMessage `json:"rows"`
+	Templating struct {
+		List []grafanaVariable `json:"list"`
+	} `json:"templating"`
+}
+
+type grafanaPanel struct {
+	Type        string `json:"type"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	GridPos     struct {
+		X int `json:"x"`
+		Y int `json:"y"`
+		W int `json:"w"`
+		H int `json:"h"`
+	} `json:"gridPos"`
+	Targets []grafanaTarget `json:"targets"`
+	Options struct {
+		ReduceOptions struct {
+			Calcs []string `json:"calcs"`
+		} `json:"reduceOptions"`
+	} `json:"options"`
+	FieldConfig struct {
+		Defaults grafanaFieldDefaults `json:"defaults"`
+	} `json:"fieldConfig"`
+	// Collapsed and Panels are only set for rows. The panels of collapsed
+	// rows are nested in the row.
+	Collapsed bool           `json:"collapsed"`
+	Panels    []grafanaPanel `json:"panels"`
+}
+
+// grafanaFieldDefaults is the default configuration of the fields displayed
+// by the stat, gauge and bar chart panels.
+type grafanaFieldDefaults struct {
+	Unit       string   `json:"unit"`
+	Decimals   *int     `json:"decimals"`
+	Max        *float64 `json:"max"`
+	Thresholds *struct {
+		Mode  string `json:"mode"`
+		Steps []struct {
+			Color string `json:"color"`
+			// Value is null for the base step.
+			Value *float64 `json:"value"`
+		} `json:"steps"`
+	} `json:"thresholds"`
+}
+
+type grafanaTarget struct {
+	RefID        string `json:"refId"`
+	Expr         string `json:"expr"`
+	LegendFormat string `json:"legendFormat"`
+	Hide         bool   `json:"hide"`
+}
+
+type grafanaVariable struct {
+	Type        string `json:"type"`
+	Name        string `json:"name"`
+	Label       string `json:"label"`
+	Description string `json:"description"`
+	// Hide is 1 to hide the label and 2 to hide the variable.
+	Hide       int    `json:"hide"`
+	Multi      bool   `json:"multi"`
+	IncludeAll bool   `json:"includeAll"`
+	AllValue   string `json:"allValue"`
+	Regex      string `json:"regex"`
+	Definition string `json:"definition"`
+	// Query is either a string or an object holding the query.
+	Query json.RawMessage `json:"query"`
+}
+
+// query returns the query of the variable or its value for constant and
+// textbox variables.
+func (v grafanaVariable) query() string {
+	var s string
+	if err := json.Unmarshal(v.Query, &s); err == nil {
+		return s
+	}
+
+	var q struct {
+		Query string `json:"query"`
+	}
+	if err := json.Unmarshal(v.Query, &q); err == nil && q.Query != "" {
+		return q.Query
+	}
+
+	return v.Definition
+}
+
+// grafanaConverter converts a Grafana dashboard and collects the warnings
+// about the parts which can't be converted and the skipped panels.
+type grafanaConverter struct {
+	warnings      []string
+	skippedPanels []string
+}
+
+func (c *grafanaConverter) warnf(format string, args ...any) {
+	c.warnings = append(c.warnings, fmt.Sprintf(format, args...))
+}
+
+// convertGrafanaDashboard converts a Grafana dashboard into a Perses
+// dashboard named name. Only the Prometheus queries of the time series,
+// table, stat, gauge and bar chart panels are converted. The other panels
+// are skipped, they are reported by the returned converter along with the
+// variables which aren't label_values() queries.
+func convertGrafanaDashboard(name string, g *grafanaDashboard) (*persesv1.Dashboard, *grafanaConverter, error) {
+	c := &grafanaConverter{}
+	options := c.timeOptions(g)
+	for _, v := range g.Templating.List {
+		if opt := c.variable(v); opt != nil {
+			options = append(options, opt)
+		}
+	}
+	options = append(options, c.panelGroups(g)...)
+
+	builder, err := dashboard.New(name, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Workaround because of type conflict between Perses plugin types and Perses fork in rhobs org
+	d := &persesv1.Dashboard{}
+	bytes, err := json.Marshal(builder.Dashboard)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := d.UnmarshalJSON(bytes); err != nil {
+		return nil, nil, err
+	}
+
+	// The display is set after the conversion since the SDK uses titles which
+	// are valid identifiers as the name of the dashboard.
+	if g.Title != "" || g.Description != "" {
+		d.Spec.Display = &persescommon.Display{
+			Name:        g.Title,
+			Description: g.Description,
+		}
+	}
+
+	// The panels which precede the first row are displayed without header.
+	for _, layout := range d.Spec.Layouts {
+		if grid, ok := layout.Spec.(*persesdashboard.GridLayoutSpec); ok && grid.Display != nil && grid.Display.Title == "" {
+			grid.Display = nil
+		}
+	}
+
+	return d, c, nil
+}
+
+// timeOptions converts the default time range and refresh interval. Only
+// time ranges relative to now are supported.
+func (c *grafanaConverter) timeOptions(g *grafanaDashboard) []dashboard.Option {
+	var options []dashboard.Option
+
+	if duration, found := strings.CutPrefix(g.Time.From, "now-"); found && g.Time.To == "now" {
+		options = append(options, dashboard.DurationAsString(duration))
+	} else if g.Time.From != "" {
+		c.warnf("time range from %q to %q isn't supported", g.Time.From, g.Time.To)
+	}
+
+	var refresh string
+	if err := json.Unmarshal(g.Refresh, &refresh); err == nil && refresh != "" {
+		options = append(options, dashboard.RefreshIntervalAsString(refresh))
+	}
+
+	return options
+}
+
+// variable converts a Grafana variable into a Perses variable. It returns
+// nil when the variable isn't converted.
+func (c *grafanaConverter) variable(v grafanaVariable) dashboard.Option {
+	switch v.Type {
+	case "query":
+		m := grafanaLabelValuesRegexp.FindStringSubmatch(v.query())
+		if m == nil {
+			c.warnf("variable %q: only label_values() queries are supported", v.Name)
+			return nil
+		}
+
+		options := []listvariable.Option{
+			listvariable.AllowMultiple(v.Multi),
+			listvariable.AllowAllValue(v.IncludeAll),
+		}
+		if v.Label != "" {
+			options = append(options, listvariable.DisplayName(v.Label))
+		}
+		if v.Description != "" {
+			options = append(options, listvariable.Description(v.Description))
+		}
+		if v.IncludeAll && v.AllValue != "" {
+			options = append(options, listvariable.CustomAllValue(v.AllValue))
+		}
+		if regex := strings.TrimSuffix(strings.TrimPrefix(v.Regex, "/"), "/"); regex != "" {
+			options = append(options, listvariable.CapturingRegexp(regex))
+		}
+		if v.Hide == 2 {
+			options = append(options, listvariable.Hidden(true))
+		}
+
+		selector, label := m[1], m[2]
+		if selector != "" {
+			options = append(options, labelvalues.PrometheusLabelValues(label, labelvalues.Matchers(selector)))
+		} else {
+			options = append(options, labelvalues.PrometheusLabelValues(label))
+		}
+
+		return dashboard.AddVariable(v.Name, listvariable.List(options...))
+
+	case "constant":
+		return dashboard.AddVariable(v.Name, textvariable.Text(v.query(),
+			textvariable.Constant(true),
+			textvariable.Hidden(true),
+		))
+
+	case "textbox":
+		options := []textvariable.Option{}
+		if v.Label != "" {
+			options = append(options, textvariable.DisplayName(v.Label))
+		}
+		if v.Hide == 2 {
+			options = append(options, textvariable.Hidden(true))
+		}
+		return dashboard.AddVariable(v.Name, textvariable.Text(v.query(), options...))
+
+	case "datasource":
+		// The queries use the default Prometheus datasource of the project.
+		return nil
+
+	default:
+		c.warnf("variable %q of type %s isn't supported", v.Name, v.Type)
+		return nil
+	}
+}
+
+// grafanaRow groups the panels between two Grafana rows.
+type grafanaRow struct {
+	title     string
+	collapsed bool
+	// y is the vertical position of the first panel of the row.
+	y      int
+	panels []grafanaPanel
+}
+
+// panelGroups converts the Grafana rows into Perses panel groups. The panels
+// which precede the first row are grouped in a panel group without title.
+func (c *grafanaConverter) panelGroups(g *grafanaDashboard) []dashboard.Option {
+	if len(g.Rows) > 0 {
+		c.warnf("the rows of dashboards older than Grafana 5 aren't supported")
+	}
+
+	rows := []*grafanaRow{{}}
+	for _, p := range g.Panels {
+		if p.Type != "row" {
+			row := rows[len(rows)-1]
+			row.panels = append(row.panels, p)
+			continue
+		}
+
+		rows = append(rows, &grafanaRow{
+			title:     p.Title,
+			collapsed: p.Collapsed,
+			y:         p.GridPos.Y + 1,
+			// The panels of collapsed rows are nested in the row.
+			panels: p.Panels,
+		})
+	}
+
+	var options []dashboard.Option
+	for _, row := range rows {
+		var (
+			positions   []dashboard.GridItem
+			groupOption []panelgroup.Option
+		)
+		for _, p := range row.panels {
+			panelOptions := c.panel(p)
+			if panelOptions == nil {
+				continue
+			}
+
+			positions = append(positions, dashboard.GridItem{
+				X: p.GridPos.X,
+				Y: max(p.GridPos.Y-row.y, 0),
+				W: p.GridPos.W,
+				H: p.GridPos.H,
+			})
+			groupOption = append(groupOption, panelgroup.AddPanel(p.Title, panelOptions...))
+		}
+
+		if len(positions) == 0 {
+			continue
+		}
+		if row.collapsed {
+			groupOption = append(groupOption, panelgroup.Collapsed(true))
+		}
+		options = append(options, dashboard.AddCustomPanelGroup(row.title, positions, groupOption...))
+	}
+
+	return options
+}
+
+// panel converts a Grafana panel into the options of a Perses panel. It
+// returns nil when the panel isn't converted.
+func (c *grafanaConverter) panel(p grafanaPanel) []panel.Option {
+	var options []panel.Option
+	switch p.Type {
+	case "timeseries", "graph":
+		options = append(options, timeseries.Chart())
+	case "table":
+		options = append(options, table.Table())
+	case "stat":
+		options = append(options, panel.Plugin(c.chartPlugin("StatChart", p, true)))
+	case "gauge":
+		options = append(options, panel.Plugin(c.chartPlugin("GaugeChart", p, true)))
+	case "barchart":
+		options = append(options, panel.Plugin(c.chartPlugin("BarChart", p, false)))
+	default:
+		c.warnf("panel %q of type %s isn't supported", p.Title, p.Type)
+		c.skippedPanels = append(c.skippedPanels, p.Title)
+		return nil
+	}
+
+	if p.Description != "" {
+		options = append(options, panel.Description(p.Description))
+	}
+
+	for _, t := range p.Targets {
+		if t.Hide {
+			continue
+		}
+		if t.Expr == "" {
+			c.warnf("panel %q: query %s isn't a PromQL query", p.Title, t.RefID)
+			continue
+		}
+
+		// Grafana and Perses share the {{label}} syntax of legends.
+		if t.LegendFormat != "" && t.LegendFormat != "__auto" {
+			options = append(options, panel.AddQuery(query.PromQL(t.Expr, query.SeriesNameFormat(t.LegendFormat))))
+		} else {
+			options = append(options, panel.AddQuery(query.PromQL(t.Expr)))
+		}
+	}
+
+	return options
+}
+
+// grafanaCalculations maps the Grafana reducers to the Perses calculations.
+var grafanaCalculations = map[string]string{
+	"first":        "first",
+	"firstNotNull": "first-number",
+	"last":         "last",
+	"lastNotNull":  "last-number",
+	"mean":         "mean",
+	"sum":          "sum",
+	"min":          "min",
+	"max":          "max",
+}
+
+// grafanaUnits maps the Grafana units to the Perses units.
+var grafanaUnits = map[string]string{
+	"none":        "decimal",
+	"short":       "decimal",
+	"percent":     "percent",
+	"percentunit": "percent-decimal",
+	"bytes":       "bytes",
+	"decbytes":    "decbytes",
+	"ms":          "milliseconds",
+	"s":           "seconds",
+	"m":           "minutes",
+	"h":           "hours",
+	"d":           "days",
+}
+
+// persesChartSpec is the spec shared by the StatChart, GaugeChart and
+// BarChart panel plugins of Perses.
+type persesChartSpec struct {
+	Calculation string                 `json:"calculation"`
+	Format      *persesChartFormat     `json:"format,omitempty"`
+	Thresholds  *persesChartThresholds `json:"thresholds,omitempty"`
+	// Max is only supported by the GaugeChart.
+	Max *float64 `json:"max,omitempty"`
+}
+
+type persesChartFormat struct {
+	Unit          string `json:"unit,omitempty"`
+	DecimalPlaces *int   `json:"decimalPlaces,omitempty"`
+}
+
+type persesChartThresholds struct {
+	Mode  string                     `json:"mode,omitempty"`
+	Steps []persesChartThresholdStep `json:"steps,omitempty"`
+}
+
+type persesChartThresholdStep struct {
+	Value float64 `json:"value"`
+	Color string  `json:"color,omitempty"`
+}
+
+// chartPlugin converts the reducer, the unit and the thresholds of a stat,
+// gauge or bar chart panel into the Perses panel plugin of the given kind.
+// The thresholds are only converted when thresholds is true.
+func (c *grafanaConverter) chartPlugin(kind string, p grafanaPanel, thresholds bool) plugin.Plugin {
+	spec := persesChartSpec{Calculation: "last-number"}
+
+	if calcs := p.Options.ReduceOptions.Calcs; len(calcs) > 0 {
+		if calculation, found := grafanaCalculations[calcs[0]]; found {
+			spec.Calculation = calculation
+		} else {
+			c.warnf("panel %q: reducer %s isn't supported", p.Title, calcs[0])
+		}
+	}
+
+	defaults := p.FieldConfig.Defaults
+	unit, found := grafanaUnits[defaults.Unit]
+	if !found && defaults.Unit != "" {
+		c.warnf("panel %q: unit %s isn't supported", p.Title, defaults.Unit)
+	}
+	if unit != "" || defaults.Decimals != nil {
+		spec.Format = &persesChartFormat{Unit: unit, DecimalPlaces: defaults.Decimals}
+	}
+
+	if thresholds && defaults.Thresholds != nil {
+		spec.Thresholds = &persesChartThresholds{}
+		if defaults.Thresholds.Mode == "percentage" {
+			spec.Thresholds.Mode = "percent"
+		}
+		for _, step := range defaults.Thresholds.Steps {
+			// The base step is the default color of Perses.
+			if step.Value == nil {
+				continue
+			}
+			s := persesChartThresholdStep{Value: *step.Value}
+			// Perses only supports the hexadecimal colors.
+			if strings.HasPrefix(step.Color, "#") {
+				s.Color = step.Color
+			}
+			spec.Thresholds.Steps = append(spec.Thresholds.Steps, s)
+		}
+	}
+
+	if kind == "GaugeChart" {
+		spec.Max = defaults.Max
+	}
+
+	return plugin.Plugin{Kind: kind, Spec: spec}
+}
+
+// grafanaDashboardName returns the name of the Perses dashboard converted
+// from a Grafana dashboard: its UID, or its title when it has no UID,
+// turned into a valid resource name.
+func grafanaDashboardName(g *grafanaDashboard) string {
+	name := g.UID
+	if name == "" {
+		name = g.Title
+	}
+
+	name = invalidPersesNameCharsRegexp.ReplaceAllString(strings.ToLower(name), "-")
+	if len(name) > 63 {
+		name = name[:63]
+	}
+	return strings.Trim(name, "-.")
+}
+
+// newPersesDashboardFromGrafana converts the Grafana dashboard held by the
+// key of the ConfigMap. The parts which can't be converted are reported in
+// status.
+func newPersesDashboardFromGrafana(cm *corev1.ConfigMap, key string, status *uiv1alpha1.PersesDefinitionStatus) (*persesv1alpha2.PersesDashboard, error) {
+	g := &grafanaDashboard{}
+	if err := json.Unmarshal([]byte(cm.Data[key]), g); err != nil {
+		return nil, fmt.Errorf("invalid Grafana dashboard: %w", err)
+	}
+
+	name := grafanaDashboardName(g)
+	if err := validatePersesDefinitionName(name); err != nil {
+		return nil, err
+	}
+
+	d, c, err := convertGrafanaDashboard(name, g)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert the Grafana dashboard: %w", err)
+	}
+	status.Warnings = c.warnings
+	status.SkippedPanels = c.skippedPanels
+
+	return &persesv1alpha2.PersesDashboard{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: persesv1alpha2.GroupVersion.String(),
+			Kind:       persesDashboardKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: cm.Namespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/managed-by": "observability-operator",
+			},
+		},
+		Spec: persesv1alpha2.PersesDashboardSpec{
+			Config: persesv1alpha2.Dashboard{
+				Spec: d.Spec,
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,247 @@
+package uiplugin
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	persesdashboard "github.com/perses/spec/go/dashboard"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	uiv1alpha1 "github.com/rhobs/observability-operator/pkg/apis/uiplugin/v1alpha1"
+)
+
+const testGrafanaDashboard = `{
+  "uid": "Team-A_Overview",
+  "title": "Team A overview",
+  "time": {"from": "now-6h", "to": "now"},
+  "refresh": "30s",
+  "templating": {
+    "list": [
+      {"type": "datasource", "name": "datasource", "query": "prometheus"},
+      {
+        "type": "query",
+        "name": "instance",
+        "label": "Instance",
+        "multi": true,
+        "includeAll": true,
+        "query": {"qryType": 1, "query": "label_values(up{job=~\"team-a|team-b\", namespace=\"team-a\"}, instance)"}
+      },
+      {"type": "query", "name": "job", "query": "query_result(up)"},
+      {"type": "custom", "name": "quantile", "query": "0.5,0.9"}
+    ]
+  },
+  "panels": [
+    {
+      "type": "timeseries",
+      "title": "Requests",
+      "gridPos": {"x": 0, "y": 0, "w": 12, "h": 8},
+      "targets": [
+        {"refId": "A", "expr": "sum(rate(http_requests_total{instance=~\"$instance\"}[$__rate_interval]))", "legendFormat": "__auto"},
+        {"refId": "B", "expr": "sum by (code) (rate(http_requests_total[5m]))", "legendFormat": "{{code}}"},
+        {"refId": "C", "datasource": {"type": "loki"}}
+      ]
+    },
+    {
+      "type": "stat",
+      "title": "Up",
+      "gridPos": {"x": 12, "y": 0, "w": 6, "h": 8},
+      "targets": [{"refId": "A", "expr": "sum(up)"}],
+      "options": {"reduceOptions": {"calcs": ["mean"]}},
+      "fieldConfig": {
+        "defaults": {
+          "unit": "percentunit",
+          "decimals": 1,
+          "thresholds": {
+            "mode": "absolute",
+            "steps": [{"color": "green", "value": null}, {"color": "#F2495C", "value": 0.5}, {"color": "red", "value": 0.9}]
+          }
+        }
+      }
+    },
+    {"type": "text", "title": "Notes", "gridPos": {"x": 18, "y": 0, "w": 6, "h": 8}},
+    {
+      "type": "row",
+      "title": "Details",
+      "collapsed": true,
+      "gridPos": {"x": 0, "y": 8, "w": 24, "h": 1},
+      "panels": [
+        {
+          "type": "table",
+          "title": "Instances",
+          "gridPos": {"x": 0, "y": 10, "w": 24, "h": 6},
+          "targets": [{"refId": "A", "expr": "up"}]
+        }
+      ]
+    }
+  ]
+}`
+
+func TestConvertGrafanaDashboard(t *testing.T) {
+	g := &grafanaDashboard{}
+	assert.NilError(t, json.Unmarshal([]byte(testGrafanaDashboard), g))
+
+	dashboard, c, err := convertGrafanaDashboard("team-a-overview", g)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, c.warnings, []string{
+		`variable "job": only label_values() queries are supported`,
+		`variable "quantile" of type custom isn't supported`,
+		`panel "Requests": query C isn't a PromQL query`,
+		`panel "Notes" of type text isn't supported`,
+	})
+	assert.DeepEqual(t, c.skippedPanels, []string{"Notes"})
+
+	assert.Equal(t, dashboard.Metadata.Name, "team-a-overview")
+
+	spec := dashboard.Spec
+	assert.Equal(t, spec.Display.Name, "Team A overview")
+	assert.Equal(t, string(spec.Duration), "6h")
+	assert.Equal(t, string(spec.RefreshInterval), "30s")
+
+	assert.Equal(t, len(spec.Variables), 1)
+	variable, ok := spec.Variables[0].Spec.(*persesdashboard.ListVariableSpec)
+	assert.Assert(t, ok)
+	assert.Equal(t, variable.Name, "instance")
+	assert.Equal(t, variable.Display.Name, "Instance")
+	assert.Assert(t, variable.AllowMultiple)
+	assert.Assert(t, variable.AllowAllValue)
+	assert.Equal(t, variable.Plugin.Kind, "PrometheusLabelValuesVariable")
+
+	assert.Equal(t, len(spec.Panels), 3)
+	assert.Equal(t, spec.Panels["0_0"].Spec.Plugin.Kind, "TimeSeriesChart")
+	assert.Equal(t, len(spec.Panels["0_0"].Spec.Queries), 2)
+	assert.Equal(t, spec.Panels["0_1"].Spec.Plugin.Kind, "StatChart")
+	assert.Equal(t, len(spec.Panels["0_1"].Spec.Queries), 1)
+	stat, err := json.Marshal(spec.Panels["0_1"].Spec.Plugin.Spec)
+	assert.NilError(t, err)
+	assert.Equal(t, string(stat), `{"calculation":"mean","format":{"unit":"percent-decimal","decimalPlaces":1},"thresholds":{"steps":[{"value":0.5,"color":"#F2495C"},{"value":0.9}]}}`)
+	assert.Equal(t, spec.Panels["1_0"].Spec.Plugin.Kind, "Table")
+
+	assert.Equal(t, len(spec.Layouts), 2)
+	untitled, ok := spec.Layouts[0].Spec.(*persesdashboard.GridLayoutSpec)
+	assert.Assert(t, ok)
+	assert.Assert(t, untitled.Display == nil)
+	details, ok := spec.Layouts[1].Spec.(*persesdashboard.GridLayoutSpec)
+	assert.Assert(t, ok)
+	assert.Equal(t, details.Display.Title, "Details")
+	assert.Assert(t, !details.Display.Collapse.Open)
+	// The positions are relative to the row.
+	assert.Equal(t, details.Items[0].Y, 1)
+	assert.Equal(t, details.Items[0].Width, 24)
+	assert.Equal(t, details.Items[0].Height, 6)
+}
+
+func TestConvertGrafanaChartPanels(t *testing.T) {
+	g := &grafanaDashboard{}
+	assert.NilError(t, json.Unmarshal([]byte(`{
+  "title": "Charts",
+  "panels": [
+    {
+      "type": "gauge",
+      "title": "Usage",
+      "gridPos": {"x": 0, "y": 0, "w": 12, "h": 8},
+      "options": {"reduceOptions": {"calcs": ["lastNotNull"]}},
+      "fieldConfig": {"defaults": {"unit": "bytes", "max": 100, "thresholds": {"mode": "percentage", "steps": [{"color": "#73BF69", "value": 80}]}}}
+    },
+    {
+      "type": "barchart",
+      "title": "Reconciliations",
+      "gridPos": {"x": 12, "y": 0, "w": 12, "h": 8},
+      "options": {"reduceOptions": {"calcs": ["delta"]}},
+      "fieldConfig": {"defaults": {"unit": "reqps"}}
+    }
+  ]
+}`), g))
+
+	dashboard, c, err := convertGrafanaDashboard("charts", g)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, c.warnings, []string{
+		`panel "Reconciliations": reducer delta isn't supported`,
+		`panel "Reconciliations": unit reqps isn't supported`,
+	})
+	assert.Equal(t, len(c.skippedPanels), 0)
+
+	for key, expected := range map[string]struct {
+		kind string
+		spec string
+	}{
+		"0_0": {
+			kind: "GaugeChart",
+			spec: `{"calculation":"last-number","format":{"unit":"bytes"},"thresholds":{"mode":"percent","steps":[{"value":80,"color":"#73BF69"}]},"max":100}`,
+		},
+		"0_1": {
+			kind: "BarChart",
+			spec: `{"calculation":"last-number"}`,
+		},
+	} {
+		p, found := dashboard.Spec.Panels[key]
+		assert.Assert(t, found, key)
+		assert.Equal(t, p.Spec.Plugin.Kind, expected.kind)
+		spec, err := json.Marshal(p.Spec.Plugin.Spec)
+		assert.NilError(t, err)
+		assert.Equal(t, string(spec), expected.spec)
+	}
+}
+
+func TestGrafanaDashboardName(t *testing.T) {
+	for _, tc := range []struct {
+		dashboard grafanaDashboard
+		expected  string
+	}{
+		{
+			dashboard: grafanaDashboard{UID: "b5a313e7-bbd7-4aa5-aa8c-a890708451c5", Title: "Overview"},
+			expected:  "b5a313e7-bbd7-4aa5-aa8c-a890708451c5",
+		},
+		{
+			dashboard: grafanaDashboard{Title: "Cluster observability operator - Overview"},
+			expected:  "cluster-observability-operator-overview",
+		},
+		{
+			dashboard: grafanaDashboard{UID: "_Team_A_"},
+			expected:  "team-a",
+		},
+		{
+			dashboard: grafanaDashboard{},
+			expected:  "",
+		},
+	} {
+		t.Run(tc.expected, func(t *testing.T) {
+			assert.Equal(t, grafanaDashboardName(&tc.dashboard), tc.expected)
+		})
+	}
+}
+
+func TestLoadGrafanaDashboards(t *testing.T) {
+	k := fake.NewClientBuilder().WithObjects(
+		newDefinitionsConfigMap("grafana", "team-a", map[string]string{"grafana_dashboard": "true"}, map[string]string{
+			"overview.json": testGrafanaDashboard,
+			"invalid.json":  `{"title": "Invalid", "time": {"from": "now-1x", "to": "now"}}`,
+		}),
+	).Build()
+
+	plugin := newMonitoringPlugin(&uiv1alpha1.MonitoringConfig{
+		Perses: &uiv1alpha1.PersesReference{
+			Enabled: true,
+			GrafanaDashboards: &uiv1alpha1.PersesDefinitionsReference{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"grafana_dashboard": "true"}},
+			},
+		},
+	})
+
+	definitions, err := loadPersesDefinitions(context.Background(), k, plugin)
+	assert.NilError(t, err)
+
+	assert.Equal(t, len(definitions.dashboards), 1)
+	assert.Equal(t, definitions.dashboards[0].Name, "team-a-overview")
+	assert.Equal(t, definitions.dashboards[0].Namespace, "team-a")
+
+	assert.Equal(t, len(definitions.status), 2)
+	assert.Equal(t, definitions.status[0].Key, "invalid.json")
+	assert.Assert(t, strings.Contains(definitions.status[0].Error, `invalid duration "1x"`), definitions.status[0].Error)
+	assert.Equal(t, definitions.status[1].Name, "team-a-overview")
+	assert.Equal(t, len(definitions.status[1].Warnings), 4)
+	assert.DeepEqual(t, definitions.status[1].SkippedPanels, []string{"Notes"})
+}
@@ -3,6 +3,7 @@ package uiplugin
 import (
 	_ "embed"
 	"fmt"
+	"slices"
 	"strings"
 
 	monv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
@@ -145,6 +146,10 @@ func newHealthAnalyzerDeployment(namespace string,
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
 					Labels: componentLabels(name),
+					// Restart the analyzer when its component tree changes.
+					Annotations: map[string]string{
+						annotationPrefix + "config-hash": computeConfigMapHash(newComponentHealthConfig(namespace, pluginInfo.HealthAnalyzerComponents)),
+					},
 				},
 				Spec: corev1.PodSpec{
 					ServiceAccountName:           serviceAccountName,
@@ -221,9 +226,80 @@ func newHealthAnalyzerDeployment(namespace string,
 			},
 		},
 	}
+	if target := pluginInfo.HealthAnalyzerTarget; target != nil {
+		setHealthAnalyzerTarget(deploy, namespace, target)
+	}
+
 	return deploy
 }
 
+// removeHealthAnalyzerServingCert removes the serving certificate issued by
+// the service CA which only exists on OpenShift. The analyzer then serves a
+// self-signed certificate.
+func removeHealthAnalyzerServingCert(deploy *appsv1.Deployment) {
+	container := &deploy.Spec.Template.Spec.Containers[0]
+	container.Args = slices.DeleteFunc(container.Args, func(arg string) bool {
+		return strings.HasPrefix(arg, "--tls-cert-file=") || strings.HasPrefix(arg, "--tls-private-key-file=")
+	})
+	container.VolumeMounts = slices.DeleteFunc(container.VolumeMounts, func(mount corev1.VolumeMount) bool {
+		return mount.Name == volumeMountName
+	})
+	deploy.Spec.Template.Spec.Volumes = slices.DeleteFunc(deploy.Spec.Template.Spec.Volumes, func(volume corev1.Volume) bool {
+		return volume.Name == volumeMountName
+	})
+}
+
+// setHealthAnalyzerTarget points the health analyzer to the Prometheus and
+// Alertmanager of its target instead of the platform monitoring. The CA
+// certificates of the target replace the system ones since the analyzer
+// only connects to the target besides the Kubernetes API. The other
+// environment variables of the analyzer are kept.
+func setHealthAnalyzerTarget(deploy *appsv1.Deployment, namespace string, target *healthAnalyzerTarget) {
+	container := &deploy.Spec.Template.Spec.Containers[0]
+	setEnvVar(container, "PROM_URL", target.prometheusURL)
+	// The analyzer doesn't query the platform Alertmanager when the target
+	// has none.
+	setEnvVar(container, "ALERTMANAGER_URL", target.alertmanagerURL)
+
+	if target.caBundle == "" {
+		return
+	}
+
+	setEnvVar(container, "SSL_CERT_FILE", healthAnalyzerTargetCAMountPath+"/"+healthAnalyzerTargetCAKey)
+	container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+		Name:      healthAnalyzerTargetCAName,
+		MountPath: healthAnalyzerTargetCAMountPath,
+		ReadOnly:  true,
+	})
+	deploy.Spec.Template.Spec.Volumes = append(deploy.Spec.Template.Spec.Volumes, corev1.Volume{
+		Name: healthAnalyzerTargetCAName,
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: healthAnalyzerTargetCAName,
+				},
+			},
+		},
+	})
+	// Restart the analyzer when the CA certificates change.
+	deploy.Spec.Template.Annotations[annotationPrefix+"target-ca-hash"] = computeConfigMapHash(newHealthAnalyzerTargetCA(namespace, target))
+}
+
+// setEnvVar sets the value of the environment variable of the container, the
+// variable is removed when the value is empty.
+func setEnvVar(container *corev1.Container, name, value string) {
+	i := slices.IndexFunc(container.Env, func(env corev1.EnvVar) bool { return env.Name == name })
+	switch {
+	case value == "" && i >= 0:
+		container.Env = slices.Delete(container.Env, i, i+1)
+	case value == "":
+	case i >= 0:
+		container.Env[i] = corev1.EnvVar{Name: name, Value: value}
+	default:
+		container.Env = append(container.Env, corev1.EnvVar{Name: name, Value: value})
+	}
+}
+
 func newHealthAnalyzerServiceMonitor(namespace string) *monv1.ServiceMonitor {
 	serviceMonitor := &monv1.ServiceMonitor{
 		TypeMeta: metav1.TypeMeta{
@@ -269,7 +345,12 @@ func newHealthAnalyzerServiceMonitor(namespace string) *monv1.ServiceMonitor {
 
 // newComponentHealthConfig creates a new ConfigMap
 // that defines the components whose health is evaluated.
-func newComponentHealthConfig(namespace string) *v1.ConfigMap {
+// The embedded default tree is used when components is empty.
+func newComponentHealthConfig(namespace string, components string) *v1.ConfigMap {
+	if components == "" {
+		components = componentHealthConfig
+	}
+
 	cm := v1.ConfigMap{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: v1.SchemeGroupVersion.String(),
@@ -281,7 +362,7 @@ func newComponentHealthConfig(namespace string) *v1.ConfigMap {
 			Labels:    componentLabels("monitoring"),
 		},
 		Data: map[string]string{
-			"components.yaml": componentHealthConfig,
+			healthComponentsKey: components,
 		},
 	}
 
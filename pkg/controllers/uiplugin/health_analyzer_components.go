@@ -0,0 +1,201 @@
+package uiplugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	uiv1alpha1 "github.com/rhobs/observability-operator/pkg/apis/uiplugin/v1alpha1"
+)
+
+const (
+	// healthComponentsKey is the key holding the component tree, both in the
+	// ConfigMap of the health analyzer and in the ConfigMap of the user.
+	healthComponentsKey = "components.yaml"
+)
+
+// healthComponentTree is the component tree evaluated by the
+// cluster-health-analyzer.
+type healthComponentTree struct {
+	Components []healthComponent `json:"components"`
+}
+
+// healthComponent is a node of the component tree. Its health is evaluated
+// from the alerts and the objects it selects, and from its children.
+type healthComponent struct {
+	Name     string            `json:"name"`
+	Alerts   *healthAlerts     `json:"alerts,omitempty"`
+	Objects  []healthObject    `json:"objects,omitempty"`
+	Children []healthComponent `json:"children,omitempty"`
+}
+
+type healthAlerts struct {
+	Selectors []healthSelector `json:"selectors"`
+}
+
+type healthObject struct {
+	Group     string           `json:"group,omitempty"`
+	Resource  string           `json:"resource"`
+	Namespace string           `json:"namespace,omitempty"`
+	Selectors []healthSelector `json:"selectors,omitempty"`
+}
+
+// healthSelector matches the labels having one of the listed values. An
+// empty list matches the presence of the label.
+type healthSelector struct {
+	MatchLabels map[string][]string `json:"matchLabels"`
+}
+
+// hasHealthAnalyzerComponents returns true if the plugin adds components to
+// the default component tree of the health analyzer.
+func hasHealthAnalyzerComponents(plugin *uiv1alpha1.UIPlugin) bool {
+	config := plugin.Spec.Monitoring
+	return config != nil && config.ClusterHealthAnalyzer != nil && config.ClusterHealthAnalyzer.Components != nil
+}
+
+// loadHealthAnalyzerComponents returns the component tree of the health
+// analyzer: the default tree merged with the components defined in the
+// ConfigMap referenced by the plugin. It returns an empty string when the
+// plugin doesn't reference a ConfigMap so that the default tree is used as
+// is. The ConfigMap is read with k which mustn't be limited to the resources
+// managed by the operator.
+func loadHealthAnalyzerComponents(ctx context.Context, k client.Reader, plugin *uiv1alpha1.UIPlugin) (string, error) {
+	if !hasHealthAnalyzerComponents(plugin) {
+		return "", nil
+	}
+	ref := plugin.Spec.Monitoring.ClusterHealthAnalyzer.Components
+
+	cm := &corev1.ConfigMap{}
+	if err := k.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: ref.Namespace}, cm); err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", fmt.Errorf("health analyzer components ConfigMap %s/%s not found", ref.Namespace, ref.Name)
+		}
+		return "", fmt.Errorf("failed to get health analyzer components ConfigMap %s/%s: %w", ref.Namespace, ref.Name, err)
+	}
+
+	data, found := cm.Data[healthComponentsKey]
+	if !found {
+		return "", fmt.Errorf("health analyzer components ConfigMap %s/%s has no %s key", ref.Namespace, ref.Name, healthComponentsKey)
+	}
+
+	components, err := mergeHealthComponents(componentHealthConfig, data)
+	if err != nil {
+		return "", fmt.Errorf("invalid health analyzer components in ConfigMap %s/%s: %w", ref.Namespace, ref.Name, err)
+	}
+	return components, nil
+}
+
+// mergeHealthComponents validates the additional component tree and merges
+// it into the default one. Components with the same name and parent are
+// merged: their alert selectors, objects and children are added to the
+// default component.
+func mergeHealthComponents(defaults, additional string) (string, error) {
+	tree := &healthComponentTree{}
+	if err := yaml.Unmarshal([]byte(defaults), tree); err != nil {
+		return "", fmt.Errorf("invalid default components: %w", err)
+	}
+
+	extra := &healthComponentTree{}
+	if err := yaml.UnmarshalStrict([]byte(additional), extra); err != nil {
+		return "", err
+	}
+	if len(extra.Components) == 0 {
+		return "", errors.New("no components defined")
+	}
+	if err := validateHealthComponents(extra.Components, ""); err != nil {
+		return "", err
+	}
+
+	tree.Components = mergeHealthComponentList(tree.Components, extra.Components)
+
+	out, err := yaml.Marshal(tree)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// validateHealthComponents checks that the components are named uniquely
+// among their siblings and that each of them selects alerts, objects or has
+// children.
+func validateHealthComponents(components []healthComponent, parent string) error {
+	seen := map[string]bool{}
+	for _, c := range components {
+		path := c.Name
+		if parent != "" {
+			path = parent + "/" + c.Name
+		}
+
+		if c.Name == "" {
+			return fmt.Errorf("component without name under %q", parent)
+		}
+		if seen[c.Name] {
+			return fmt.Errorf("component %q is defined twice", path)
+		}
+		seen[c.Name] = true
+
+		if c.Alerts == nil && len(c.Objects) == 0 && len(c.Children) == 0 {
+			return fmt.Errorf("component %q has no alerts, objects or children", path)
+		}
+
+		if c.Alerts != nil {
+			if len(c.Alerts.Selectors) == 0 {
+				return fmt.Errorf("component %q: alerts have no selectors", path)
+			}
+			for _, s := range c.Alerts.Selectors {
+				if len(s.MatchLabels) == 0 {
+					return fmt.Errorf("component %q: alert selector without matchLabels", path)
+				}
+			}
+		}
+
+		for _, o := range c.Objects {
+			if o.Resource == "" {
+				return fmt.Errorf("component %q: object without resource", path)
+			}
+			for _, s := range o.Selectors {
+				if len(s.MatchLabels) == 0 {
+					return fmt.Errorf("component %q: selector of %s without matchLabels", path, o.Resource)
+				}
+			}
+		}
+
+		if err := validateHealthComponents(c.Children, path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func mergeHealthComponentList(components, additional []healthComponent) []healthComponent {
+	for _, a := range additional {
+		i := -1
+		for j := range components {
+			if components[j].Name == a.Name {
+				i = j
+				break
+			}
+		}
+		if i < 0 {
+			components = append(components, a)
+			continue
+		}
+
+		c := &components[i]
+		if a.Alerts != nil {
+			if c.Alerts == nil {
+				c.Alerts = &healthAlerts{}
+			}
+			c.Alerts.Selectors = append(c.Alerts.Selectors, a.Alerts.Selectors...)
+		}
+		c.Objects = append(c.Objects, a.Objects...)
+		c.Children = mergeHealthComponentList(c.Children, a.Children)
+	}
+	return components
+}
@@ -0,0 +1,131 @@
+package uiplugin
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
+
+	uiv1alpha1 "github.com/rhobs/observability-operator/pkg/apis/uiplugin/v1alpha1"
+)
+
+const testHealthComponents = `
+components:
+- name: addons
+  children:
+  - name: storage
+    alerts:
+      selectors:
+      - matchLabels:
+          namespace: ["openshift-storage"]
+    objects:
+    - group: ocs.openshift.io
+      resource: storageclusters
+      namespace: openshift-storage
+- name: team-a
+  alerts:
+    selectors:
+    - matchLabels:
+        namespace: ["team-a"]
+`
+
+func TestMergeHealthComponents(t *testing.T) {
+	merged, err := mergeHealthComponents(componentHealthConfig, testHealthComponents)
+	assert.NilError(t, err)
+
+	tree := &healthComponentTree{}
+	assert.NilError(t, yaml.Unmarshal([]byte(merged), tree))
+
+	var names []string
+	for _, c := range tree.Components {
+		names = append(names, c.Name)
+	}
+	assert.DeepEqual(t, names, []string{"control-plane", "addons", "team-a"})
+
+	// The storage component is added next to the default kubevirt one.
+	addons := tree.Components[1]
+	assert.Equal(t, len(addons.Children), 2)
+	assert.Equal(t, addons.Children[0].Name, "kubevirt")
+	assert.Equal(t, addons.Children[1].Name, "storage")
+	assert.Equal(t, addons.Children[1].Objects[0].Resource, "storageclusters")
+
+	// The default selectors are kept.
+	nodes := tree.Components[0].Children[0]
+	assert.Equal(t, nodes.Name, "nodes")
+	assert.DeepEqual(t, nodes.Objects[0].Selectors[0].MatchLabels, map[string][]string{"node-role.kubernetes.io/control-plane": {}})
+}
+
+func TestMergeInvalidHealthComponents(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		components string
+		err        string
+	}{
+		{
+			name:       "empty",
+			components: `components: []`,
+			err:        "no components defined",
+		},
+		{
+			name:       "unknown field",
+			components: "components:\n- name: a\n  alert: {}",
+			err:        `unknown field "alert"`,
+		},
+		{
+			name:       "duplicate",
+			components: "components:\n- name: a\n  children:\n  - {name: b, objects: [{resource: nodes}]}\n  - {name: b, objects: [{resource: pods}]}",
+			err:        `component "a/b" is defined twice`,
+		},
+		{
+			name:       "empty component",
+			components: "components:\n- name: a",
+			err:        `component "a" has no alerts, objects or children`,
+		},
+		{
+			name:       "alerts without selectors",
+			components: "components:\n- name: a\n  alerts: {selectors: []}",
+			err:        `component "a": alerts have no selectors`,
+		},
+		{
+			name:       "object without resource",
+			components: "components:\n- name: a\n  objects: [{group: apps}]",
+			err:        `component "a": object without resource`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := mergeHealthComponents(componentHealthConfig, tc.components)
+			assert.ErrorContains(t, err, tc.err)
+		})
+	}
+}
+
+func TestLoadHealthAnalyzerComponents(t *testing.T) {
+	k := fake.NewClientBuilder().WithObjects(
+		newDefinitionsConfigMap("health-components", "team-a", nil, map[string]string{
+			healthComponentsKey: testHealthComponents,
+		}),
+	).Build()
+
+	plugin := newMonitoringPlugin(&uiv1alpha1.MonitoringConfig{
+		ClusterHealthAnalyzer: &uiv1alpha1.ClusterHealthAnalyzerReference{Enabled: true},
+	})
+
+	components, err := loadHealthAnalyzerComponents(context.Background(), k, plugin)
+	assert.NilError(t, err)
+	assert.Equal(t, components, "")
+	assert.Equal(t, newComponentHealthConfig("observability", components).Data[healthComponentsKey], componentHealthConfig)
+
+	plugin.Spec.Monitoring.ClusterHealthAnalyzer.Components = &uiv1alpha1.NamespacedConfigMapReference{
+		Name:      "health-components",
+		Namespace: "team-a",
+	}
+	components, err = loadHealthAnalyzerComponents(context.Background(), k, plugin)
+	assert.NilError(t, err)
+	assert.Assert(t, components != componentHealthConfig)
+
+	plugin.Spec.Monitoring.ClusterHealthAnalyzer.Components.Name = "missing"
+	_, err = loadHealthAnalyzerComponents(context.Background(), k, plugin)
+	assert.ErrorContains(t, err, "health analyzer components ConfigMap team-a/missing not found")
+}
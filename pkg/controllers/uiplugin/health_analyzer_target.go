@@ -0,0 +1,164 @@
+package uiplugin
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	msoapi "github.com/rhobs/observability-operator/pkg/apis/monitoring/v1alpha1"
+	uiv1alpha1 "github.com/rhobs/observability-operator/pkg/apis/uiplugin/v1alpha1"
+)
+
+const (
+	healthAnalyzerTargetCAName      = name + "-target-ca"
+	healthAnalyzerTargetCAMountPath = "/etc/health-analyzer/target-ca"
+	healthAnalyzerTargetCAKey       = "ca.crt"
+)
+
+// healthAnalyzerTarget is the Prometheus and Alertmanager analyzed by the
+// health analyzer when it targets a MonitoringStack or a ThanosQuerier
+// instead of the platform monitoring.
+type healthAnalyzerTarget struct {
+	prometheusURL string
+	// alertmanagerURL is empty when the target has no Alertmanager.
+	alertmanagerURL string
+	// caBundle holds the CA certificates verifying the TLS endpoints of the
+	// target. It's empty when the target doesn't serve TLS.
+	caBundle string
+}
+
+// hasHealthAnalyzerTarget returns true if the health analyzer of the plugin
+// targets a workload of the operator.
+func hasHealthAnalyzerTarget(plugin *uiv1alpha1.UIPlugin) bool {
+	config := plugin.Spec.Monitoring
+	return config != nil && config.ClusterHealthAnalyzer != nil && config.ClusterHealthAnalyzer.Target != nil
+}
+
+// loadHealthAnalyzerTarget resolves the URLs and the CA certificates of the
+// MonitoringStack or ThanosQuerier targeted by the health analyzer. It
+// returns nil when the platform monitoring is analyzed.
+func loadHealthAnalyzerTarget(ctx context.Context, k client.Reader, plugin *uiv1alpha1.UIPlugin) (*healthAnalyzerTarget, error) {
+	if !hasHealthAnalyzerTarget(plugin) {
+		return nil, nil
+	}
+	ref := plugin.Spec.Monitoring.ClusterHealthAnalyzer.Target
+	key := types.NamespacedName{Name: ref.Name, Namespace: ref.Namespace}
+
+	var (
+		target    = &healthAnalyzerTarget{}
+		tlsConfig []*msoapi.WebTLSConfig
+	)
+	switch ref.Kind {
+	case uiv1alpha1.HealthAnalyzerTargetMonitoringStack:
+		ms := &msoapi.MonitoringStack{}
+		if err := k.Get(ctx, key, ms); err != nil {
+			return nil, healthAnalyzerTargetError(ref, err)
+		}
+
+		var prometheusTLS *msoapi.WebTLSConfig
+		if ms.Spec.PrometheusConfig != nil {
+			prometheusTLS = ms.Spec.PrometheusConfig.WebTLSConfig
+		}
+		target.prometheusURL = workloadURL(prometheusTLS, fmt.Sprintf("%s-prometheus.%s.svc:9090", ms.Name, ms.Namespace)) + "/"
+		tlsConfig = append(tlsConfig, prometheusTLS)
+
+		if !ms.Spec.AlertmanagerConfig.Disabled {
+			alertmanagerTLS := ms.Spec.AlertmanagerConfig.WebTLSConfig
+			target.alertmanagerURL = workloadURL(alertmanagerTLS, fmt.Sprintf("%s-alertmanager.%s.svc:9093", ms.Name, ms.Namespace))
+			tlsConfig = append(tlsConfig, alertmanagerTLS)
+		}
+
+	case uiv1alpha1.HealthAnalyzerTargetThanosQuerier:
+		tq := &msoapi.ThanosQuerier{}
+		if err := k.Get(ctx, key, tq); err != nil {
+			return nil, healthAnalyzerTargetError(ref, err)
+		}
+
+		target.prometheusURL = workloadURL(tq.Spec.WebTLSConfig, fmt.Sprintf("thanos-querier-%s.%s.svc:10902", tq.Name, tq.Namespace)) + "/"
+		tlsConfig = append(tlsConfig, tq.Spec.WebTLSConfig)
+
+	default:
+		return nil, fmt.Errorf("unsupported health analyzer target kind %q", ref.Kind)
+	}
+
+	// The Prometheus and Alertmanager of a MonitoringStack may be verified
+	// with different CAs, the bundle holds all of them.
+	var certificates []string
+	for _, c := range tlsConfig {
+		if c == nil {
+			continue
+		}
+		ca, err := readSecretKey(ctx, k, ref.Namespace, c.CertificateAuthority)
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Contains(certificates, ca) {
+			certificates = append(certificates, ca)
+		}
+	}
+	for _, ca := range certificates {
+		target.caBundle += ca
+		if ca[len(ca)-1] != '\n' {
+			target.caBundle += "\n"
+		}
+	}
+
+	return target, nil
+}
+
+func healthAnalyzerTargetError(ref *uiv1alpha1.HealthAnalyzerTarget, err error) error {
+	if apierrors.IsNotFound(err) {
+		return fmt.Errorf("health analyzer target %s %s/%s not found", ref.Kind, ref.Namespace, ref.Name)
+	}
+	return fmt.Errorf("failed to get health analyzer target %s %s/%s: %w", ref.Kind, ref.Namespace, ref.Name, err)
+}
+
+// workloadURL returns the URL of a workload of the operator at address,
+// using https when the workload serves TLS.
+func workloadURL(tlsConfig *msoapi.WebTLSConfig, address string) string {
+	if tlsConfig != nil {
+		return "https://" + address
+	}
+	return "http://" + address
+}
+
+func readSecretKey(ctx context.Context, k client.Reader, namespace string, selector msoapi.SecretKeySelector) (string, error) {
+	secret := &corev1.Secret{}
+	if err := k.Get(ctx, types.NamespacedName{Name: selector.Name, Namespace: namespace}, secret); err != nil {
+		return "", fmt.Errorf("failed to get secret %s/%s: %w", namespace, selector.Name, err)
+	}
+	value := secret.Data[selector.Key]
+	if len(value) == 0 {
+		return "", fmt.Errorf("secret %s/%s has no %s key", namespace, selector.Name, selector.Key)
+	}
+	return string(value), nil
+}
+
+// newHealthAnalyzerTargetCA returns the ConfigMap holding the CA
+// certificates of the target, mounted in the pod of the health analyzer.
+// The ConfigMap is empty when target is nil.
+func newHealthAnalyzerTargetCA(namespace string, target *healthAnalyzerTarget) *corev1.ConfigMap {
+	cm := &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: corev1.SchemeGroupVersion.String(),
+			Kind:       "ConfigMap",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      healthAnalyzerTargetCAName,
+			Namespace: namespace,
+			Labels:    componentLabels(name),
+		},
+	}
+	if target != nil {
+		cm.Data = map[string]string{
+			healthAnalyzerTargetCAKey: target.caBundle,
+		}
+	}
+	return cm
+}
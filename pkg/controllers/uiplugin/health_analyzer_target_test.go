@@ -0,0 +1,172 @@
+package uiplugin
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	msoapi "github.com/rhobs/observability-operator/pkg/apis/monitoring/v1alpha1"
+	uiv1alpha1 "github.com/rhobs/observability-operator/pkg/apis/uiplugin/v1alpha1"
+)
+
+func newTestWebTLSConfig(secret string) *msoapi.WebTLSConfig {
+	return &msoapi.WebTLSConfig{
+		PrivateKey:           msoapi.SecretKeySelector{Name: secret, Key: "tls.key"},
+		Certificate:          msoapi.SecretKeySelector{Name: secret, Key: "tls.crt"},
+		CertificateAuthority: msoapi.SecretKeySelector{Name: secret, Key: "ca.crt"},
+	}
+}
+
+func TestLoadHealthAnalyzerTarget(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NilError(t, msoapi.AddToScheme(scheme))
+	assert.NilError(t, corev1.AddToScheme(scheme))
+
+	k := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&msoapi.MonitoringStack{
+			ObjectMeta: metav1.ObjectMeta{Name: "tls", Namespace: "team-a"},
+			Spec: msoapi.MonitoringStackSpec{
+				PrometheusConfig:   &msoapi.PrometheusConfig{WebTLSConfig: newTestWebTLSConfig("prometheus-tls")},
+				AlertmanagerConfig: msoapi.AlertmanagerConfig{WebTLSConfig: newTestWebTLSConfig("alertmanager-tls")},
+			},
+		},
+		&msoapi.MonitoringStack{
+			ObjectMeta: metav1.ObjectMeta{Name: "plain", Namespace: "team-a"},
+			Spec: msoapi.MonitoringStackSpec{
+				AlertmanagerConfig: msoapi.AlertmanagerConfig{Disabled: true},
+			},
+		},
+		&msoapi.ThanosQuerier{ObjectMeta: metav1.ObjectMeta{Name: "global", Namespace: "team-a"}},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "prometheus-tls", Namespace: "team-a"},
+			Data:       map[string][]byte{"ca.crt": []byte("prometheus-ca")},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "alertmanager-tls", Namespace: "team-a"},
+			Data:       map[string][]byte{"ca.crt": []byte("alertmanager-ca\n")},
+		},
+	).Build()
+
+	plugin := newMonitoringPlugin(&uiv1alpha1.MonitoringConfig{
+		ClusterHealthAnalyzer: &uiv1alpha1.ClusterHealthAnalyzerReference{Enabled: true},
+	})
+
+	target, err := loadHealthAnalyzerTarget(context.Background(), k, plugin)
+	assert.NilError(t, err)
+	assert.Assert(t, target == nil)
+
+	for _, tc := range []struct {
+		name   string
+		ref    uiv1alpha1.HealthAnalyzerTarget
+		target *healthAnalyzerTarget
+		err    string
+	}{
+		{
+			name: "MonitoringStack with TLS",
+			ref:  uiv1alpha1.HealthAnalyzerTarget{Kind: uiv1alpha1.HealthAnalyzerTargetMonitoringStack, Name: "tls", Namespace: "team-a"},
+			target: &healthAnalyzerTarget{
+				prometheusURL:   "https://tls-prometheus.team-a.svc:9090/",
+				alertmanagerURL: "https://tls-alertmanager.team-a.svc:9093",
+				caBundle:        "prometheus-ca\nalertmanager-ca\n",
+			},
+		},
+		{
+			name: "MonitoringStack without Alertmanager",
+			ref:  uiv1alpha1.HealthAnalyzerTarget{Kind: uiv1alpha1.HealthAnalyzerTargetMonitoringStack, Name: "plain", Namespace: "team-a"},
+			target: &healthAnalyzerTarget{
+				prometheusURL: "http://plain-prometheus.team-a.svc:9090/",
+			},
+		},
+		{
+			name: "ThanosQuerier",
+			ref:  uiv1alpha1.HealthAnalyzerTarget{Kind: uiv1alpha1.HealthAnalyzerTargetThanosQuerier, Name: "global", Namespace: "team-a"},
+			target: &healthAnalyzerTarget{
+				prometheusURL: "http://thanos-querier-global.team-a.svc:10902/",
+			},
+		},
+		{
+			name: "missing",
+			ref:  uiv1alpha1.HealthAnalyzerTarget{Kind: uiv1alpha1.HealthAnalyzerTargetThanosQuerier, Name: "missing", Namespace: "team-a"},
+			err:  "health analyzer target ThanosQuerier team-a/missing not found",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			plugin.Spec.Monitoring.ClusterHealthAnalyzer.Target = &tc.ref
+
+			target, err := loadHealthAnalyzerTarget(context.Background(), k, plugin)
+			if tc.err != "" {
+				assert.ErrorContains(t, err, tc.err)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, *target, *tc.target)
+		})
+	}
+}
+
+func TestNewHealthAnalyzerDeploymentWithTarget(t *testing.T) {
+	target := &healthAnalyzerTarget{
+		prometheusURL: "https://tls-prometheus.team-a.svc:9090/",
+		caBundle:      "prometheus-ca\n",
+	}
+	deploy := newHealthAnalyzerDeployment("observability", "health-analyzer", UIPluginInfo{HealthAnalyzerTarget: target})
+
+	container := deploy.Spec.Template.Spec.Containers[0]
+	assert.DeepEqual(t, container.Env, []corev1.EnvVar{
+		{Name: "PROM_URL", Value: "https://tls-prometheus.team-a.svc:9090/"},
+		{Name: "SSL_CERT_FILE", Value: "/etc/health-analyzer/target-ca/ca.crt"},
+	})
+	assert.Equal(t, container.VolumeMounts[len(container.VolumeMounts)-1].MountPath, healthAnalyzerTargetCAMountPath)
+
+	volumes := deploy.Spec.Template.Spec.Volumes
+	assert.Equal(t, volumes[len(volumes)-1].ConfigMap.Name, healthAnalyzerTargetCAName)
+	assert.Assert(t, deploy.Spec.Template.Annotations[annotationPrefix+"target-ca-hash"] != "")
+}
+
+func TestSetHealthAnalyzerTargetKeepsEnv(t *testing.T) {
+	deploy := newHealthAnalyzerDeployment("observability", "health-analyzer", UIPluginInfo{})
+	container := &deploy.Spec.Template.Spec.Containers[0]
+	container.Env = append(container.Env, corev1.EnvVar{Name: "HTTPS_PROXY", Value: "http://proxy:3128"})
+
+	setHealthAnalyzerTarget(deploy, "observability", &healthAnalyzerTarget{
+		prometheusURL:   "http://plain-prometheus.team-a.svc:9090/",
+		alertmanagerURL: "http://plain-alertmanager.team-a.svc:9093",
+	})
+
+	assert.DeepEqual(t, container.Env, []corev1.EnvVar{
+		{Name: "PROM_URL", Value: "http://plain-prometheus.team-a.svc:9090/"},
+		{Name: "ALERTMANAGER_URL", Value: "http://plain-alertmanager.team-a.svc:9093"},
+		{Name: "HTTPS_PROXY", Value: "http://proxy:3128"},
+	})
+}
+
+func TestHealthAnalyzerComponentsWithUnresolvedTarget(t *testing.T) {
+	plugin := newMonitoringPlugin(&uiv1alpha1.MonitoringConfig{
+		ClusterHealthAnalyzer: &uiv1alpha1.ClusterHealthAnalyzerReference{
+			Enabled: true,
+			Target: &uiv1alpha1.HealthAnalyzerTarget{
+				Kind:      uiv1alpha1.HealthAnalyzerTargetMonitoringStack,
+				Name:      "missing",
+				Namespace: "team-a",
+			},
+		},
+	})
+	info := UIPluginInfo{
+		ResourceNamespace:       "observability",
+		HealthAnalyzerImage:     "health-analyzer:latest",
+		HealthAnalyzerTargetErr: errors.New("health analyzer target MonitoringStack team-a/missing not found"),
+	}
+
+	// Neither updated nor deleted, the last deployed analyzer is kept.
+	assert.Equal(t, len(healthAnalyzerComponentReconcilers(plugin, info, true)), 0)
+
+	info.HealthAnalyzerTargetErr = nil
+	info.HealthAnalyzerTarget = &healthAnalyzerTarget{prometheusURL: "http://missing-prometheus.team-a.svc:9090/"}
+	assert.Assert(t, len(healthAnalyzerComponentReconcilers(plugin, info, true)) > 0)
+}
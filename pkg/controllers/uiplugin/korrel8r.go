@@ -0,0 +1,306 @@
+package uiplugin
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"maps"
+	"path"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	uiv1alpha1 "github.com/rhobs/observability-operator/pkg/apis/uiplugin/v1alpha1"
+	"github.com/rhobs/observability-operator/pkg/reconciler"
+)
+
+const (
+	korrel8rServiceCA      = "./run/secrets/kubernetes.io/serviceaccount/service-ca.crt"
+	korrel8rDefaultRules   = "/etc/korrel8r/rules/all.yaml"
+	korrel8rCAMountDir     = "/etc/korrel8r/ca"
+	korrel8rRulesMountDir  = "/etc/korrel8r/custom-rules"
+	korrel8rCAFileName     = "ca.crt"
+	korrel8rTempoSearchAPI = "/api/traces/v1/platform/tempo/api/search"
+)
+
+// korrel8rStore is a store of the korrel8r configuration file.
+type korrel8rStore struct {
+	Domain               string `yaml:"domain"`
+	Metrics              string `yaml:"metrics,omitempty"`
+	Alertmanager         string `yaml:"alertmanager,omitempty"`
+	Metric               string `yaml:"metric,omitempty"`
+	LokiStack            string `yaml:"lokiStack,omitempty"`
+	TempoStack           string `yaml:"tempoStack,omitempty"`
+	CertificateAuthority string `yaml:"certificateAuthority,omitempty"`
+}
+
+// korrel8rConfig is the korrel8r configuration file.
+type korrel8rConfig struct {
+	Stores  []korrel8rStore `yaml:"stores"`
+	Include []string        `yaml:"include"`
+}
+
+// korrel8rInfo is the korrel8r configuration resolved for a
+// TroubleshootingPanel plugin.
+type korrel8rInfo struct {
+	config       korrel8rConfig
+	stores       []uiv1alpha1.Korrel8rStoreStatus
+	backends     []uiv1alpha1.UIPluginBackend
+	volumes      []corev1.Volume
+	volumeMounts []corev1.VolumeMount
+	// configMaps are the keys of the ConfigMaps mounted in the korrel8r
+	// pod and configMapsHash the hash of their content. Korrel8r only reads
+	// the rules and CAs at startup, the hash restarts it when they change.
+	configMaps     []types.NamespacedName
+	configMapsHash string
+}
+
+// resolvedStore is the endpoint of a korrel8r store.
+type resolvedStore struct {
+	url    string
+	source uiv1alpha1.Korrel8rStoreSource
+	// name and namespace of the service of the store, if known.
+	name      string
+	namespace string
+}
+
+// resolveKorrel8r returns the korrel8r configuration for the plugin. The
+// stores declared in the UIPlugin take precedence over the discovered ones
+// which take precedence over the default locations. The ConfigMaps holding
+// the custom rules and CAs are read with apiReader in the namespace of the
+// korrel8r pod.
+func resolveKorrel8r(ctx context.Context, k client.Client, apiReader client.Reader, namespace string, cfg *uiv1alpha1.Korrel8rConfig) (*korrel8rInfo, error) {
+	if cfg == nil {
+		cfg = &uiv1alpha1.Korrel8rConfig{}
+	}
+
+	declared := make(map[uiv1alpha1.Korrel8rDomain]uiv1alpha1.Korrel8rStore, len(cfg.Stores))
+	for _, s := range cfg.Stores {
+		declared[s.Domain] = s
+	}
+
+	// mounted are the keys mounted from each ConfigMap.
+	mounted := map[string][]string{}
+	info := &korrel8rInfo{
+		config: korrel8rConfig{
+			Stores:  []korrel8rStore{{Domain: "k8s"}},
+			Include: []string{korrel8rDefaultRules},
+		},
+	}
+
+	monitoringService := func(domain uiv1alpha1.Korrel8rDomain, name string, port int32) resolvedStore {
+		if s, ok := declared[domain]; ok && s.URL != "" {
+			return resolvedStore{url: s.URL, source: uiv1alpha1.Korrel8rStoreConfigured}
+		}
+		return resolvedStore{
+			url:       fmt.Sprintf("https://%s.%s.svc:%d", name, reconciler.OpenshiftMonitoringNamespace, port),
+			source:    uiv1alpha1.Korrel8rStoreDefault,
+			name:      name,
+			namespace: reconciler.OpenshiftMonitoringNamespace,
+		}
+	}
+	metric := monitoringService(uiv1alpha1.Korrel8rDomainMetric, "thanos-querier", 9091)
+
+	for _, domain := range []uiv1alpha1.Korrel8rDomain{
+		uiv1alpha1.Korrel8rDomainAlert,
+		uiv1alpha1.Korrel8rDomainLog,
+		uiv1alpha1.Korrel8rDomainMetric,
+		uiv1alpha1.Korrel8rDomainNetflow,
+		uiv1alpha1.Korrel8rDomainTrace,
+	} {
+		if slices.Contains(cfg.DisabledDomains, domain) {
+			continue
+		}
+
+		var (
+			store    = korrel8rStore{Domain: string(domain)}
+			resolved resolvedStore
+			err      error
+		)
+		switch domain {
+		case uiv1alpha1.Korrel8rDomainAlert:
+			resolved = monitoringService(domain, "alertmanager-main", 9094)
+			store.Metrics = metric.url
+			store.Alertmanager = resolved.url
+		case uiv1alpha1.Korrel8rDomainMetric:
+			resolved = metric
+			store.Metric = resolved.url
+		case uiv1alpha1.Korrel8rDomainLog:
+			resolved, err = discoverStore(ctx, k, declared[domain], OpenshiftLoggingNs, "logging-loki-gateway-http", getLokiServiceName,
+				func(name, namespace string) string { return fmt.Sprintf("https://%s.%s.svc:8080", name, namespace) })
+			store.LokiStack = resolved.url
+		case uiv1alpha1.Korrel8rDomainNetflow:
+			resolved, err = discoverStore(ctx, k, declared[domain], OpenshiftNetobservNs, "loki-gateway-http", getLokiServiceName,
+				func(name, namespace string) string { return fmt.Sprintf("https://%s.%s.svc:8080", name, namespace) })
+			store.LokiStack = resolved.url
+		case uiv1alpha1.Korrel8rDomainTrace:
+			resolved, err = discoverStore(ctx, k, declared[domain], OpenshiftTracingNs, "tempo-platform-gateway", getTempoServiceName,
+				func(name, namespace string) string {
+					return fmt.Sprintf("https://%s.%s.svc.cluster.local:8080%s", name, namespace, korrel8rTempoSearchAPI)
+				})
+			store.TempoStack = resolved.url
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to discover the korrel8r store of the %s domain: %w", domain, err)
+		}
+
+		store.CertificateAuthority = korrel8rServiceCA
+		if ca := declared[domain].CertificateAuthority; ca != nil {
+			volumeName := "korrel8r-ca-" + string(domain)
+			mountPath := path.Join(korrel8rCAMountDir, string(domain))
+			info.addConfigMapVolume(volumeName, mountPath, ca.Name, corev1.KeyToPath{Key: ca.Key, Path: korrel8rCAFileName})
+			mounted[ca.Name] = append(mounted[ca.Name], ca.Key)
+			store.CertificateAuthority = path.Join(mountPath, korrel8rCAFileName)
+		}
+
+		info.config.Stores = append(info.config.Stores, store)
+		info.stores = append(info.stores, uiv1alpha1.Korrel8rStoreStatus{
+			Domain: string(domain),
+			URL:    resolved.url,
+			Source: resolved.source,
+		})
+		if resolved.source != uiv1alpha1.Korrel8rStoreDefault {
+			info.backends = append(info.backends, uiv1alpha1.UIPluginBackend{
+				Type:      korrel8rDomainBackendType[domain],
+				Name:      resolved.name,
+				Namespace: resolved.namespace,
+				URL:       resolved.url,
+			})
+		}
+	}
+
+	for i, rule := range cfg.Rules {
+		mountPath := path.Join(korrel8rRulesMountDir, fmt.Sprint(i))
+		info.addConfigMapVolume(fmt.Sprintf("korrel8r-rules-%d", i), mountPath, rule.Name, corev1.KeyToPath{Key: rule.Key, Path: rule.Key})
+		info.config.Include = append(info.config.Include, path.Join(mountPath, rule.Key))
+		mounted[rule.Name] = append(mounted[rule.Name], rule.Key)
+	}
+
+	if err := info.hashConfigMaps(ctx, apiReader, namespace, mounted); err != nil {
+		return nil, err
+	}
+
+	return info, nil
+}
+
+// hashConfigMaps hashes the mounted keys of the ConfigMaps. A missing
+// ConfigMap doesn't fail the reconciliation: the korrel8r pod can't start
+// until it's created which changes the hash.
+func (info *korrel8rInfo) hashConfigMaps(ctx context.Context, k client.Reader, namespace string, mounted map[string][]string) error {
+	content := &corev1.ConfigMap{Data: map[string]string{}}
+	for _, name := range slices.Sorted(maps.Keys(mounted)) {
+		key := types.NamespacedName{Namespace: namespace, Name: name}
+		info.configMaps = append(info.configMaps, key)
+
+		cm := &corev1.ConfigMap{}
+		if err := k.Get(ctx, key, cm); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return fmt.Errorf("failed to get korrel8r ConfigMap %s: %w", key, err)
+		}
+		for _, item := range mounted[name] {
+			if v, found := cm.Data[item]; found {
+				content.Data[name+"/"+item] = v
+			} else if v, found := cm.BinaryData[item]; found {
+				content.Data[name+"/"+item] = string(v)
+			}
+		}
+	}
+
+	if len(info.configMaps) > 0 {
+		info.configMapsHash = computeConfigMapHash(content)
+	}
+	return nil
+}
+
+// korrel8rConfigMaps returns the names of the ConfigMaps mounted in the
+// korrel8r pod of the plugin.
+func korrel8rConfigMaps(plugin *uiv1alpha1.UIPlugin) []string {
+	if plugin.Spec.Type != uiv1alpha1.TypeTroubleshootingPanel || plugin.Spec.TroubleshootingPanel == nil || plugin.Spec.TroubleshootingPanel.Korrel8r == nil {
+		return nil
+	}
+	cfg := plugin.Spec.TroubleshootingPanel.Korrel8r
+
+	var names []string
+	for _, s := range cfg.Stores {
+		if s.CertificateAuthority != nil {
+			names = append(names, s.CertificateAuthority.Name)
+		}
+	}
+	for _, rule := range cfg.Rules {
+		names = append(names, rule.Name)
+	}
+	return names
+}
+
+var korrel8rDomainBackendType = map[uiv1alpha1.Korrel8rDomain]uiv1alpha1.BackendType{
+	uiv1alpha1.Korrel8rDomainAlert:   uiv1alpha1.BackendTypeAlertmanager,
+	uiv1alpha1.Korrel8rDomainLog:     uiv1alpha1.BackendTypeLokiStack,
+	uiv1alpha1.Korrel8rDomainMetric:  uiv1alpha1.BackendTypeThanosQuerier,
+	uiv1alpha1.Korrel8rDomainNetflow: uiv1alpha1.BackendTypeLokiStack,
+	uiv1alpha1.Korrel8rDomainTrace:   uiv1alpha1.BackendTypeTempoStack,
+}
+
+// discoverStore returns the declared URL of the store if any, else the URL
+// of the service found in the namespace of the store, else the URL of the
+// service at its default location.
+func discoverStore(
+	ctx context.Context,
+	k client.Client,
+	declared uiv1alpha1.Korrel8rStore,
+	defaultNamespace, defaultService string,
+	discover func(context.Context, client.Client, string) (string, error),
+	url func(name, namespace string) string,
+) (resolvedStore, error) {
+	if declared.URL != "" {
+		return resolvedStore{url: declared.URL, source: uiv1alpha1.Korrel8rStoreConfigured}, nil
+	}
+
+	namespace := defaultNamespace
+	if declared.Namespace != "" {
+		namespace = declared.Namespace
+	}
+
+	name, err := discover(ctx, k, namespace)
+	if err != nil {
+		return resolvedStore{}, err
+	}
+
+	source := uiv1alpha1.Korrel8rStoreDiscovered
+	if name == "" {
+		name, source = defaultService, uiv1alpha1.Korrel8rStoreDefault
+	}
+
+	return resolvedStore{url: url(name, namespace), source: source, name: name, namespace: namespace}, nil
+}
+
+func (info *korrel8rInfo) addConfigMapVolume(volumeName, mountPath, configMap string, item corev1.KeyToPath) {
+	info.volumes = append(info.volumes, corev1.Volume{
+		Name: volumeName,
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: configMap},
+				Items:                []corev1.KeyToPath{item},
+			},
+		},
+	})
+	info.volumeMounts = append(info.volumeMounts, corev1.VolumeMount{
+		Name:      volumeName,
+		ReadOnly:  true,
+		MountPath: mountPath,
+	})
+}
+
+func marshalKorrel8rConfig(cfg korrel8rConfig) (string, error) {
+	buf := &bytes.Buffer{}
+	if err := yaml.NewEncoder(buf).Encode(cfg); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
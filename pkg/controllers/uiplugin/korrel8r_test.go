@@ -0,0 +1,150 @@
+package uiplugin
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	uiv1alpha1 "github.com/rhobs/observability-operator/pkg/apis/uiplugin/v1alpha1"
+)
+
+func TestResolveKorrel8r(t *testing.T) {
+	lokiGateway := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "logs-gateway-http",
+			Namespace: "custom-logging",
+			Labels:    map[string]string{"app.kubernetes.io/component": "lokistack-gateway"},
+		},
+	}
+
+	t.Run("default stores", func(t *testing.T) {
+		k := fake.NewClientBuilder().Build()
+
+		info, err := resolveKorrel8r(context.Background(), k, k, "openshift-operators", nil)
+		assert.NilError(t, err)
+
+		cfg, err := marshalKorrel8rConfig(info.config)
+		assert.NilError(t, err)
+		assert.Equal(t, cfg, `stores:
+    - domain: k8s
+    - domain: alert
+      metrics: https://thanos-querier.openshift-monitoring.svc:9091
+      alertmanager: https://alertmanager-main.openshift-monitoring.svc:9094
+      certificateAuthority: ./run/secrets/kubernetes.io/serviceaccount/service-ca.crt
+    - domain: log
+      lokiStack: https://logging-loki-gateway-http.openshift-logging.svc:8080
+      certificateAuthority: ./run/secrets/kubernetes.io/serviceaccount/service-ca.crt
+    - domain: metric
+      metric: https://thanos-querier.openshift-monitoring.svc:9091
+      certificateAuthority: ./run/secrets/kubernetes.io/serviceaccount/service-ca.crt
+    - domain: netflow
+      lokiStack: https://loki-gateway-http.netobserv.svc:8080
+      certificateAuthority: ./run/secrets/kubernetes.io/serviceaccount/service-ca.crt
+    - domain: trace
+      tempoStack: https://tempo-platform-gateway.openshift-tracing.svc.cluster.local:8080/api/traces/v1/platform/tempo/api/search
+      certificateAuthority: ./run/secrets/kubernetes.io/serviceaccount/service-ca.crt
+include:
+    - /etc/korrel8r/rules/all.yaml
+`)
+		assert.Equal(t, len(info.stores), 5)
+		for _, s := range info.stores {
+			assert.Equal(t, s.Source, uiv1alpha1.Korrel8rStoreDefault)
+		}
+		assert.Equal(t, len(info.backends), 0)
+		assert.Equal(t, len(info.volumes), 0)
+		assert.Equal(t, info.configMapsHash, "")
+	})
+
+	t.Run("declared, discovered and disabled stores", func(t *testing.T) {
+		k := fake.NewClientBuilder().WithObjects(lokiGateway).Build()
+
+		info, err := resolveKorrel8r(context.Background(), k, k, "openshift-operators", &uiv1alpha1.Korrel8rConfig{
+			Stores: []uiv1alpha1.Korrel8rStore{
+				{
+					Domain:    uiv1alpha1.Korrel8rDomainLog,
+					Namespace: "custom-logging",
+				},
+				{
+					Domain: uiv1alpha1.Korrel8rDomainMetric,
+					URL:    "https://thanos.example.com",
+					CertificateAuthority: &uiv1alpha1.ConfigMapKeyReference{
+						Name: "thanos-ca",
+						Key:  "bundle.crt",
+					},
+				},
+			},
+			DisabledDomains: []uiv1alpha1.Korrel8rDomain{
+				uiv1alpha1.Korrel8rDomainNetflow,
+				uiv1alpha1.Korrel8rDomainTrace,
+			},
+			Rules: []uiv1alpha1.ConfigMapKeyReference{
+				{Name: "custom-rules", Key: "rules.yaml"},
+			},
+		})
+		assert.NilError(t, err)
+
+		assert.DeepEqual(t, info.stores, []uiv1alpha1.Korrel8rStoreStatus{
+			{Domain: "alert", URL: "https://alertmanager-main.openshift-monitoring.svc:9094", Source: uiv1alpha1.Korrel8rStoreDefault},
+			{Domain: "log", URL: "https://logs-gateway-http.custom-logging.svc:8080", Source: uiv1alpha1.Korrel8rStoreDiscovered},
+			{Domain: "metric", URL: "https://thanos.example.com", Source: uiv1alpha1.Korrel8rStoreConfigured},
+		})
+
+		assert.Equal(t, info.config.Stores[1].Metrics, "https://thanos.example.com")
+		assert.Equal(t, info.config.Stores[3].CertificateAuthority, "/etc/korrel8r/ca/metric/ca.crt")
+		assert.DeepEqual(t, info.config.Include, []string{
+			"/etc/korrel8r/rules/all.yaml",
+			"/etc/korrel8r/custom-rules/0/rules.yaml",
+		})
+
+		assert.DeepEqual(t, info.backends, []uiv1alpha1.UIPluginBackend{
+			{Type: uiv1alpha1.BackendTypeLokiStack, Name: "logs-gateway-http", Namespace: "custom-logging", URL: "https://logs-gateway-http.custom-logging.svc:8080"},
+			{Type: uiv1alpha1.BackendTypeThanosQuerier, URL: "https://thanos.example.com"},
+		})
+
+		assert.Equal(t, len(info.volumes), 2)
+		assert.Equal(t, info.volumes[0].ConfigMap.Name, "thanos-ca")
+		assert.Equal(t, info.volumes[1].ConfigMap.Items[0].Path, "rules.yaml")
+		assert.Equal(t, info.volumeMounts[1].MountPath, "/etc/korrel8r/custom-rules/0")
+	})
+
+	t.Run("ConfigMaps hash", func(t *testing.T) {
+		rules := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "custom-rules", Namespace: "openshift-operators"},
+			Data:       map[string]string{"rules.yaml": "rules: []", "other": "ignored"},
+		}
+		cfg := &uiv1alpha1.Korrel8rConfig{
+			Rules: []uiv1alpha1.ConfigMapKeyReference{{Name: "custom-rules", Key: "rules.yaml"}},
+		}
+		k := fake.NewClientBuilder().WithObjects(rules).Build()
+		hash := func() string {
+			t.Helper()
+			info, err := resolveKorrel8r(context.Background(), k, k, "openshift-operators", cfg)
+			assert.NilError(t, err)
+			return info.configMapsHash
+		}
+
+		initial := hash()
+		assert.Assert(t, initial != "")
+
+		rules.Data["other"] = "changed"
+		assert.NilError(t, k.Update(context.Background(), rules))
+		assert.Equal(t, hash(), initial)
+
+		rules.Data["rules.yaml"] = "rules: [{name: custom}]"
+		assert.NilError(t, k.Update(context.Background(), rules))
+		assert.Assert(t, hash() != initial)
+
+		plugin := &uiv1alpha1.UIPlugin{Spec: uiv1alpha1.UIPluginSpec{
+			Type:                 uiv1alpha1.TypeTroubleshootingPanel,
+			TroubleshootingPanel: &uiv1alpha1.TroubleshootingPanelConfig{Korrel8r: cfg},
+		}}
+		assert.DeepEqual(t, korrel8rConfigMaps(plugin), []string{"custom-rules"})
+		// The ConfigMaps aren't watched, the plugin is resynced.
+		assert.Equal(t, withResync(plugin, ctrl.Result{}), ctrl.Result{RequeueAfter: resyncPeriod})
+	})
+}
@@ -0,0 +1,343 @@
+package uiplugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	persesv1alpha2 "github.com/rhobs/perses-operator/api/v1alpha2"
+	appsv1 "k8s.io/api/apps/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	uiv1alpha1 "github.com/rhobs/observability-operator/pkg/apis/uiplugin/v1alpha1"
+	"github.com/rhobs/observability-operator/pkg/reconciler"
+)
+
+const (
+	PersesNotFoundReason  = "PersesNotFound"
+	PersesNotReadyReason  = "PersesNotReady"
+	PersesNotFoundMessage = "The Perses instance doesn't exist"
+
+	// persesAvailableCondition is the condition reported by the Perses
+	// operator when the instance is ready.
+	persesAvailableCondition = "Available"
+)
+
+// createKubernetesPluginInfo returns the information of a plugin on a
+// Kubernetes cluster without the OpenShift console. Only the Perses and the
+// cluster health analyzer components of the Monitoring plugin are supported.
+//
+// The information is returned along with the error when neither is enabled
+// so that the resources of a previous configuration are deleted.
+func createKubernetesPluginInfo(plugin *uiv1alpha1.UIPlugin, pluginConf UIPluginsConfiguration) (*UIPluginInfo, error) {
+	if plugin.Spec.Type != uiv1alpha1.TypeMonitoring {
+		return nil, fmt.Errorf("plugin type %s requires the OpenShift console", plugin.Spec.Type)
+	}
+
+	config := plugin.Spec.Monitoring
+	if config == nil {
+		return nil, fmt.Errorf("monitoring configuration can not be empty for plugin type %s", plugin.Spec.Type)
+	}
+
+	namespace := pluginConf.ResourcesNamespace
+	pluginInfo := &UIPluginInfo{
+		Name:              plugin.Name,
+		ResourceNamespace: namespace,
+	}
+
+	persesEnabled := validatePersesConfig(config)
+	healthAnalyzerEnabled := config.ClusterHealthAnalyzer != nil && config.ClusterHealthAnalyzer.Enabled
+	if !persesEnabled && !healthAnalyzerEnabled {
+		return pluginInfo, errors.New("perses or the cluster health analyzer must be enabled for the Monitoring plugin on Kubernetes")
+	}
+
+	// Without the console, only the features backed by the components can be
+	// enabled, and disabling them removes the components.
+	var derived []string
+	if persesEnabled {
+		derived = append(derived, "perses-dashboards")
+	}
+	if healthAnalyzerEnabled {
+		derived = append(derived, "cluster-health-analyzer")
+	}
+	pluginInfo.Features, pluginInfo.UnsupportedFeatures = pluginFeatures(plugin, nil, derived)
+
+	if slices.Contains(pluginInfo.Features, "perses-dashboards") {
+		if config.Perses.PrometheusURL == "" {
+			return pluginInfo, errors.New("perses.prometheusURL is required for the Monitoring plugin on Kubernetes")
+		}
+		if err := validatePersesInstance(config.Perses); err != nil {
+			return nil, err
+		}
+		pluginInfo.PersesImage = pluginConf.Images["perses"]
+		if pluginInfo.PersesImage == "" {
+			return nil, errors.New("no image provided for perses")
+		}
+
+		pluginInfo.Backends = []uiv1alpha1.UIPluginBackend{
+			{
+				Type:      uiv1alpha1.BackendTypePerses,
+				Name:      persesServiceName,
+				Namespace: namespace,
+				URL:       fmt.Sprintf("http://%s.%s.svc:8080", persesServiceName, namespace),
+			},
+		}
+	}
+
+	if slices.Contains(pluginInfo.Features, "cluster-health-analyzer") {
+		// There is no platform monitoring to analyze.
+		if !hasHealthAnalyzerTarget(plugin) {
+			return pluginInfo, errors.New("clusterHealthAnalyzer.target is required for the Monitoring plugin on Kubernetes")
+		}
+		pluginInfo.HealthAnalyzerImage = pluginConf.Images["health-analyzer"]
+		if pluginInfo.HealthAnalyzerImage == "" {
+			return nil, errors.New("no image provided for health-analyzer")
+		}
+	}
+
+	return pluginInfo, nil
+}
+
+// persesWatcher starts watching the Perses resources owned by the plugins
+// once the Perses CRDs are installed: on Kubernetes, the Perses operator
+// may be installed after the Observability Operator.
+type persesWatcher struct {
+	mu       sync.Mutex
+	mapper   meta.RESTMapper
+	watch    func() error
+	watching bool
+}
+
+// installed returns true when the Perses CRDs are installed. The CRDs are
+// looked up again at each call until they are found.
+func (w *persesWatcher) installed() (bool, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.watching {
+		return true, nil
+	}
+
+	persesKind := persesv1alpha2.GroupVersion.WithKind("Perses")
+	if _, err := w.mapper.RESTMapping(persesKind.GroupKind(), persesKind.Version); err != nil {
+		if meta.IsNoMatchError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	if err := w.watch(); err != nil {
+		return false, fmt.Errorf("failed to watch the Perses resources: %w", err)
+	}
+	w.watching = true
+
+	return true, nil
+}
+
+// reconcileKubernetesPlugin reconciles a plugin on a Kubernetes cluster
+// without the OpenShift console: the Perses instance, its datasources and
+// dashboards are deployed and Perses is exposed with an Ingress instead of
+// being proxied by the console.
+func (rm resourceManager) reconcileKubernetesPlugin(ctx context.Context, req ctrl.Request, plugin *uiv1alpha1.UIPlugin) (ctrl.Result, error) {
+	logger := rm.logger.WithValues("plugin", req.NamespacedName)
+
+	// The Perses resources can't be reconciled, even to be deleted, until
+	// the Perses CRDs are installed. The CRDs are looked up again at the
+	// next reconciliation.
+	persesInstalled, err := rm.persesWatcher.installed()
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	observed := &pluginObservation{}
+	pluginInfo, pluginInfoErr := createKubernetesPluginInfo(plugin, rm.pluginConf)
+	if pluginInfo != nil && pluginInfoErr == nil && pluginInfo.PersesImage != "" && !persesInstalled {
+		pluginInfoErr = errors.New("the Perses CRDs are not installed")
+	}
+	if pluginInfo != nil && pluginInfoErr == nil && persesInstalled {
+		pluginInfo.PersesDefinitions, pluginInfoErr = loadPersesDefinitions(ctx, rm.persesDefinitionsReader, plugin)
+	}
+	if pluginInfo != nil && pluginInfoErr == nil {
+		pluginInfo.OperatorWorkloads, pluginInfoErr = listOperatorWorkloads(ctx, rm.k8sClient, plugin)
+	}
+	if pluginInfo != nil && pluginInfoErr == nil {
+		pluginInfo.HealthAnalyzerComponents, pluginInfoErr = loadHealthAnalyzerComponents(ctx, rm.apiReader, plugin)
+	}
+	if pluginInfo != nil && pluginInfoErr == nil {
+		// The health analyzer keeps analyzing the last resolved target
+		// rather than failing the reconciliation of the plugin.
+		pluginInfo.HealthAnalyzerTarget, pluginInfo.HealthAnalyzerTargetErr = loadHealthAnalyzerTarget(ctx, rm.k8sClient, plugin)
+	}
+	observed.info = pluginInfo
+
+	if pluginInfo != nil {
+		plan := reconciler.NewPlan()
+		components := plan.AddAll(healthAnalyzerComponentReconcilers(plugin, *pluginInfo, false))
+		if persesInstalled {
+			components = append(components, plan.AddAll(persesComponentReconcilers(plugin, *pluginInfo, false, rm.logger))...)
+		}
+		observed.overridesErr = reconciler.ApplyOverrides(plan.Reconcilers(), plugin.Spec.Overrides)
+		if observed.overridesErr != nil {
+			logger.Info("failed to apply overrides", "err", observed.overridesErr)
+		}
+		if pluginInfoErr == nil && pluginInfo.HealthAnalyzerTargetErr == nil {
+			plan.Add(reconciler.NewInventory(plugin, pluginInfo.ResourceNamespace, plan.Reconcilers()), components...)
+		}
+		err := plan.Reconcile(ctx, rm.k8sClient, rm.scheme, reconciler.NewEventRecorder(rm.recorder, plugin))
+		if reconciler.IsStaleCacheError(err) {
+			logger.V(8).Info("skipping reconcile error", "err", err)
+			return ctrl.Result{RequeueAfter: 2 * time.Second}, nil
+		}
+		if err != nil {
+			return rm.updateStatus(ctx, req, plugin, observed, err), err
+		}
+	}
+
+	if pluginInfoErr != nil {
+		return rm.updateStatus(ctx, req, plugin, observed, pluginInfoErr), pluginInfoErr
+	}
+
+	return withResync(plugin, rm.updateStatus(ctx, req, plugin, observed, nil)), nil
+}
+
+// kubernetesAvailableCondition returns the Available condition of a plugin
+// on Kubernetes which reflects the readiness of the Perses instance and of
+// the health analyzer deployment when they are enabled.
+func (rm resourceManager) kubernetesAvailableCondition(ctx context.Context, pl *uiv1alpha1.UIPlugin, info *UIPluginInfo, recError error) metav1.Condition {
+	condition := metav1.Condition{
+		Type:               string(uiv1alpha1.AvailableCondition),
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: pl.Generation,
+	}
+
+	if info == nil {
+		condition.Reason = FailedToReconcileReason
+		if recError != nil {
+			condition.Message = recError.Error()
+		}
+		return condition
+	}
+
+	if info.PersesImage != "" {
+		perses := &persesv1alpha2.Perses{}
+		if err := rm.k8sClient.Get(ctx, client.ObjectKey{Name: persesServiceName, Namespace: info.ResourceNamespace}, perses); err != nil {
+			if !apierrors.IsNotFound(err) {
+				rm.logger.Info("Failed to get the Perses instance", "err", err)
+			}
+			condition.Reason = PersesNotFoundReason
+			condition.Message = PersesNotFoundMessage
+			return condition
+		}
+
+		available := meta.FindStatusCondition(perses.Status.Conditions, persesAvailableCondition)
+		if available == nil || available.Status != metav1.ConditionTrue {
+			condition.Reason = PersesNotReadyReason
+			if available != nil {
+				condition.Message = available.Message
+			}
+			return condition
+		}
+	}
+
+	if info.HealthAnalyzerImage != "" {
+		deployment := &appsv1.Deployment{}
+		if err := rm.k8sClient.Get(ctx, client.ObjectKey{Name: name, Namespace: info.ResourceNamespace}, deployment); err != nil {
+			if !apierrors.IsNotFound(err) {
+				rm.logger.Info("Failed to get the health analyzer deployment", "err", err)
+			}
+			deployment = nil
+		}
+		return availableCondition(pl, info, deployment, recError)
+	}
+
+	condition.Status = metav1.ConditionTrue
+	condition.Reason = AvailableReason
+	condition.Message = "The Perses instance is available"
+	return condition
+}
+
+// persesIngressReconciler returns the reconciler of the Ingress exposing the
+// Perses instance: the Ingress is created when Perses is enabled and the
+// Ingress configured, and deleted otherwise.
+func persesIngressReconciler(plugin *uiv1alpha1.UIPlugin, namespace string, config *uiv1alpha1.PersesIngress, persesEnabled bool) reconciler.Reconciler {
+	if ingress := newPersesIngress(namespace, config); persesEnabled && ingress != nil {
+		return reconciler.NewUpdater(ingress, plugin)
+	}
+	return reconciler.NewDeleter(&networkingv1.Ingress{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: networkingv1.SchemeGroupVersion.String(),
+			Kind:       "Ingress",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      persesServiceName,
+			Namespace: namespace,
+		},
+	})
+}
+
+// newPersesIngress returns the Ingress exposing the Perses instance, or nil
+// when the Ingress isn't configured. On OpenShift, Perses serves TLS and the
+// ingress controller must be configured with annotations to connect to it
+// with TLS.
+func newPersesIngress(namespace string, config *uiv1alpha1.PersesIngress) *networkingv1.Ingress {
+	if config == nil {
+		return nil
+	}
+
+	ingress := &networkingv1.Ingress{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: networkingv1.SchemeGroupVersion.String(),
+			Kind:       "Ingress",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        persesServiceName,
+			Namespace:   namespace,
+			Labels:      componentLabels(persesServiceName),
+			Annotations: config.Annotations,
+		},
+	}
+	ingress.Spec = networkingv1.IngressSpec{
+		IngressClassName: config.IngressClassName,
+		Rules: []networkingv1.IngressRule{
+			{
+				Host: config.Host,
+				IngressRuleValue: networkingv1.IngressRuleValue{
+					HTTP: &networkingv1.HTTPIngressRuleValue{
+						Paths: []networkingv1.HTTPIngressPath{
+							{
+								Path:     "/",
+								PathType: ptr.To(networkingv1.PathTypePrefix),
+								Backend: networkingv1.IngressBackend{
+									Service: &networkingv1.IngressServiceBackend{
+										Name: persesServiceName,
+										Port: networkingv1.ServiceBackendPort{Number: 8080},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	if config.TLSSecretName != "" {
+		ingress.Spec.TLS = []networkingv1.IngressTLS{
+			{
+				Hosts:      []string{config.Host},
+				SecretName: config.TLSSecretName,
+			},
+		}
+	}
+
+	return ingress
+}
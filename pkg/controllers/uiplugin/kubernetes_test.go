@@ -0,0 +1,187 @@
+package uiplugin
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"k8s.io/utils/ptr"
+
+	uiv1alpha1 "github.com/rhobs/observability-operator/pkg/apis/uiplugin/v1alpha1"
+	"github.com/rhobs/observability-operator/pkg/reconciler"
+)
+
+func newMonitoringPlugin(cfg *uiv1alpha1.MonitoringConfig) *uiv1alpha1.UIPlugin {
+	plugin := &uiv1alpha1.UIPlugin{
+		Spec: uiv1alpha1.UIPluginSpec{
+			Type:       uiv1alpha1.TypeMonitoring,
+			Monitoring: cfg,
+		},
+	}
+	plugin.Name = "monitoring"
+	return plugin
+}
+
+func TestCreateKubernetesPluginInfo(t *testing.T) {
+	pluginConf := UIPluginsConfiguration{
+		Images:             map[string]string{"perses": "quay.io/perses:latest"},
+		ResourcesNamespace: "observability",
+	}
+
+	t.Run("perses enabled", func(t *testing.T) {
+		plugin := newMonitoringPlugin(&uiv1alpha1.MonitoringConfig{
+			Perses: &uiv1alpha1.PersesReference{
+				Enabled:       true,
+				PrometheusURL: "http://prometheus.monitoring.svc:9090",
+			},
+		})
+
+		info, err := createKubernetesPluginInfo(plugin, pluginConf)
+		assert.NilError(t, err)
+		assert.Equal(t, info.PersesImage, "quay.io/perses:latest")
+		assert.Equal(t, info.ResourceNamespace, "observability")
+		assert.DeepEqual(t, info.Backends, []uiv1alpha1.UIPluginBackend{
+			{Type: uiv1alpha1.BackendTypePerses, Name: "perses", Namespace: "observability", URL: "http://perses.observability.svc:8080"},
+		})
+	})
+
+	t.Run("perses disabled", func(t *testing.T) {
+		plugin := newMonitoringPlugin(&uiv1alpha1.MonitoringConfig{
+			Perses: &uiv1alpha1.PersesReference{Enabled: false},
+		})
+
+		info, err := createKubernetesPluginInfo(plugin, pluginConf)
+		assert.ErrorContains(t, err, "perses or the cluster health analyzer must be enabled")
+		// The information is returned to delete the Perses resources.
+		assert.Assert(t, info != nil)
+	})
+
+	t.Run("health analyzer enabled", func(t *testing.T) {
+		pluginConf := UIPluginsConfiguration{
+			Images:             map[string]string{"health-analyzer": "quay.io/health-analyzer:latest"},
+			ResourcesNamespace: "observability",
+		}
+		plugin := newMonitoringPlugin(&uiv1alpha1.MonitoringConfig{
+			ClusterHealthAnalyzer: &uiv1alpha1.ClusterHealthAnalyzerReference{
+				Enabled: true,
+				Target: &uiv1alpha1.HealthAnalyzerTarget{
+					Kind:      uiv1alpha1.HealthAnalyzerTargetMonitoringStack,
+					Name:      "stack",
+					Namespace: "monitoring",
+				},
+			},
+		})
+
+		info, err := createKubernetesPluginInfo(plugin, pluginConf)
+		assert.NilError(t, err)
+		assert.Equal(t, info.HealthAnalyzerImage, "quay.io/health-analyzer:latest")
+		assert.Equal(t, info.PersesImage, "")
+
+		// There is no service CA to issue the serving certificate.
+		deployment := newHealthAnalyzerDeployment(info.ResourceNamespace, "monitoring-sa", *info)
+		removeHealthAnalyzerServingCert(deployment)
+		assert.DeepEqual(t, deployment.Spec.Template.Spec.Containers[0].Args, []string{"serve"})
+		assert.Equal(t, len(deployment.Spec.Template.Spec.Containers[0].VolumeMounts), 1)
+		assert.Equal(t, len(deployment.Spec.Template.Spec.Volumes), 1)
+	})
+
+	t.Run("health analyzer without target", func(t *testing.T) {
+		plugin := newMonitoringPlugin(&uiv1alpha1.MonitoringConfig{
+			ClusterHealthAnalyzer: &uiv1alpha1.ClusterHealthAnalyzerReference{Enabled: true},
+		})
+
+		_, err := createKubernetesPluginInfo(plugin, pluginConf)
+		assert.ErrorContains(t, err, "clusterHealthAnalyzer.target is required")
+	})
+
+	t.Run("perses feature disabled", func(t *testing.T) {
+		plugin := newMonitoringPlugin(&uiv1alpha1.MonitoringConfig{
+			Perses: &uiv1alpha1.PersesReference{
+				Enabled:       true,
+				PrometheusURL: "http://prometheus.monitoring.svc:9090",
+			},
+		})
+		plugin.Spec.Features = &uiv1alpha1.UIPluginFeatures{
+			Enabled:  []string{"acm-alerting"},
+			Disabled: []string{"perses-dashboards"},
+		}
+
+		info, err := createKubernetesPluginInfo(plugin, pluginConf)
+		assert.NilError(t, err)
+		assert.Equal(t, info.PersesImage, "")
+		assert.Equal(t, len(info.Backends), 0)
+		assert.DeepEqual(t, info.Features, []string{})
+		assert.DeepEqual(t, info.UnsupportedFeatures, []string{"acm-alerting"})
+	})
+
+	t.Run("missing Prometheus URL", func(t *testing.T) {
+		plugin := newMonitoringPlugin(&uiv1alpha1.MonitoringConfig{
+			Perses: &uiv1alpha1.PersesReference{Enabled: true},
+		})
+
+		_, err := createKubernetesPluginInfo(plugin, pluginConf)
+		assert.ErrorContains(t, err, "perses.prometheusURL is required")
+	})
+
+	t.Run("unsupported plugin type", func(t *testing.T) {
+		plugin := newLoggingPlugin(&uiv1alpha1.LoggingConfig{})
+
+		info, err := createKubernetesPluginInfo(plugin, pluginConf)
+		assert.ErrorContains(t, err, "plugin type Logging requires the OpenShift console")
+		assert.Assert(t, info == nil)
+	})
+}
+
+func TestNewPersesIngress(t *testing.T) {
+	ingress := newPersesIngress("observability", &uiv1alpha1.PersesIngress{
+		Host:             "perses.example.com",
+		IngressClassName: ptr.To("nginx"),
+		TLSSecretName:    "perses-tls",
+		Annotations:      map[string]string{"cert-manager.io/cluster-issuer": "letsencrypt"},
+	})
+
+	assert.Equal(t, ingress.Name, "perses")
+	assert.Equal(t, *ingress.Spec.IngressClassName, "nginx")
+	assert.Equal(t, ingress.Annotations["cert-manager.io/cluster-issuer"], "letsencrypt")
+	assert.Equal(t, ingress.Spec.Rules[0].Host, "perses.example.com")
+	backend := ingress.Spec.Rules[0].HTTP.Paths[0].Backend.Service
+	assert.Equal(t, backend.Name, "perses")
+	assert.Equal(t, backend.Port.Number, int32(8080))
+	assert.DeepEqual(t, ingress.Spec.TLS[0].Hosts, []string{"perses.example.com"})
+	assert.Equal(t, ingress.Spec.TLS[0].SecretName, "perses-tls")
+
+	ingress = newPersesIngress("observability", &uiv1alpha1.PersesIngress{Host: "perses.example.com"})
+	assert.Assert(t, ingress.Spec.TLS == nil)
+
+	// Without configuration, no Ingress is applied and the existing one is
+	// deleted.
+	assert.Assert(t, newPersesIngress("observability", nil) == nil)
+	plugin := newMonitoringPlugin(nil)
+	_, isDeleter := persesIngressReconciler(plugin, "observability", nil, true).(reconciler.Deleter)
+	assert.Assert(t, isDeleter)
+	_, isDeleter = persesIngressReconciler(plugin, "observability", &uiv1alpha1.PersesIngress{Host: "perses.example.com"}, false).(reconciler.Deleter)
+	assert.Assert(t, isDeleter)
+	_, isUpdater := persesIngressReconciler(plugin, "observability", &uiv1alpha1.PersesIngress{Host: "perses.example.com"}, true).(reconciler.Updater)
+	assert.Assert(t, isUpdater)
+}
+
+func TestNewPrometheusGlobalDatasource(t *testing.T) {
+	datasource := newPrometheusGlobalDatasource("", nil)
+	assert.Equal(t, datasource.Spec.Client.TLS.CaCert.CertPath, "/ca/service-ca.crt")
+
+	datasource = newPrometheusGlobalDatasource("https://prometheus.example.com", nil)
+	assert.Assert(t, *datasource.Spec.Client.TLS.Enable)
+	assert.Assert(t, datasource.Spec.Client.TLS.CaCert == nil)
+
+	datasource = newPrometheusGlobalDatasource("http://prometheus.monitoring.svc:9090", &uiv1alpha1.PersesDatasourceProxy{
+		Headers: map[string]string{"X-Scope-OrgID": "team-a"},
+		AllowedEndpoints: []uiv1alpha1.PersesAllowedEndpoint{
+			{EndpointPattern: "/api/v1/query_range", Method: "POST"},
+		},
+	})
+	assert.Assert(t, datasource.Spec.Client == nil)
+	proxy := datasource.Spec.Config.Spec.Plugin.Spec.(map[string]interface{})["proxy"].(map[string]interface{})["spec"].(map[string]interface{})
+	assert.DeepEqual(t, proxy["headers"], map[string]interface{}{"X-Scope-OrgID": "team-a"})
+	assert.DeepEqual(t, proxy["allowedEndpoints"], []interface{}{
+		map[string]interface{}{"endpointPattern": "/api/v1/query_range", "method": "POST"},
+	})
+}
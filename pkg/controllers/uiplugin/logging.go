@@ -3,7 +3,9 @@ package uiplugin
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -13,6 +15,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/dynamic"
@@ -27,16 +30,45 @@ type loggingConfig struct {
 	ShowTimezoneSelector bool          `yaml:"showTimezoneSelector,omitempty"`
 }
 
+// lokiStackBackend is a LokiStack queried by the logging plugin. An empty
+// list of tenants means that the LokiStack serves all the tenants.
+type lokiStackBackend struct {
+	types.NamespacedName
+	tenants []uiv1alpha1.LoggingTenant
+}
+
 func createLoggingPluginInfo(plugin *uiv1alpha1.UIPlugin, namespace, name, image string, features []string, ctx context.Context, dk dynamic.Interface, logger logr.Logger, korrel8rImage string) (*UIPluginInfo, error) {
-	lokiStack, err := getLokiStack(plugin, ctx, dk, logger)
+	lokiStacks, err := getLokiStacks(plugin, ctx, dk, logger)
 	if err != nil {
 		return nil, err
 	}
 
-	lokiStackName := lokiStack.Name
-	lokiStackNamespace := lokiStack.Namespace
+	config := &uiv1alpha1.LoggingConfig{}
+	if plugin.Spec.Logging != nil {
+		config = plugin.Spec.Logging.DeepCopy()
+	}
 
-	config := plugin.Spec.Logging
+	capabilities := detectLokiStackCapabilities(ctx, dk, lokiStacks, logger)
+	if config.Schema == "" && capabilities.otlp {
+		config.Schema = loggingSchemaSelect
+	}
+
+	alerts := capabilities.ruler
+	if config.Alerts != nil && config.Alerts.Enabled != nil {
+		alerts = *config.Alerts.Enabled
+	}
+	supported, derived := features, features
+	if !alerts {
+		derived = slices.DeleteFunc(slices.Clone(features), func(f string) bool {
+			return f == "alerts" || f == "dev-alerts"
+		})
+		// The alerts can only be enabled by the overrides when the ruler
+		// isn't detected, not when they are disabled by the configuration.
+		if config.Alerts != nil && config.Alerts.Enabled != nil {
+			supported = derived
+		}
+	}
+	features, unsupported := pluginFeatures(plugin, supported, derived)
 
 	configYaml, err := marshalLoggingPluginConfig(config)
 	if err != nil {
@@ -51,17 +83,52 @@ func createLoggingPluginInfo(plugin *uiv1alpha1.UIPlugin, namespace, name, image
 		extraArgs = append(extraArgs, fmt.Sprintf("-features=%s", strings.Join(features, ",")))
 	}
 
-	proxies := []PluginProxy{
-		{
+	// The plugin sends its queries to the "backend" proxy. When the tenants
+	// are stored in different LokiStacks, the proxy is a router which
+	// forwards the queries to the LokiStack of the tenant.
+	var (
+		router   *lokiStackRouter
+		backend  PluginProxy
+		backends []uiv1alpha1.UIPluginBackend
+	)
+	if len(lokiStacks) == 1 && len(lokiStacks[0].tenants) == 0 {
+		backend = PluginProxy{
 			Alias:            "backend",
-			ServiceName:      fmt.Sprintf("%s-gateway-http", lokiStackName),
-			ServiceNamespace: lokiStackNamespace,
+			ServiceName:      fmt.Sprintf("%s-gateway-http", lokiStacks[0].Name),
+			ServiceNamespace: lokiStacks[0].Namespace,
 			ServicePort:      8080,
 			Authorize:        true,
-		},
+		}
+	} else {
+		router = newLokiStackRouter(name, lokiStacks)
+		backend = PluginProxy{
+			Alias:            "backend",
+			ServiceName:      router.name,
+			ServiceNamespace: namespace,
+			ServicePort:      lokiStackRouterPort,
+			Authorize:        true,
+		}
+	}
+	proxies := []PluginProxy{backend}
+	if alerts {
+		// The log-based alerts views read the rules and alerts of the
+		// Loki ruler which is served by the same gateway.
+		ruler := backend
+		ruler.Alias = "ruler"
+		proxies = append(proxies, ruler)
+	}
+	for _, lokiStack := range lokiStacks {
+		serviceName := fmt.Sprintf("%s-gateway-http", lokiStack.Name)
+		backends = append(backends, uiv1alpha1.UIPluginBackend{
+			Type:      uiv1alpha1.BackendTypeLokiStack,
+			Name:      lokiStack.Name,
+			Namespace: lokiStack.Namespace,
+			URL:       fmt.Sprintf("https://%s.%s.svc:8080", serviceName, lokiStack.Namespace),
+		})
 	}
 
 	if korrel8rImage != "" {
+		backends = append(backends, serviceBackend(uiv1alpha1.BackendTypeKorrel8r, korrel8rName, namespace, port))
 		proxies = append(proxies, PluginProxy{
 			Alias:            "korrel8r",
 			ServiceName:      korrel8rName,
@@ -79,7 +146,9 @@ func createLoggingPluginInfo(plugin *uiv1alpha1.UIPlugin, namespace, name, image
 		ExtraArgs:         extraArgs,
 		ResourceNamespace: namespace,
 		Proxies:           proxies,
+		Backends:          backends,
 		Korrel8rImage:     korrel8rImage,
+		LokiStackRouter:   router,
 		ConfigMap: &corev1.ConfigMap{
 			TypeMeta: metav1.TypeMeta{
 				APIVersion: corev1.SchemeGroupVersion.String(),
@@ -98,6 +167,25 @@ func createLoggingPluginInfo(plugin *uiv1alpha1.UIPlugin, namespace, name, image
 			loggingClusterRole("infrastructure"),
 			loggingClusterRole("audit"),
 		},
+		LoggingCapabilitiesErr: capabilities.err,
+		LoggingStatus: &uiv1alpha1.LoggingStatus{
+			Schema: config.Schema,
+			OTLP:   capabilities.otlp,
+			Ruler:  capabilities.ruler,
+			Alerts: alerts,
+		},
+	}
+	pluginInfo.Features = features
+	pluginInfo.UnsupportedFeatures = unsupported
+	if pluginInfo.LoggingStatus.Schema == "" {
+		pluginInfo.LoggingStatus.Schema = loggingSchemaViaQ
+	}
+
+	if alerts {
+		rulesRole := loggingRulesClusterRole(name)
+		pluginInfo.ClusterRoles = append(pluginInfo.ClusterRoles, rulesRole)
+		pluginInfo.ClusterRoleBindings = append(pluginInfo.ClusterRoleBindings,
+			newClusterRoleBinding(namespace, name+serviceAccountSuffix, rulesRole.Name, rulesRole.Name))
 	}
 
 	return pluginInfo, nil
@@ -178,10 +266,143 @@ func loggingClusterRole(tenant string) *rbacv1.ClusterRole {
 	}
 }
 
+// loggingRulesClusterRole grants the plugin read access to the LokiStack
+// alerting and recording rules which are shown in the log-based alerts views.
+func loggingRulesClusterRole(pluginName string) *rbacv1.ClusterRole {
+	return &rbacv1.ClusterRole{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: rbacv1.SchemeGroupVersion.String(),
+			Kind:       "ClusterRole",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: pluginName + "-logging-rules-view",
+		},
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{
+					"loki.grafana.com",
+				},
+				Resources: []string{
+					"alertingrules",
+					"recordingrules",
+				},
+				Verbs: []string{
+					"get",
+					"list",
+					"watch",
+				},
+			},
+		},
+	}
+}
+
+const (
+	loggingSchemaViaQ   = "viaq"
+	loggingSchemaSelect = "select"
+)
+
+// lokiStackCapabilities are the capabilities of the LokiStacks queried by
+// the logging plugin.
+type lokiStackCapabilities struct {
+	// otlp is true when a LokiStack is configured to ingest OTLP logs.
+	otlp bool
+	// ruler is true when the ruler of a LokiStack is enabled.
+	ruler bool
+	// err reports the LokiStacks which couldn't be inspected.
+	err error
+}
+
+// detectLokiStackCapabilities inspects the LokiStack resources. A LokiStack
+// which can't be read is ignored and reported in the error of the
+// capabilities.
+func detectLokiStackCapabilities(ctx context.Context, client dynamic.Interface, lokiStacks []lokiStackBackend, logger logr.Logger) lokiStackCapabilities {
+	var (
+		capabilities lokiStackCapabilities
+		errs         []error
+	)
+	for _, ls := range lokiStacks {
+		lokiStack, err := client.Resource(lokiStackResource).Namespace(ls.Namespace).Get(ctx, ls.Name, metav1.GetOptions{})
+		if err != nil {
+			logger.Info("Failed to get LokiStack, its capabilities are not detected", "lokistack", ls.NamespacedName.String(), "error", err.Error())
+			errs = append(errs, fmt.Errorf("failed to get LokiStack %s: %w", ls.NamespacedName, err))
+			continue
+		}
+
+		if rulerEnabled, _, _ := unstructured.NestedBool(lokiStack.Object, "spec", "rules", "enabled"); rulerEnabled {
+			capabilities.ruler = true
+		}
+
+		for _, fields := range [][]string{
+			{"spec", "limits", "global", "otlp"},
+			{"spec", "tenants", "openshift", "otlp"},
+		} {
+			if _, found, _ := unstructured.NestedFieldNoCopy(lokiStack.Object, fields...); found {
+				capabilities.otlp = true
+			}
+		}
+		if tenants, _, _ := unstructured.NestedMap(lokiStack.Object, "spec", "limits", "tenants"); tenants != nil {
+			for _, limits := range tenants {
+				if limits, ok := limits.(map[string]any); ok && limits["otlp"] != nil {
+					capabilities.otlp = true
+				}
+			}
+		}
+	}
+	capabilities.err = errors.Join(errs...)
+
+	return capabilities
+}
+
 var lokiStackResource = schema.GroupVersionResource{
 	Group: "loki.grafana.com", Version: "v1", Resource: "lokistacks",
 }
 
+// getLokiStacks returns the LokiStack resources to use for the logging plugin.
+// When no list of LokiStacks is configured, a single LokiStack serves all the
+// tenants.
+func getLokiStacks(plugin *uiv1alpha1.UIPlugin, ctx context.Context, client dynamic.Interface, logger logr.Logger) ([]lokiStackBackend, error) {
+	config := plugin.Spec.Logging
+	if config == nil || len(config.LokiStacks) == 0 {
+		lokiStack, err := getLokiStack(plugin, ctx, client, logger)
+		if err != nil {
+			return nil, err
+		}
+		return []lokiStackBackend{{NamespacedName: *lokiStack}}, nil
+	}
+
+	var (
+		lokiStacks = make([]lokiStackBackend, 0, len(config.LokiStacks))
+		seen       = map[types.NamespacedName]struct{}{}
+		servedBy   = map[uiv1alpha1.LoggingTenant]types.NamespacedName{}
+	)
+	for _, ref := range config.LokiStacks {
+		nn := types.NamespacedName{Name: ref.Name, Namespace: ref.Namespace}
+		if nn.Namespace == "" {
+			nn.Namespace = OpenshiftLoggingNs
+		}
+
+		if _, found := seen[nn]; found {
+			return nil, fmt.Errorf("LokiStack %s is configured more than once", nn)
+		}
+		seen[nn] = struct{}{}
+
+		for _, tenant := range ref.Tenants {
+			if other, found := servedBy[tenant]; found {
+				return nil, fmt.Errorf("tenant %q is served by both LokiStack %s and %s", tenant, other, nn)
+			}
+			servedBy[tenant] = nn
+		}
+
+		if _, err := client.Resource(lokiStackResource).Namespace(nn.Namespace).Get(ctx, nn.Name, metav1.GetOptions{}); err != nil {
+			return nil, fmt.Errorf("failed to get LokiStack %s in namespace %s: %w", nn.Name, nn.Namespace, err)
+		}
+
+		lokiStacks = append(lokiStacks, lokiStackBackend{NamespacedName: nn, tenants: ref.Tenants})
+	}
+
+	return lokiStacks, nil
+}
+
 // getLokiStack returns the LokiStack resource to use for the logging plugin.
 // It either uses the explicitly configured LokiStack or discovers one from the cluster.
 func getLokiStack(plugin *uiv1alpha1.UIPlugin, ctx context.Context, client dynamic.Interface, logger logr.Logger) (*types.NamespacedName, error) {
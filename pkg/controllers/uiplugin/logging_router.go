@@ -0,0 +1,244 @@
+package uiplugin
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	lokiStackRouterPort      = 8080
+	lokiStackRouterConfigDir = "/etc/lokistack-router"
+	lokiStackRouterCertDir   = "/etc/lokistack-router/tls"
+	// lokiStackRouterServiceCA is the CA of the service serving certificates
+	// mounted with the service account token on OpenShift.
+	lokiStackRouterServiceCA = "/var/run/secrets/kubernetes.io/serviceaccount/service-ca.crt"
+)
+
+// lokiStackRouter routes the requests of the logging plugin to the LokiStack
+// serving the queried tenant. The plugin sends all its requests to the
+// "backend" proxy with the tenant as path prefix (/api/logs/v1/<tenant>/),
+// the router is this proxy when the tenants are stored in different
+// LokiStacks.
+type lokiStackRouter struct {
+	name   string
+	image  string
+	routes []lokiStackRoute
+}
+
+// lokiStackRoute maps a tenant to the gateway of its LokiStack.
+type lokiStackRoute struct {
+	Tenant  string
+	Gateway string
+}
+
+func newLokiStackRouter(pluginName string, lokiStacks []lokiStackBackend) *lokiStackRouter {
+	router := &lokiStackRouter{name: pluginName + "-lokistack-router"}
+	for _, lokiStack := range lokiStacks {
+		for _, tenant := range lokiStack.tenants {
+			router.routes = append(router.routes, lokiStackRoute{
+				Tenant:  string(tenant),
+				Gateway: fmt.Sprintf("%s-gateway-http.%s.svc:8080", lokiStack.Name, lokiStack.Namespace),
+			})
+		}
+	}
+	return router
+}
+
+var lokiStackRouterConfigTemplate = template.Must(template.New("nginx.conf").Parse(`pid /tmp/nginx.pid;
+error_log /dev/stderr warn;
+
+events {}
+
+http {
+  access_log off;
+  client_body_temp_path /tmp/client_body;
+  proxy_temp_path /tmp/proxy;
+  fastcgi_temp_path /tmp/fastcgi;
+  uwsgi_temp_path /tmp/uwsgi;
+  scgi_temp_path /tmp/scgi;
+
+  server {
+    listen {{ .Port }} ssl;
+    ssl_certificate {{ .CertDir }}/tls.crt;
+    ssl_certificate_key {{ .CertDir }}/tls.key;
+
+    proxy_ssl_verify on;
+    proxy_ssl_server_name on;
+    proxy_ssl_trusted_certificate {{ .ServiceCA }};
+    proxy_read_timeout 5m;
+    proxy_buffering off;
+{{ range .Routes }}
+    location /api/logs/v1/{{ .Tenant }}/ {
+      proxy_pass https://{{ .Gateway }};
+    }
+{{ end }}
+    location / {
+      return 404;
+    }
+  }
+}
+`))
+
+func (r *lokiStackRouter) configMap(namespace string) (*corev1.ConfigMap, error) {
+	buf := &bytes.Buffer{}
+	if err := lokiStackRouterConfigTemplate.Execute(buf, map[string]any{
+		"Port":      lokiStackRouterPort,
+		"CertDir":   lokiStackRouterCertDir,
+		"ServiceCA": lokiStackRouterServiceCA,
+		"Routes":    r.routes,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to generate the LokiStack router configuration: %w", err)
+	}
+
+	return &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: corev1.SchemeGroupVersion.String(),
+			Kind:       "ConfigMap",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      r.name,
+			Namespace: namespace,
+			Labels:    componentLabels(r.name),
+		},
+		Data: map[string]string{
+			"nginx.conf": buf.String(),
+		},
+	}, nil
+}
+
+func (r *lokiStackRouter) deployment(namespace string, config *corev1.ConfigMap) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: appsv1.SchemeGroupVersion.String(),
+			Kind:       "Deployment",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      r.name,
+			Namespace: namespace,
+			Labels:    componentLabels(r.name),
+		},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: componentLabels(r.name),
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      r.name,
+					Namespace: namespace,
+					Labels:    componentLabels(r.name),
+					// The routes are only read at startup.
+					Annotations: map[string]string{
+						annotationPrefix + "config-hash": computeConfigMapHash(config),
+					},
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name:    "router",
+							Image:   r.image,
+							Command: []string{"nginx", "-c", lokiStackRouterConfigDir + "/nginx.conf", "-g", "daemon off;"},
+							Ports: []corev1.ContainerPort{
+								{
+									ContainerPort: lokiStackRouterPort,
+									Protocol:      corev1.ProtocolTCP,
+								},
+							},
+							SecurityContext: &corev1.SecurityContext{
+								RunAsNonRoot:             ptr.To(true),
+								ReadOnlyRootFilesystem:   ptr.To(true),
+								AllowPrivilegeEscalation: ptr.To(false),
+								Capabilities: &corev1.Capabilities{
+									Drop: []corev1.Capability{
+										"ALL",
+									},
+								},
+								SeccompProfile: &corev1.SeccompProfile{
+									Type: corev1.SeccompProfileTypeRuntimeDefault,
+								},
+							},
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      "config",
+									ReadOnly:  true,
+									MountPath: lokiStackRouterConfigDir,
+								},
+								{
+									Name:      servingCertVolumeName,
+									ReadOnly:  true,
+									MountPath: lokiStackRouterCertDir,
+								},
+								{
+									Name:      "tmp",
+									MountPath: "/tmp",
+								},
+							},
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name: "config",
+							VolumeSource: corev1.VolumeSource{
+								ConfigMap: &corev1.ConfigMapVolumeSource{
+									LocalObjectReference: corev1.LocalObjectReference{
+										Name: r.name,
+									},
+								},
+							},
+						},
+						{
+							Name: servingCertVolumeName,
+							VolumeSource: corev1.VolumeSource{
+								Secret: &corev1.SecretVolumeSource{
+									SecretName:  r.name,
+									DefaultMode: ptr.To(int32(420)),
+								},
+							},
+						},
+						{
+							Name: "tmp",
+							VolumeSource: corev1.VolumeSource{
+								EmptyDir: &corev1.EmptyDirVolumeSource{},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (r *lokiStackRouter) service(namespace string) *corev1.Service {
+	return &corev1.Service{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: corev1.SchemeGroupVersion.String(),
+			Kind:       "Service",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      r.name,
+			Namespace: namespace,
+			Labels:    componentLabels(r.name),
+			Annotations: map[string]string{
+				"service.beta.openshift.io/serving-cert-secret-name": r.name,
+			},
+		},
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{
+				{
+					Port:       lokiStackRouterPort,
+					Name:       "https",
+					Protocol:   corev1.ProtocolTCP,
+					TargetPort: intstr.FromInt32(lokiStackRouterPort),
+				},
+			},
+			Selector: componentLabels(r.name),
+			Type:     corev1.ServiceTypeClusterIP,
+		},
+	}
+}
@@ -0,0 +1,201 @@
+package uiplugin
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"gotest.tools/v3/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/utils/ptr"
+
+	uiv1alpha1 "github.com/rhobs/observability-operator/pkg/apis/uiplugin/v1alpha1"
+)
+
+func newLokiStack(name, namespace string) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion("loki.grafana.com/v1")
+	u.SetKind("LokiStack")
+	u.SetName(name)
+	u.SetNamespace(namespace)
+	return u
+}
+
+func newLoggingPlugin(cfg *uiv1alpha1.LoggingConfig) *uiv1alpha1.UIPlugin {
+	plugin := &uiv1alpha1.UIPlugin{
+		Spec: uiv1alpha1.UIPluginSpec{
+			Type:    uiv1alpha1.TypeLogging,
+			Logging: cfg,
+		},
+	}
+	plugin.Name = "logging"
+	return plugin
+}
+
+func TestCreateLoggingPluginInfo(t *testing.T) {
+	dk := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(
+		runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{lokiStackResource: "LokiStackList"},
+		newLokiStack("apps", OpenshiftLoggingNs),
+		newLokiStack("infra", "infra-logs"),
+	)
+
+	t.Run("single LokiStack", func(t *testing.T) {
+		plugin := newLoggingPlugin(&uiv1alpha1.LoggingConfig{
+			LokiStack: &uiv1alpha1.LokiStackReference{Name: "apps"},
+		})
+
+		info, err := createLoggingPluginInfo(plugin, "openshift-operators", plugin.Name, "quay.io/logging:latest", nil, context.Background(), dk, logr.Discard(), "")
+		assert.NilError(t, err)
+
+		assert.Equal(t, len(info.Proxies), 1)
+		assert.Equal(t, info.Proxies[0].Alias, "backend")
+		assert.Equal(t, info.Proxies[0].ServiceName, "apps-gateway-http")
+		assert.Equal(t, info.Proxies[0].ServiceNamespace, OpenshiftLoggingNs)
+		assert.Equal(t, info.ConfigMap.Data["config.yaml"], "")
+		assert.Assert(t, info.LokiStackRouter == nil)
+	})
+
+	t.Run("LokiStack per tenant", func(t *testing.T) {
+		plugin := newLoggingPlugin(&uiv1alpha1.LoggingConfig{
+			LokiStacks: []uiv1alpha1.LokiStackBackend{
+				{
+					Name:    "apps",
+					Tenants: []uiv1alpha1.LoggingTenant{uiv1alpha1.LoggingTenantApplication},
+				},
+				{
+					Name:      "infra",
+					Namespace: "infra-logs",
+					Tenants:   []uiv1alpha1.LoggingTenant{uiv1alpha1.LoggingTenantInfrastructure, uiv1alpha1.LoggingTenantAudit},
+				},
+			},
+		})
+
+		info, err := createLoggingPluginInfo(plugin, "openshift-operators", plugin.Name, "quay.io/logging:latest", nil, context.Background(), dk, logr.Discard(), "")
+		assert.NilError(t, err)
+
+		assert.Equal(t, len(info.Proxies), 1)
+		assert.Equal(t, info.Proxies[0].Alias, "backend")
+		assert.Equal(t, info.Proxies[0].ServiceName, "logging-lokistack-router")
+		assert.Equal(t, info.Proxies[0].ServiceNamespace, "openshift-operators")
+		assert.Equal(t, info.ConfigMap.Data["config.yaml"], "")
+
+		assert.Equal(t, len(info.Backends), 2)
+		assert.Equal(t, info.Backends[1].URL, "https://infra-gateway-http.infra-logs.svc:8080")
+
+		assert.Assert(t, info.LokiStackRouter != nil)
+		cm, err := info.LokiStackRouter.configMap("openshift-operators")
+		assert.NilError(t, err)
+		config := cm.Data["nginx.conf"]
+		for _, route := range []string{
+			"location /api/logs/v1/application/ {\n      proxy_pass https://apps-gateway-http.openshift-logging.svc:8080;",
+			"location /api/logs/v1/infrastructure/ {\n      proxy_pass https://infra-gateway-http.infra-logs.svc:8080;",
+			"location /api/logs/v1/audit/ {\n      proxy_pass https://infra-gateway-http.infra-logs.svc:8080;",
+		} {
+			assert.Assert(t, strings.Contains(config, route), config)
+		}
+		assert.Equal(t, info.LokiStackRouter.service("openshift-operators").Name, "logging-lokistack-router")
+	})
+
+	t.Run("tenant served by several LokiStacks", func(t *testing.T) {
+		plugin := newLoggingPlugin(&uiv1alpha1.LoggingConfig{
+			LokiStacks: []uiv1alpha1.LokiStackBackend{
+				{
+					Name:    "apps",
+					Tenants: []uiv1alpha1.LoggingTenant{uiv1alpha1.LoggingTenantApplication},
+				},
+				{
+					Name:      "infra",
+					Namespace: "infra-logs",
+					Tenants:   []uiv1alpha1.LoggingTenant{uiv1alpha1.LoggingTenantApplication},
+				},
+			},
+		})
+
+		_, err := createLoggingPluginInfo(plugin, "openshift-operators", plugin.Name, "quay.io/logging:latest", nil, context.Background(), dk, logr.Discard(), "")
+		assert.ErrorContains(t, err, `tenant "application" is served by both LokiStack openshift-logging/apps and infra-logs/infra`)
+	})
+
+	t.Run("missing LokiStack", func(t *testing.T) {
+		plugin := newLoggingPlugin(&uiv1alpha1.LoggingConfig{
+			LokiStacks: []uiv1alpha1.LokiStackBackend{
+				{
+					Name:    "audit",
+					Tenants: []uiv1alpha1.LoggingTenant{uiv1alpha1.LoggingTenantAudit},
+				},
+			},
+		})
+
+		_, err := createLoggingPluginInfo(plugin, "openshift-operators", plugin.Name, "quay.io/logging:latest", nil, context.Background(), dk, logr.Discard(), "")
+		assert.ErrorContains(t, err, "failed to get LokiStack audit in namespace openshift-logging")
+	})
+
+	t.Run("LokiStack capabilities", func(t *testing.T) {
+		otlpStack := newLokiStack("otlp", OpenshiftLoggingNs)
+		otlpStack.Object["spec"] = map[string]any{
+			"rules":  map[string]any{"enabled": true},
+			"limits": map[string]any{"global": map[string]any{"otlp": map[string]any{}}},
+		}
+		dk := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(
+			runtime.NewScheme(),
+			map[schema.GroupVersionResource]string{lokiStackResource: "LokiStackList"},
+			otlpStack,
+		)
+		features := []string{"dev-console", "alerts", "dev-alerts"}
+
+		plugin := newLoggingPlugin(&uiv1alpha1.LoggingConfig{
+			LokiStack: &uiv1alpha1.LokiStackReference{Name: "otlp"},
+		})
+		info, err := createLoggingPluginInfo(plugin, "openshift-operators", plugin.Name, "quay.io/logging:latest", features, context.Background(), dk, logr.Discard(), "")
+		assert.NilError(t, err)
+
+		assert.DeepEqual(t, info.LoggingStatus, &uiv1alpha1.LoggingStatus{Schema: "select", OTLP: true, Ruler: true, Alerts: true})
+		assert.Equal(t, info.ConfigMap.Data["config.yaml"], "schema: select\n")
+		assert.Assert(t, slices.Contains(info.ExtraArgs, "-features=dev-console,alerts,dev-alerts"))
+		assert.Equal(t, info.ClusterRoles[len(info.ClusterRoles)-1].Name, "logging-logging-rules-view")
+		assert.Equal(t, len(info.ClusterRoleBindings), 1)
+		assert.Equal(t, info.ClusterRoleBindings[0].RoleRef.Name, "logging-logging-rules-view")
+		assert.Equal(t, info.ClusterRoleBindings[0].Subjects[0].Name, "logging-sa")
+		assert.Equal(t, len(info.Proxies), 2)
+		assert.Equal(t, info.Proxies[1].Alias, "ruler")
+		assert.Equal(t, info.Proxies[1].ServiceName, "otlp-gateway-http")
+		assert.NilError(t, info.LoggingCapabilitiesErr)
+
+		plugin = newLoggingPlugin(&uiv1alpha1.LoggingConfig{
+			LokiStack: &uiv1alpha1.LokiStackReference{Name: "otlp"},
+			Schema:    "otel",
+			Alerts:    &uiv1alpha1.LoggingAlertsConfig{Enabled: ptr.To(false)},
+		})
+		info, err = createLoggingPluginInfo(plugin, "openshift-operators", plugin.Name, "quay.io/logging:latest", features, context.Background(), dk, logr.Discard(), "")
+		assert.NilError(t, err)
+
+		assert.DeepEqual(t, info.LoggingStatus, &uiv1alpha1.LoggingStatus{Schema: "otel", OTLP: true, Ruler: true, Alerts: false})
+		assert.Assert(t, slices.Contains(info.ExtraArgs, "-features=dev-console"))
+		assert.Equal(t, len(info.ClusterRoles), 3)
+		assert.Equal(t, len(info.ClusterRoleBindings), 0)
+		assert.Equal(t, len(info.Proxies), 1)
+		assert.DeepEqual(t, features, []string{"dev-console", "alerts", "dev-alerts"})
+
+		// The overrides don't enable the alerts disabled by the configuration.
+		plugin.Spec.Features = &uiv1alpha1.UIPluginFeatures{Enabled: []string{"alerts"}}
+		info, err = createLoggingPluginInfo(plugin, "openshift-operators", plugin.Name, "quay.io/logging:latest", features, context.Background(), dk, logr.Discard(), "")
+		assert.NilError(t, err)
+		assert.DeepEqual(t, info.Features, []string{"dev-console"})
+		assert.DeepEqual(t, info.UnsupportedFeatures, []string{"alerts"})
+	})
+
+	t.Run("LokiStack capabilities not detected", func(t *testing.T) {
+		capabilities := detectLokiStackCapabilities(context.Background(), dk, []lokiStackBackend{
+			{NamespacedName: types.NamespacedName{Name: "apps", Namespace: OpenshiftLoggingNs}},
+			{NamespacedName: types.NamespacedName{Name: "missing", Namespace: OpenshiftLoggingNs}},
+		}, logr.Discard())
+		assert.ErrorContains(t, capabilities.err, "failed to get LokiStack openshift-logging/missing")
+		assert.Assert(t, !capabilities.ruler)
+	})
+}
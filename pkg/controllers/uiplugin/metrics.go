@@ -0,0 +1,41 @@
+package uiplugin
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// compatibilityInfo exposes the entries of the compatibility matrix used by
+// the operator, one series per entry.
+var compatibilityInfo = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "observability_operator_uiplugin_compatibility_info",
+		Help: "Entries of the compatibility matrix of the UI plugins. The source label is the file from which the matrix is loaded, built-in for the matrix of the operator.",
+	},
+	[]string{"source", "plugin_type", "min_cluster_version", "max_cluster_version", "image_key", "image", "support_level"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(compatibilityInfo)
+}
+
+// setCompatibilityInfo replaces the series of the compatibility matrix
+// metric with the entries of matrix.
+func setCompatibilityInfo(matrix []CompatibilityEntry, source string) {
+	if source == "" {
+		source = "built-in"
+	}
+
+	compatibilityInfo.Reset()
+	for _, entry := range matrix {
+		compatibilityInfo.WithLabelValues(
+			source,
+			string(entry.PluginType),
+			entry.MinClusterVersion,
+			entry.MaxClusterVersion,
+			entry.ImageKey,
+			entry.Image,
+			string(entry.SupportLevel),
+		).Set(1)
+	}
+}
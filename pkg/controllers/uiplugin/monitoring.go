@@ -1,11 +1,17 @@
 package uiplugin
 
 import (
+	"errors"
 	"fmt"
+	"net"
+	"path"
+	"regexp"
+	"slices"
 	"strings"
 
 	persesv1alpha2 "github.com/rhobs/perses-operator/api/v1alpha2"
 	persesconfig "github.com/rhobs/perses/pkg/model/api/config"
+	"github.com/rhobs/perses/pkg/model/api/v1/secret"
 	"golang.org/x/mod/semver"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
@@ -23,22 +29,17 @@ const (
 
 /*
 Requirements for ACM enablement
-1. UIPlugin configuration requires acm.enabled, acm.thanosQuerier.Url, and acm.alertmanager.Url
+1. UIPlugin configuration requires acm.enabled, acm.thanosQuerier.Url, and acm.alertmanager.Url (or acm.discover)
 2. OpenShift Container Platform requirement: v4.14+
 */
-func validateACMConfig(config *uiv1alpha1.MonitoringConfig) bool {
-	enabled := config.ACM != nil && config.ACM.Enabled
-
-	if !enabled {
+func validateACMConfig(acm *uiv1alpha1.ACMStatus) bool {
+	// acm is nil when acm.enabled is false
+	if acm == nil {
 		return false
 	}
 
-	// alertManager and thanosQuerier url configurations are required to enable 'acm-alerting'
-	validAlertManagerUrl := config.ACM.Alertmanager.Url != ""
-	validThanosQuerierUrl := config.ACM.ThanosQuerier.Url != ""
-	isValidAcmAlertingConfig := validAlertManagerUrl && validThanosQuerierUrl
-
-	return isValidAcmAlertingConfig && enabled
+	// alertManager and thanosQuerier urls are required to enable 'acm-alerting'
+	return acm.AlertmanagerURL != "" && acm.ThanosQuerierURL != ""
 }
 
 func validatePersesConfig(config *uiv1alpha1.MonitoringConfig) bool {
@@ -106,12 +107,17 @@ func addPersesProxy(pluginInfo *UIPluginInfo, namespace string) {
 		ServicePort:      8080,
 		Authorize:        true,
 	})
+	pluginInfo.Backends = append(pluginInfo.Backends, serviceBackend(uiv1alpha1.BackendTypePerses, persesServiceName, namespace, 8080))
 }
 
-func addAcmAlertingProxy(pluginInfo *UIPluginInfo, name string, namespace string, config *uiv1alpha1.MonitoringConfig) {
+func addAcmAlertingProxy(pluginInfo *UIPluginInfo, name string, namespace string, acm *uiv1alpha1.ACMStatus) {
 	pluginInfo.ExtraArgs = append(pluginInfo.ExtraArgs,
-		fmt.Sprintf("-alertmanager=%s", config.ACM.Alertmanager.Url),
-		fmt.Sprintf("-thanos-querier=%s", config.ACM.ThanosQuerier.Url),
+		fmt.Sprintf("-alertmanager=%s", acm.AlertmanagerURL),
+		fmt.Sprintf("-thanos-querier=%s", acm.ThanosQuerierURL),
+	)
+	pluginInfo.Backends = append(pluginInfo.Backends,
+		uiv1alpha1.UIPluginBackend{Type: uiv1alpha1.BackendTypeAlertmanager, URL: acm.AlertmanagerURL},
+		uiv1alpha1.UIPluginBackend{Type: uiv1alpha1.BackendTypeThanosQuerier, URL: acm.ThanosQuerierURL},
 	)
 	pluginInfo.Proxies = append(pluginInfo.Proxies,
 		PluginProxy{
@@ -131,14 +137,14 @@ func addAcmAlertingProxy(pluginInfo *UIPluginInfo, name string, namespace string
 	)
 }
 
-func createMonitoringPluginInfo(plugin *uiv1alpha1.UIPlugin, namespace, name, image string, features []string, clusterVersion string, healthAnalyzerImage string, persesImage string) (*UIPluginInfo, error) {
+func createMonitoringPluginInfo(plugin *uiv1alpha1.UIPlugin, namespace, name, image string, features []string, clusterVersion string, healthAnalyzerImage string, persesImage string, acm *uiv1alpha1.ACMStatus) (*UIPluginInfo, error) {
 	config := plugin.Spec.Monitoring
 	if config == nil {
 		return nil, fmt.Errorf("monitoring configuration can not be empty for plugin type %s", plugin.Spec.Type)
 	}
 
 	// Validate feature configuration and cluster conditions support enablement
-	isValidAcmConfig := validateACMConfig(config)
+	isValidAcmConfig := validateACMConfig(acm)
 	isValidPersesConfig := validatePersesConfig(config)
 	isValidIncidentsConfig := validateIncidentsConfig(config, clusterVersion)
 	isValidHealthAnalyzerConfig := validateHealthanalyzerConfig(config, clusterVersion)
@@ -146,29 +152,43 @@ func createMonitoringPluginInfo(plugin *uiv1alpha1.UIPlugin, namespace, name, im
 	atLeastOneValidConfig := isValidAcmConfig || isValidPersesConfig || isValidIncidentsConfig || isValidHealthAnalyzerConfig
 
 	pluginInfo := getBasePluginInfo(namespace, name, image)
+	pluginInfo.ACMStatus = acm
 	if !atLeastOneValidConfig {
 		pluginInfo.AreMonitoringFeatsDisabled = true
 		// pluginInfo must be return to controller to delete related components
 		return pluginInfo, fmt.Errorf("all uiplugin monitoring configurations are invalid or not supported in this cluster version")
 	}
 
-	features = append(features, "mcp-overview")
-
-	//  Add proxies and feature flags
+	// features lists the features supported by the compatibility matrix,
+	// derived is extended with the features enabled by the configuration.
+	derived := append(slices.Clone(features), "mcp-overview")
 	if isValidAcmConfig {
-		addAcmAlertingProxy(pluginInfo, name, namespace, config)
-		features = append(features, "acm-alerting")
+		derived = append(derived, "acm-alerting")
 	}
 	if isValidPersesConfig {
+		if err := validatePersesInstance(config.Perses); err != nil {
+			return nil, err
+		}
+		derived = append(derived, "perses-dashboards")
+	}
+	if isValidIncidentsConfig || isValidHealthAnalyzerConfig {
+		derived = append(derived, "cluster-health-analyzer")
+	}
+	pluginInfo.Features, pluginInfo.UnsupportedFeatures = pluginFeatures(plugin, features, derived)
+
+	// The components backing the features disabled by the overrides aren't
+	// deployed.
+	if slices.Contains(pluginInfo.Features, "acm-alerting") {
+		addAcmAlertingProxy(pluginInfo, name, namespace, acm)
+	}
+	if slices.Contains(pluginInfo.Features, "perses-dashboards") {
 		addPersesProxy(pluginInfo, namespace)
-		features = append(features, "perses-dashboards")
 		pluginInfo.PersesImage = persesImage
 	}
-	if isValidIncidentsConfig || isValidHealthAnalyzerConfig {
+	if slices.Contains(pluginInfo.Features, "cluster-health-analyzer") {
 		pluginInfo.HealthAnalyzerImage = healthAnalyzerImage
-		features = append(features, "cluster-health-analyzer")
 	}
-	addFeatureFlags(pluginInfo, features)
+	addFeatureFlags(pluginInfo, pluginInfo.Features)
 
 	return pluginInfo, nil
 }
@@ -216,9 +236,19 @@ func newMonitoringService(name string, namespace string) *corev1.Service {
 	}
 }
 
-func newPerses(namespace string, persesImage string) *persesv1alpha2.Perses {
+// persesPodSelector selects the pods of the Perses instance deployed by the
+// Perses operator.
+var persesPodSelector = map[string]string{
+	"app.kubernetes.io/name":     "perses",
+	"app.kubernetes.io/instance": persesServiceName,
+}
+
+// newPerses returns the Perses instance of the plugin. On OpenShift, Perses
+// serves TLS with a certificate of the service CA, on Kubernetes TLS is
+// expected to be terminated by the Ingress.
+func newPerses(namespace string, persesImage string, config uiv1alpha1.PersesReference, openshift bool) *persesv1alpha2.Perses {
 	name := "perses"
-	return &persesv1alpha2.Perses{
+	perses := &persesv1alpha2.Perses{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: persesv1alpha2.GroupVersion.String(),
 			Kind:       "Perses",
@@ -316,6 +346,189 @@ func newPerses(namespace string, persesImage string) *persesv1alpha2.Perses {
 			ServiceAccountName: ptr.To("perses" + serviceAccountSuffix),
 		},
 	}
+
+	if !openshift {
+		perses.Spec.TLS = nil
+		perses.Spec.Client.TLS = nil
+		perses.Spec.Service = nil
+	}
+
+	// The Perses operator doesn't support the priority class and the
+	// environment variables of the pods.
+	if deployment := config.Deployment; deployment != nil {
+		perses.Spec.Replicas = deployment.Replicas
+		perses.Spec.Resources = deployment.Resources
+		perses.Spec.NodeSelector = deployment.NodeSelector
+		perses.Spec.Tolerations = deployment.Tolerations
+		perses.Spec.Affinity = deployment.Affinity
+	}
+
+	if config.DatasourceProxy != nil {
+		perses.Spec.Config.Config.Datasource.DisableLocal = config.DatasourceProxy.DisableLocalDatasources
+	}
+
+	if storage := config.Storage; storage != nil {
+		switch {
+		case storage.PersistentVolumeClaim != nil:
+			// The Perses operator mounts the claimed volume on the folder of
+			// the file database.
+			perses.Spec.Storage = &persesv1alpha2.StorageConfiguration{
+				PersistentVolumeClaimTemplate: storage.PersistentVolumeClaim,
+			}
+		case storage.SQL != nil:
+			setPersesSQLDatabase(perses, storage.SQL)
+		}
+	}
+
+	return perses
+}
+
+const (
+	persesSQLCredentialsPath = "/etc/perses/sql"
+	persesSQLCAPath          = "/etc/perses/sql-ca"
+	persesSQLAddressPath     = "/etc/perses/sql-address"
+	persesSQLConfigMapName   = "perses-sql"
+)
+
+// newPersesSQLConfigMap returns the ConfigMap holding the address of the SQL
+// database of Perses. The address is read from a file since the addr field
+// of the Perses configuration is hidden when the Perses resource is
+// marshalled.
+func newPersesSQLConfigMap(namespace string, storage *uiv1alpha1.PersesStorage) *corev1.ConfigMap {
+	cm := &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: corev1.SchemeGroupVersion.String(),
+			Kind:       "ConfigMap",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      persesSQLConfigMapName,
+			Namespace: namespace,
+			Labels:    componentLabels(persesServiceName),
+		},
+	}
+	if storage != nil && storage.SQL != nil {
+		cm.Data = map[string]string{"addr": storage.SQL.Address}
+	}
+	return cm
+}
+
+// setPersesSQLDatabase replaces the file database of Perses with an SQL
+// database. The address, the credentials and the CA certificate are mounted
+// from their ConfigMap and secrets so that they don't appear in the Perses
+// resource.
+func setPersesSQLDatabase(perses *persesv1alpha2.Perses, sql *uiv1alpha1.PersesSQLStorage) {
+	// The address file is named after the hash of the address so that the
+	// Perses pods are restarted when it changes.
+	addressFile := "addr-" + computeConfigMapHash(newPersesSQLConfigMap(perses.Namespace, &uiv1alpha1.PersesStorage{SQL: sql}))
+	database := &persesconfig.SQL{
+		Net:                  "tcp",
+		AddrFile:             path.Join(persesSQLAddressPath, addressFile),
+		DBName:               sql.Database,
+		UserFile:             path.Join(persesSQLCredentialsPath, "user"),
+		PasswordFile:         path.Join(persesSQLCredentialsPath, "password"),
+		AllowNativePasswords: true,
+		CaseSensitive:        true,
+	}
+
+	perses.Spec.Volumes = append(perses.Spec.Volumes, corev1.Volume{
+		Name: "sql-credentials",
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: sql.CredentialsSecretName,
+			},
+		},
+	})
+	perses.Spec.VolumeMounts = append(perses.Spec.VolumeMounts, corev1.VolumeMount{
+		Name:      "sql-credentials",
+		MountPath: persesSQLCredentialsPath,
+		ReadOnly:  true,
+	})
+
+	perses.Spec.Volumes = append(perses.Spec.Volumes, corev1.Volume{
+		Name: "sql-address",
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: persesSQLConfigMapName,
+				},
+				Items: []corev1.KeyToPath{{Key: "addr", Path: addressFile}},
+			},
+		},
+	})
+	perses.Spec.VolumeMounts = append(perses.Spec.VolumeMounts, corev1.VolumeMount{
+		Name:      "sql-address",
+		MountPath: persesSQLAddressPath,
+		ReadOnly:  true,
+	})
+
+	if sql.CASecretName != "" {
+		database.TLSConfig = &secret.PublicTLSConfig{
+			CAFile: path.Join(persesSQLCAPath, "ca.crt"),
+		}
+		perses.Spec.Volumes = append(perses.Spec.Volumes, corev1.Volume{
+			Name: "sql-ca",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: sql.CASecretName,
+				},
+			},
+		})
+		perses.Spec.VolumeMounts = append(perses.Spec.VolumeMounts, corev1.VolumeMount{
+			Name:      "sql-ca",
+			MountPath: persesSQLCAPath,
+			ReadOnly:  true,
+		})
+	}
+
+	perses.Spec.Config.Config.Database = persesconfig.Database{SQL: database}
+}
+
+// headerNameRegexp matches the valid names of HTTP header fields.
+var headerNameRegexp = regexp.MustCompile("^[!#$%&'*+.^_`|~0-9A-Za-z-]+$")
+
+// validatePersesInstance checks the configuration of the Perses instance
+// which can't be validated by the API server. The Perses resource isn't
+// updated when the configuration is invalid.
+func validatePersesInstance(config *uiv1alpha1.PersesReference) error {
+	replicas := int32(1)
+	if config.Deployment != nil && config.Deployment.Replicas != nil {
+		replicas = *config.Deployment.Replicas
+	}
+	usesSQL := config.Storage != nil && config.Storage.SQL != nil
+	if replicas > 1 && !usesSQL {
+		return fmt.Errorf("perses.storage.sql is required to run %d replicas of Perses", replicas)
+	}
+
+	if config.Storage != nil {
+		if pvc := config.Storage.PersistentVolumeClaim; pvc != nil {
+			if size, ok := pvc.Resources.Requests[corev1.ResourceStorage]; !ok || size.IsZero() {
+				return errors.New("perses.storage.persistentVolumeClaim must request a storage size")
+			}
+		}
+		if usesSQL {
+			if _, _, err := net.SplitHostPort(config.Storage.SQL.Address); err != nil {
+				return fmt.Errorf("invalid perses.storage.sql.address: %w", err)
+			}
+		}
+	}
+
+	if proxy := config.DatasourceProxy; proxy != nil {
+		for name := range proxy.Headers {
+			if !headerNameRegexp.MatchString(name) {
+				return fmt.Errorf("invalid header name %q in perses.datasourceProxy.headers", name)
+			}
+			if strings.EqualFold(name, "Authorization") {
+				return errors.New("the Authorization header isn't allowed in perses.datasourceProxy.headers")
+			}
+		}
+		for _, endpoint := range proxy.AllowedEndpoints {
+			if _, err := regexp.Compile(endpoint.EndpointPattern); err != nil {
+				return fmt.Errorf("invalid endpoint pattern %q in perses.datasourceProxy.allowedEndpoints: %w", endpoint.EndpointPattern, err)
+			}
+		}
+	}
+
+	return nil
 }
 
 func newPersesClusterRole() *rbacv1.ClusterRole {
@@ -1,12 +1,18 @@
 package uiplugin
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"path"
 	"strings"
 	"testing"
 
 	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 
 	uiv1alpha1 "github.com/rhobs/observability-operator/pkg/apis/uiplugin/v1alpha1"
 )
@@ -295,7 +301,13 @@ func getPluginInfo(plugin *uiv1alpha1.UIPlugin, features []string, clusterVersio
 		image     = "quay.io/monitoring-foo-test:123"
 	)
 
-	return createMonitoringPluginInfo(plugin, namespace, name, image, features, clusterVersion, healthAnalyzerImage, persesImage)
+	var acm *uiv1alpha1.ACMStatus
+	if plugin.Spec.Monitoring != nil {
+		// The URLs of the test plugins are set, no discovery is done.
+		acm, _ = resolveACMEndpoints(context.Background(), nil, nil, plugin.Spec.Monitoring.ACM)
+	}
+
+	return createMonitoringPluginInfo(plugin, namespace, name, image, features, clusterVersion, healthAnalyzerImage, persesImage, acm)
 }
 
 func TestCreateMonitoringPluginInfo(t *testing.T) {
@@ -562,3 +574,139 @@ func TestCreateMonitoringPluginInfo(t *testing.T) {
 		assert.Assert(t, validateIncidentsConfig(pluginConfigIncidents.Spec.Monitoring, "4.18.0") == false)
 	})
 }
+
+func TestValidatePersesInstance(t *testing.T) {
+	sql := &uiv1alpha1.PersesStorage{
+		SQL: &uiv1alpha1.PersesSQLStorage{
+			Address:               "mysql.perses.svc:3306",
+			Database:              "perses",
+			CredentialsSecretName: "perses-sql",
+		},
+	}
+
+	for _, tc := range []struct {
+		name   string
+		config uiv1alpha1.PersesReference
+		err    string
+	}{
+		{
+			name: "default",
+		},
+		{
+			name: "replicas with sql",
+			config: uiv1alpha1.PersesReference{
+				Deployment: &uiv1alpha1.DeploymentConfig{Replicas: ptr.To(int32(2))},
+				Storage:    sql,
+			},
+		},
+		{
+			name: "replicas without sql",
+			config: uiv1alpha1.PersesReference{
+				Deployment: &uiv1alpha1.DeploymentConfig{Replicas: ptr.To(int32(2))},
+				Storage: &uiv1alpha1.PersesStorage{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimSpec{
+						Resources: corev1.VolumeResourceRequirements{
+							Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("1Gi")},
+						},
+					},
+				},
+			},
+			err: "perses.storage.sql is required to run 2 replicas of Perses",
+		},
+		{
+			name: "claim without size",
+			config: uiv1alpha1.PersesReference{
+				Storage: &uiv1alpha1.PersesStorage{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimSpec{},
+				},
+			},
+			err: "perses.storage.persistentVolumeClaim must request a storage size",
+		},
+		{
+			name: "sql address without port",
+			config: uiv1alpha1.PersesReference{
+				Storage: &uiv1alpha1.PersesStorage{
+					SQL: &uiv1alpha1.PersesSQLStorage{Address: "mysql.perses.svc", Database: "perses", CredentialsSecretName: "perses-sql"},
+				},
+			},
+			err: "invalid perses.storage.sql.address",
+		},
+		{
+			name: "authorization header",
+			config: uiv1alpha1.PersesReference{
+				DatasourceProxy: &uiv1alpha1.PersesDatasourceProxy{
+					Headers: map[string]string{"authorization": "Bearer token"},
+				},
+			},
+			err: "the Authorization header isn't allowed",
+		},
+		{
+			name: "invalid header name",
+			config: uiv1alpha1.PersesReference{
+				DatasourceProxy: &uiv1alpha1.PersesDatasourceProxy{
+					Headers: map[string]string{"X Tenant": "team-a"},
+				},
+			},
+			err: `invalid header name "X Tenant"`,
+		},
+		{
+			name: "invalid endpoint pattern",
+			config: uiv1alpha1.PersesReference{
+				DatasourceProxy: &uiv1alpha1.PersesDatasourceProxy{
+					AllowedEndpoints: []uiv1alpha1.PersesAllowedEndpoint{{EndpointPattern: "/api/v1/(query", Method: "GET"}},
+				},
+			},
+			err: `invalid endpoint pattern "/api/v1/(query"`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validatePersesInstance(&tc.config)
+			if tc.err == "" {
+				assert.NilError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tc.err)
+		})
+	}
+}
+
+func TestNewPersesSQLStorage(t *testing.T) {
+	perses := newPerses("observability", "perses:latest", uiv1alpha1.PersesReference{
+		Storage: &uiv1alpha1.PersesStorage{
+			SQL: &uiv1alpha1.PersesSQLStorage{
+				Address:               "mysql.perses.svc:3306",
+				Database:              "perses",
+				CredentialsSecretName: "perses-sql",
+				CASecretName:          "perses-sql-ca",
+			},
+		},
+		DatasourceProxy: &uiv1alpha1.PersesDatasourceProxy{DisableLocalDatasources: true},
+	}, true)
+
+	database := perses.Spec.Config.Config.Database
+	assert.Assert(t, database.File == nil)
+	assert.Equal(t, string(database.SQL.Addr), "")
+	assert.Equal(t, database.SQL.DBName, "perses")
+	assert.Equal(t, database.SQL.UserFile, "/etc/perses/sql/user")
+	assert.Equal(t, database.SQL.PasswordFile, "/etc/perses/sql/password")
+	assert.Equal(t, database.SQL.TLSConfig.CAFile, "/etc/perses/sql-ca/ca.crt")
+	assert.Assert(t, perses.Spec.Config.Config.Datasource.DisableLocal)
+
+	assert.Equal(t, len(perses.Spec.Volumes), 3)
+	assert.Equal(t, perses.Spec.Volumes[0].Secret.SecretName, "perses-sql")
+	assert.Equal(t, perses.Spec.Volumes[1].ConfigMap.Name, "perses-sql")
+	assert.Equal(t, perses.Spec.Volumes[2].Secret.SecretName, "perses-sql-ca")
+	assert.Equal(t, len(perses.Spec.VolumeMounts), 3)
+
+	// The hidden fields of the configuration are marshalled as <secret>, the
+	// address is read from the file mounted from the ConfigMap.
+	data, err := json.Marshal(perses)
+	assert.NilError(t, err)
+	assert.Assert(t, !strings.Contains(string(data), "<secret>"), string(data))
+
+	cm := newPersesSQLConfigMap("observability", &uiv1alpha1.PersesStorage{SQL: &uiv1alpha1.PersesSQLStorage{Address: "mysql.perses.svc:3306"}})
+	items := perses.Spec.Volumes[1].ConfigMap.Items
+	assert.Equal(t, len(items), 1)
+	assert.Equal(t, cm.Data[items[0].Key], "mysql.perses.svc:3306")
+	assert.Equal(t, database.SQL.AddrFile, path.Join(perses.Spec.VolumeMounts[1].MountPath, items[0].Path))
+}
@@ -0,0 +1,313 @@
+package uiplugin
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	persesv1alpha2 "github.com/rhobs/perses-operator/api/v1alpha2"
+	persesv1 "github.com/rhobs/perses/pkg/model/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	uiv1alpha1 "github.com/rhobs/observability-operator/pkg/apis/uiplugin/v1alpha1"
+)
+
+const (
+	persesDashboardKind  = "PersesDashboard"
+	persesDatasourceKind = "PersesDatasource"
+
+	// persesDefinitionsLabel is the label of the ConfigMaps holding Perses
+	// definitions. Only these ConfigMaps are cached and watched since they
+	// may be created in any namespace.
+	persesDefinitionsLabel = "observability.openshift.io/perses-definitions"
+)
+
+// persesDefinitionsSelector selects the ConfigMaps holding Perses definitions.
+var persesDefinitionsSelector = labels.SelectorFromSet(labels.Set{persesDefinitionsLabel: "true"})
+
+// persesDefinitions are the Perses resources defined in the ConfigMaps
+// selected by the Monitoring plugin.
+type persesDefinitions struct {
+	dashboards  []*persesv1alpha2.PersesDashboard
+	datasources []*persesv1alpha2.PersesDatasource
+	status      []uiv1alpha1.PersesDefinitionStatus
+}
+
+// hasPersesDefinitions returns true if the plugin loads Perses definitions
+// from ConfigMaps.
+func hasPersesDefinitions(plugin *uiv1alpha1.UIPlugin) bool {
+	config := plugin.Spec.Monitoring
+	if config == nil || config.Perses == nil || !config.Perses.Enabled {
+		return false
+	}
+	return config.Perses.Dashboards != nil || config.Perses.Datasources != nil || config.Perses.GrafanaDashboards != nil
+}
+
+// selectsPersesDefinitions returns true if the ConfigMap is referenced or
+// selected by the plugin for its Perses definitions.
+func selectsPersesDefinitions(plugin *uiv1alpha1.UIPlugin, cm client.Object) bool {
+	if !hasPersesDefinitions(plugin) {
+		return false
+	}
+
+	config := plugin.Spec.Monitoring.Perses
+	for _, ref := range []*uiv1alpha1.PersesDefinitionsReference{config.Dashboards, config.GrafanaDashboards, config.Datasources} {
+		if ref == nil {
+			continue
+		}
+		for _, cmRef := range ref.ConfigMaps {
+			if cmRef.Name == cm.GetName() && cmRef.Namespace == cm.GetNamespace() {
+				return true
+			}
+		}
+		if ref.Selector == nil {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(ref.Selector)
+		if err == nil && selector.Matches(labels.Set(cm.GetLabels())) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// loadPersesDefinitions returns the Perses resources defined in the
+// ConfigMaps selected by the plugin, including the dashboards converted from
+// Grafana. The ConfigMaps are read with k which must hold the ConfigMaps
+// with the Perses definitions label in all namespaces.
+//
+// Invalid definitions and missing ConfigMaps are reported in the status
+// rather than failing the reconciliation of the plugin.
+func loadPersesDefinitions(ctx context.Context, k client.Reader, plugin *uiv1alpha1.UIPlugin) (*persesDefinitions, error) {
+	definitions := &persesDefinitions{}
+	if !hasPersesDefinitions(plugin) {
+		return definitions, nil
+	}
+	config := plugin.Spec.Monitoring.Perses
+
+	// The resources are identified by their kind, namespace and name.
+	seen := map[string]uiv1alpha1.PersesDefinitionStatus{}
+	load := func(kind string, ref *uiv1alpha1.PersesDefinitionsReference, newResource func(*corev1.ConfigMap, string, *uiv1alpha1.PersesDefinitionStatus) (client.Object, error)) error {
+		configMaps, err := getPersesConfigMaps(ctx, k, ref)
+		if err != nil {
+			return err
+		}
+
+		for _, cm := range configMaps {
+			if cm.missing {
+				definitions.status = append(definitions.status, uiv1alpha1.PersesDefinitionStatus{
+					Kind:      kind,
+					Namespace: cm.Namespace,
+					ConfigMap: cm.Name,
+					Error:     fmt.Sprintf("ConfigMap not found or without the %s=true label", persesDefinitionsLabel),
+				})
+				continue
+			}
+
+			keys := make([]string, 0, len(cm.Data))
+			for key := range cm.Data {
+				keys = append(keys, key)
+			}
+			slices.Sort(keys)
+
+			for _, key := range keys {
+				status := uiv1alpha1.PersesDefinitionStatus{
+					Kind:      kind,
+					Namespace: cm.Namespace,
+					ConfigMap: cm.Name,
+					Key:       key,
+				}
+
+				obj, err := newResource(&cm.ConfigMap, key, &status)
+				if err == nil {
+					status.Name = obj.GetName()
+					id := strings.Join([]string{kind, cm.Namespace, obj.GetName()}, "/")
+					if other, found := seen[id]; found {
+						err = fmt.Errorf("%s %s is already defined by ConfigMap %s key %s", kind, obj.GetName(), other.ConfigMap, other.Key)
+					} else {
+						seen[id] = status
+					}
+				}
+
+				if err != nil {
+					status.Error = err.Error()
+				} else {
+					switch o := obj.(type) {
+					case *persesv1alpha2.PersesDashboard:
+						definitions.dashboards = append(definitions.dashboards, o)
+					case *persesv1alpha2.PersesDatasource:
+						definitions.datasources = append(definitions.datasources, o)
+					}
+				}
+				definitions.status = append(definitions.status, status)
+			}
+		}
+
+		return nil
+	}
+
+	if err := load(persesDashboardKind, config.Dashboards, func(cm *corev1.ConfigMap, key string, _ *uiv1alpha1.PersesDefinitionStatus) (client.Object, error) {
+		return newPersesDashboardFromDefinition(cm, key)
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := load(persesDashboardKind, config.GrafanaDashboards, func(cm *corev1.ConfigMap, key string, status *uiv1alpha1.PersesDefinitionStatus) (client.Object, error) {
+		return newPersesDashboardFromGrafana(cm, key, status)
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := load(persesDatasourceKind, config.Datasources, func(cm *corev1.ConfigMap, key string, _ *uiv1alpha1.PersesDefinitionStatus) (client.Object, error) {
+		return newPersesDatasourceFromDefinition(cm, key)
+	}); err != nil {
+		return nil, err
+	}
+
+	return definitions, nil
+}
+
+// persesConfigMap is a ConfigMap selected for its Perses definitions.
+type persesConfigMap struct {
+	corev1.ConfigMap
+	// missing is true when the referenced ConfigMap doesn't exist.
+	missing bool
+}
+
+// getPersesConfigMaps returns the ConfigMaps referenced or selected by ref,
+// sorted by namespace and name.
+func getPersesConfigMaps(ctx context.Context, k client.Reader, ref *uiv1alpha1.PersesDefinitionsReference) ([]persesConfigMap, error) {
+	if ref == nil {
+		return nil, nil
+	}
+
+	configMaps := map[client.ObjectKey]persesConfigMap{}
+
+	for _, cmRef := range ref.ConfigMaps {
+		key := client.ObjectKey{Name: cmRef.Name, Namespace: cmRef.Namespace}
+		cm := persesConfigMap{}
+		if err := k.Get(ctx, key, &cm.ConfigMap); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return nil, fmt.Errorf("failed to get ConfigMap %s in namespace %s: %w", cmRef.Name, cmRef.Namespace, err)
+			}
+			cm.Name, cm.Namespace, cm.missing = cmRef.Name, cmRef.Namespace, true
+		}
+		configMaps[key] = cm
+	}
+
+	if ref.Selector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(ref.Selector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ConfigMap selector: %w", err)
+		}
+
+		list := &corev1.ConfigMapList{}
+		if err := k.List(ctx, list, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+			return nil, fmt.Errorf("failed to list ConfigMaps: %w", err)
+		}
+		for _, cm := range list.Items {
+			configMaps[client.ObjectKeyFromObject(&cm)] = persesConfigMap{ConfigMap: cm}
+		}
+	}
+
+	sorted := make([]persesConfigMap, 0, len(configMaps))
+	for _, cm := range configMaps {
+		sorted = append(sorted, cm)
+	}
+	slices.SortFunc(sorted, func(a, b persesConfigMap) int {
+		return cmp.Or(cmp.Compare(a.Namespace, b.Namespace), cmp.Compare(a.Name, b.Name))
+	})
+
+	return sorted, nil
+}
+
+// unmarshalPersesDefinition decodes the JSON or YAML definition held by the
+// key of the ConfigMap. The definition is validated by Perses.
+func unmarshalPersesDefinition(cm *corev1.ConfigMap, key string, v interface{ UnmarshalJSON([]byte) error }) error {
+	data, err := yaml.YAMLToJSON([]byte(cm.Data[key]))
+	if err != nil {
+		return fmt.Errorf("invalid definition: %w", err)
+	}
+
+	if err := v.UnmarshalJSON(data); err != nil {
+		return fmt.Errorf("invalid definition: %w", err)
+	}
+
+	return nil
+}
+
+func validatePersesDefinitionName(name string) error {
+	if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+		return fmt.Errorf("invalid name %q: %s", name, strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+func newPersesDashboardFromDefinition(cm *corev1.ConfigMap, key string) (*persesv1alpha2.PersesDashboard, error) {
+	dashboard := persesv1.Dashboard{}
+	if err := unmarshalPersesDefinition(cm, key, &dashboard); err != nil {
+		return nil, err
+	}
+
+	if err := validatePersesDefinitionName(dashboard.Metadata.Name); err != nil {
+		return nil, err
+	}
+
+	return &persesv1alpha2.PersesDashboard{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: persesv1alpha2.GroupVersion.String(),
+			Kind:       persesDashboardKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      dashboard.Metadata.Name,
+			Namespace: cm.Namespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/managed-by": "observability-operator",
+			},
+		},
+		Spec: persesv1alpha2.PersesDashboardSpec{
+			Config: persesv1alpha2.Dashboard{
+				Spec: dashboard.Spec,
+			},
+		},
+	}, nil
+}
+
+func newPersesDatasourceFromDefinition(cm *corev1.ConfigMap, key string) (*persesv1alpha2.PersesDatasource, error) {
+	datasource := persesv1.Datasource{}
+	if err := unmarshalPersesDefinition(cm, key, &datasource); err != nil {
+		return nil, err
+	}
+
+	if err := validatePersesDefinitionName(datasource.Metadata.Name); err != nil {
+		return nil, err
+	}
+
+	return &persesv1alpha2.PersesDatasource{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: persesv1alpha2.GroupVersion.String(),
+			Kind:       persesDatasourceKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      datasource.Metadata.Name,
+			Namespace: cm.Namespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/managed-by": "observability-operator",
+			},
+		},
+		Spec: persesv1alpha2.DatasourceSpec{
+			Config: persesv1alpha2.Datasource{
+				Spec: datasource.Spec,
+			},
+		},
+	}, nil
+}
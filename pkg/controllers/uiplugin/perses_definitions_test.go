@@ -0,0 +1,155 @@
+package uiplugin
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	uiv1alpha1 "github.com/rhobs/observability-operator/pkg/apis/uiplugin/v1alpha1"
+)
+
+const testDashboardDefinition = `kind: Dashboard
+metadata:
+  name: team-a
+  project: ignored
+spec:
+  display:
+    name: Team A
+  duration: 1h
+  panels: {}
+  layouts: []
+`
+
+const testDatasourceDefinition = `{
+  "kind": "Datasource",
+  "metadata": {"name": "team-a-prometheus"},
+  "spec": {
+    "default": false,
+    "plugin": {"kind": "PrometheusDatasource", "spec": {"directUrl": "http://prometheus.team-a.svc:9090"}}
+  }
+}`
+
+func newDefinitionsConfigMap(name, namespace string, labels map[string]string, data map[string]string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Data: data,
+	}
+}
+
+func TestLoadPersesDefinitions(t *testing.T) {
+	k := fake.NewClientBuilder().WithObjects(
+		newDefinitionsConfigMap("dashboards", "team-a", map[string]string{"perses.dev/dashboard": "true"}, map[string]string{
+			"team-a.yaml":  testDashboardDefinition,
+			"invalid.json": `{"kind": "Datasource"}`,
+		}),
+		newDefinitionsConfigMap("copy", "team-a", nil, map[string]string{
+			"team-a.yaml": testDashboardDefinition,
+		}),
+		newDefinitionsConfigMap("datasources", "team-a", nil, map[string]string{
+			"prometheus.json": testDatasourceDefinition,
+		}),
+	).Build()
+
+	t.Run("no definitions", func(t *testing.T) {
+		plugin := newMonitoringPlugin(&uiv1alpha1.MonitoringConfig{
+			Perses: &uiv1alpha1.PersesReference{Enabled: true},
+		})
+
+		definitions, err := loadPersesDefinitions(context.Background(), k, plugin)
+		assert.NilError(t, err)
+		assert.Equal(t, len(definitions.dashboards), 0)
+		assert.Equal(t, len(definitions.status), 0)
+	})
+
+	t.Run("selected and referenced ConfigMaps", func(t *testing.T) {
+		plugin := newMonitoringPlugin(&uiv1alpha1.MonitoringConfig{
+			Perses: &uiv1alpha1.PersesReference{
+				Enabled: true,
+				Dashboards: &uiv1alpha1.PersesDefinitionsReference{
+					ConfigMaps: []uiv1alpha1.NamespacedConfigMapReference{
+						{Name: "copy", Namespace: "team-a"},
+						{Name: "missing", Namespace: "team-b"},
+					},
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"perses.dev/dashboard": "true"}},
+				},
+				Datasources: &uiv1alpha1.PersesDefinitionsReference{
+					ConfigMaps: []uiv1alpha1.NamespacedConfigMapReference{{Name: "datasources", Namespace: "team-a"}},
+				},
+			},
+		})
+
+		definitions, err := loadPersesDefinitions(context.Background(), k, plugin)
+		assert.NilError(t, err)
+
+		assert.Equal(t, len(definitions.dashboards), 1)
+		assert.Equal(t, definitions.dashboards[0].Name, "team-a")
+		assert.Equal(t, definitions.dashboards[0].Namespace, "team-a")
+		assert.Equal(t, definitions.dashboards[0].Spec.Config.Spec.Display.Name, "Team A")
+
+		assert.Equal(t, len(definitions.datasources), 1)
+		assert.Equal(t, definitions.datasources[0].Name, "team-a-prometheus")
+		assert.Equal(t, definitions.datasources[0].Spec.Config.Spec.Plugin.Kind, "PrometheusDatasource")
+
+		assert.DeepEqual(t, definitions.status, []uiv1alpha1.PersesDefinitionStatus{
+			{Kind: "PersesDashboard", Namespace: "team-a", ConfigMap: "copy", Key: "team-a.yaml", Name: "team-a"},
+			{Kind: "PersesDashboard", Namespace: "team-a", ConfigMap: "dashboards", Key: "invalid.json", Error: `invalid definition: invalid kind: "Datasource" for a Dashboard type`},
+			{Kind: "PersesDashboard", Namespace: "team-a", ConfigMap: "dashboards", Key: "team-a.yaml", Name: "team-a", Error: "PersesDashboard team-a is already defined by ConfigMap copy key team-a.yaml"},
+			{Kind: "PersesDashboard", Namespace: "team-b", ConfigMap: "missing", Error: "ConfigMap not found or without the observability.openshift.io/perses-definitions=true label"},
+			{Kind: "PersesDatasource", Namespace: "team-a", ConfigMap: "datasources", Key: "prometheus.json", Name: "team-a-prometheus"},
+		})
+	})
+}
+
+func TestSelectsPersesDefinitions(t *testing.T) {
+	plugin := newMonitoringPlugin(&uiv1alpha1.MonitoringConfig{
+		Perses: &uiv1alpha1.PersesReference{
+			Enabled: true,
+			Dashboards: &uiv1alpha1.PersesDefinitionsReference{
+				ConfigMaps: []uiv1alpha1.NamespacedConfigMapReference{{Name: "dashboards", Namespace: "team-a"}},
+			},
+			GrafanaDashboards: &uiv1alpha1.PersesDefinitionsReference{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"grafana_dashboard": "true"}},
+			},
+		},
+	})
+
+	for _, tc := range []struct {
+		name     string
+		cm       *corev1.ConfigMap
+		expected bool
+	}{
+		{
+			name:     "referenced",
+			cm:       newDefinitionsConfigMap("dashboards", "team-a", nil, nil),
+			expected: true,
+		},
+		{
+			name:     "selected",
+			cm:       newDefinitionsConfigMap("grafana", "team-b", map[string]string{"grafana_dashboard": "true"}, nil),
+			expected: true,
+		},
+		{
+			name: "other namespace",
+			cm:   newDefinitionsConfigMap("dashboards", "team-b", nil, nil),
+		},
+		{
+			name: "other labels",
+			cm:   newDefinitionsConfigMap("grafana", "team-b", map[string]string{"grafana_dashboard": "false"}, nil),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, selectsPersesDefinitions(plugin, tc.cm), tc.expected)
+		})
+	}
+
+	plugin.Spec.Monitoring.Perses.Enabled = false
+	assert.Assert(t, !selectsPersesDefinitions(plugin, newDefinitionsConfigMap("dashboards", "team-a", nil, nil)))
+}
@@ -3,6 +3,7 @@ package uiplugin
 import (
 	"context"
 	"fmt"
+	"slices"
 
 	"github.com/go-logr/logr"
 	libgocrypto "github.com/openshift/library-go/pkg/crypto"
@@ -18,8 +19,6 @@ type UIPluginInfo struct {
 	Image                      string
 	Korrel8rImage              string
 	HealthAnalyzerImage        string
-	LokiServiceNames           map[string]string
-	TempoServiceNames          map[string]string
 	Name                       string
 	ConsoleName                string
 	DisplayName                string
@@ -27,6 +26,8 @@ type UIPluginInfo struct {
 	Proxies                    []PluginProxy
 	Role                       *rbacv1.Role
 	RoleBinding                *rbacv1.RoleBinding
+	Roles                      []*rbacv1.Role
+	RoleBindings               []*rbacv1.RoleBinding
 	ClusterRoles               []*rbacv1.ClusterRole
 	ClusterRoleBindings        []*rbacv1.ClusterRoleBinding
 	ConfigMap                  *corev1.ConfigMap
@@ -35,6 +36,48 @@ type UIPluginInfo struct {
 	AreMonitoringFeatsDisabled bool
 	TLSMinVersion              string
 	TLSCiphers                 []string
+	// Backends are the backends resolved for the plugin, they are reported
+	// in the status of the UIPlugin.
+	Backends []uiv1alpha1.UIPluginBackend
+	// Korrel8r is the korrel8r configuration of the TroubleshootingPanel plugin.
+	Korrel8r *korrel8rInfo
+	// LokiStackRouter routes the queries of the Logging plugin to the
+	// LokiStacks of the tenants, it is nil when a single LokiStack serves
+	// all the tenants.
+	LokiStackRouter *lokiStackRouter
+	// LoggingCapabilitiesErr reports the LokiStacks of the Logging plugin
+	// which couldn't be inspected to detect their capabilities.
+	LoggingCapabilitiesErr error
+	// LoggingStatus reports the capabilities detected for the Logging plugin.
+	LoggingStatus *uiv1alpha1.LoggingStatus
+	// PersesDefinitions are the Perses resources defined in the ConfigMaps
+	// selected by the Monitoring plugin.
+	PersesDefinitions *persesDefinitions
+	// OperatorWorkloads are the MonitoringStacks and ThanosQueriers for
+	// which the Monitoring plugin creates Perses dashboards.
+	OperatorWorkloads *operatorWorkloads
+	// HealthAnalyzerComponents is the component tree of the health analyzer
+	// merged with the components of the Monitoring plugin. The default tree
+	// is used when empty.
+	HealthAnalyzerComponents string
+	// HealthAnalyzerTarget is the MonitoringStack or ThanosQuerier analyzed
+	// by the health analyzer. The platform monitoring is analyzed when nil.
+	HealthAnalyzerTarget *healthAnalyzerTarget
+	// HealthAnalyzerTargetErr is set when the target of the health analyzer
+	// can't be resolved. The deployed health analyzer is then kept as is.
+	HealthAnalyzerTargetErr error
+	// ACMStatus reports the hub endpoints used by the ACM alerting of the
+	// Monitoring plugin.
+	ACMStatus *uiv1alpha1.ACMStatus
+	// ACMCABundle holds the CA certificates of the discovered hub endpoints
+	// of ACM, trusted by the alerting proxies of the Monitoring plugin. It's
+	// empty when the ACM alerting isn't enabled or its URLs are configured.
+	ACMCABundle string
+	// Features are the features enabled in the plugin and
+	// UnsupportedFeatures the ones requested by the plugin but not
+	// supported for the version of the cluster.
+	Features            []string
+	UnsupportedFeatures []string
 }
 
 var pluginTypeToConsoleName = map[uiv1alpha1.UIPluginType]string{
@@ -51,8 +94,11 @@ func ConsoleNameForType(pluginType uiv1alpha1.UIPluginType) string {
 	return pluginTypeToConsoleName[pluginType]
 }
 
-func PluginInfoBuilder(ctx context.Context, k client.Client, dk dynamic.Interface, plugin *uiv1alpha1.UIPlugin, pluginConf UIPluginsConfiguration, compatibilityInfo CompatibilityEntry, clusterVersion string, logger logr.Logger) (*UIPluginInfo, error) {
-	image := pluginConf.Images[compatibilityInfo.ImageKey]
+func PluginInfoBuilder(ctx context.Context, k client.Client, apiReader client.Reader, dk dynamic.Interface, plugin *uiv1alpha1.UIPlugin, pluginConf UIPluginsConfiguration, compatibilityInfo CompatibilityEntry, clusterVersion string, logger logr.Logger) (*UIPluginInfo, error) {
+	image := compatibilityInfo.Image
+	if image == "" {
+		image = pluginConf.Images[compatibilityInfo.ImageKey]
+	}
 	if image == "" {
 		return nil, fmt.Errorf("no image provided for plugin type %s with key %s", plugin.Spec.Type, compatibilityInfo.ImageKey)
 	}
@@ -64,44 +110,62 @@ func PluginInfoBuilder(ctx context.Context, k client.Client, dk dynamic.Interfac
 
 	switch plugin.Spec.Type {
 	case uiv1alpha1.TypeTroubleshootingPanel:
-		pluginInfo, err = createTroubleshootingPanelPluginInfo(plugin, namespace, plugin.Name, image, []string{}, clusterVersion, logger)
+		pluginInfo, err = createTroubleshootingPanelPluginInfo(plugin, namespace, plugin.Name, image, compatibilityInfo.Features, clusterVersion, logger)
 		if err != nil {
 			return nil, err
 		}
 
 		pluginInfo.Korrel8rImage = pluginConf.Images["korrel8r"]
-		pluginInfo.LokiServiceNames[OpenshiftLoggingNs], err = getLokiServiceName(ctx, k, OpenshiftLoggingNs)
-		if err != nil {
-			return nil, err
+		var korrel8rCfg *uiv1alpha1.Korrel8rConfig
+		if plugin.Spec.TroubleshootingPanel != nil {
+			korrel8rCfg = plugin.Spec.TroubleshootingPanel.Korrel8r
 		}
-
-		pluginInfo.LokiServiceNames[OpenshiftNetobservNs], err = getLokiServiceName(ctx, k, OpenshiftNetobservNs)
+		pluginInfo.Korrel8r, err = resolveKorrel8r(ctx, k, apiReader, namespace, korrel8rCfg)
 		if err != nil {
 			return nil, err
 		}
+		pluginInfo.Backends = append(pluginInfo.Backends, pluginInfo.Korrel8r.backends...)
 
-		pluginInfo.TempoServiceNames[OpenshiftTracingNs], err = getTempoServiceName(ctx, k, OpenshiftTracingNs)
+	case uiv1alpha1.TypeDistributedTracing:
+		instances, err := getTempoInstances(ctx, dk, plugin.Spec.DistributedTracing)
 		if err != nil {
 			return nil, err
 		}
-
-	case uiv1alpha1.TypeDistributedTracing:
-		pluginInfo, err = createDistributedTracingPluginInfo(plugin, namespace, plugin.Name, image, []string{})
+		pluginInfo, err = createDistributedTracingPluginInfo(plugin, namespace, plugin.Name, image, compatibilityInfo.Features, instances)
 		if err != nil {
 			return nil, err
 		}
+		if !restrictsTempoInstances(plugin.Spec.DistributedTracing) {
+			pluginInfo.Backends = append(pluginInfo.Backends, getTempoBackends(ctx, dk, logger)...)
+		}
 
 	case uiv1alpha1.TypeLogging:
 		pluginInfo, err = createLoggingPluginInfo(plugin, namespace, plugin.Name, image, compatibilityInfo.Features, ctx, dk, logger, pluginConf.Images["korrel8r"])
 		if err != nil {
 			return nil, err
 		}
+		if pluginInfo.LokiStackRouter != nil {
+			pluginInfo.LokiStackRouter.image = pluginConf.Images["lokistack-router"]
+			if pluginInfo.LokiStackRouter.image == "" {
+				return nil, fmt.Errorf("no image provided for the LokiStack router with key lokistack-router")
+			}
+		}
 
 	case uiv1alpha1.TypeMonitoring:
-		pluginInfo, err = createMonitoringPluginInfo(plugin, namespace, plugin.Name, image, compatibilityInfo.Features, clusterVersion, pluginConf.Images["health-analyzer"], pluginConf.Images["perses"])
+		var (
+			acm         *uiv1alpha1.ACMStatus
+			acmCABundle string
+		)
+		if plugin.Spec.Monitoring != nil {
+			acm, acmCABundle = resolveACMEndpoints(ctx, apiReader, dk, plugin.Spec.Monitoring.ACM)
+		}
+		pluginInfo, err = createMonitoringPluginInfo(plugin, namespace, plugin.Name, image, compatibilityInfo.Features, clusterVersion, pluginConf.Images["health-analyzer"], pluginConf.Images["perses"], acm)
 		if err != nil {
 			return nil, err
 		}
+		if slices.Contains(pluginInfo.Features, "acm-alerting") {
+			pluginInfo.ACMCABundle = acmCABundle
+		}
 
 	default:
 		return nil, fmt.Errorf("plugin type not supported: %s", plugin.Spec.Type)
@@ -112,3 +176,13 @@ func PluginInfoBuilder(ctx context.Context, k client.Client, dk dynamic.Interfac
 
 	return pluginInfo, err
 }
+
+// serviceBackend returns a backend served by a service of the cluster.
+func serviceBackend(backendType uiv1alpha1.BackendType, name, namespace string, port int32) uiv1alpha1.UIPluginBackend {
+	return uiv1alpha1.UIPluginBackend{
+		Type:      backendType,
+		Name:      name,
+		Namespace: namespace,
+		URL:       fmt.Sprintf("https://%s.%s.svc:%d", name, namespace, port),
+	}
+}
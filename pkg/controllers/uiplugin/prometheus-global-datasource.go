@@ -1,15 +1,76 @@
 package uiplugin
 
 import (
+	"strings"
+
 	specCommon "github.com/perses/spec/go/common"
 	dsSpec "github.com/perses/spec/go/datasource"
 	pluginSpec "github.com/perses/spec/go/plugin"
 	persesv1alpha2 "github.com/rhobs/perses-operator/api/v1alpha2"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
+
+	uiv1alpha1 "github.com/rhobs/observability-operator/pkg/apis/uiplugin/v1alpha1"
 )
 
-func newPrometheusGlobalDatasource() *persesv1alpha2.PersesDatasource {
+const platformThanosQuerierURL = "https://thanos-querier.openshift-monitoring.svc.cluster.local:9091"
+
+// newPrometheusGlobalDatasource returns the default datasource of Perses.
+// The platform Thanos Querier is verified with the service CA, other HTTPS
+// endpoints with the system certificates. The headers and the allowed
+// endpoints of the proxy are set from proxyConfig when not nil.
+func newPrometheusGlobalDatasource(url string, proxyConfig *uiv1alpha1.PersesDatasourceProxy) *persesv1alpha2.PersesDatasource {
+	if url == "" {
+		url = platformThanosQuerierURL
+	}
+
+	proxy := map[string]interface{}{
+		"url": url,
+	}
+	if proxyConfig != nil {
+		if len(proxyConfig.Headers) > 0 {
+			headers := map[string]interface{}{}
+			for name, value := range proxyConfig.Headers {
+				headers[name] = value
+			}
+			proxy["headers"] = headers
+		}
+		if len(proxyConfig.AllowedEndpoints) > 0 {
+			endpoints := make([]interface{}, 0, len(proxyConfig.AllowedEndpoints))
+			for _, endpoint := range proxyConfig.AllowedEndpoints {
+				endpoints = append(endpoints, map[string]interface{}{
+					"endpointPattern": endpoint.EndpointPattern,
+					"method":          endpoint.Method,
+				})
+			}
+			proxy["allowedEndpoints"] = endpoints
+		}
+	}
+
+	var client *persesv1alpha2.Client
+	switch {
+	case url == platformThanosQuerierURL:
+		proxy["secret"] = "global-thanos-querier-datasource-secret"
+		client = &persesv1alpha2.Client{
+			TLS: &persesv1alpha2.TLS{
+				Enable: ptr.To(true),
+				CaCert: &persesv1alpha2.Certificate{
+					SecretSource: persesv1alpha2.SecretSource{
+						Type: persesv1alpha2.SecretSourceTypeFile,
+					},
+					CertPath: "/ca/service-ca.crt",
+				},
+			},
+		}
+	case strings.HasPrefix(url, "https://"):
+		proxy["secret"] = "global-thanos-querier-datasource-secret"
+		client = &persesv1alpha2.Client{
+			TLS: &persesv1alpha2.TLS{
+				Enable: ptr.To(true),
+			},
+		}
+	}
+
 	return &persesv1alpha2.PersesDatasource{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: persesv1alpha2.GroupVersion.String(),
@@ -33,26 +94,13 @@ func newPrometheusGlobalDatasource() *persesv1alpha2.PersesDatasource {
 						Spec: map[string]interface{}{
 							"proxy": map[string]interface{}{
 								"kind": "HTTPProxy",
-								"spec": map[string]interface{}{
-									"url":    "https://thanos-querier.openshift-monitoring.svc.cluster.local:9091",
-									"secret": "global-thanos-querier-datasource-secret",
-								},
+								"spec": proxy,
 							},
 						},
 					},
 				},
 			},
-			Client: &persesv1alpha2.Client{
-				TLS: &persesv1alpha2.TLS{
-					Enable: ptr.To(true),
-					CaCert: &persesv1alpha2.Certificate{
-						SecretSource: persesv1alpha2.SecretSource{
-							Type: persesv1alpha2.SecretSourceTypeFile,
-						},
-						CertPath: "/ca/service-ca.crt",
-					},
-				},
-			},
+			Client: client,
 		},
 	}
 }
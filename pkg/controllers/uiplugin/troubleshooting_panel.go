@@ -34,15 +34,22 @@ func createTroubleshootingPanelPluginInfo(plugin *uiv1alpha1.UIPlugin, namespace
 	extraArgs := []string{
 		"-plugin-config-path=/etc/plugin/config/config.yaml",
 	}
+	// The agent navigation isn't listed by the compatibility matrix, it is
+	// supported from OpenShift 4.22.
+	supported, derived := slices.Clone(features), slices.Clone(features)
+	if IsVersionAheadOrEqual(clusterVersion, "v4.22") && !slices.Contains(supported, "agent-navigation") {
+		supported = append(supported, "agent-navigation")
+	}
 	if plugin.Spec.TroubleshootingPanel != nil && plugin.Spec.TroubleshootingPanel.EnableAgentNavigation {
-		if IsVersionAheadOrEqual(clusterVersion, "v4.22") {
-			if !slices.Contains(features, "agent-navigation") {
-				features = append(features, "agent-navigation")
+		if slices.Contains(supported, "agent-navigation") {
+			if !slices.Contains(derived, "agent-navigation") {
+				derived = append(derived, "agent-navigation")
 			}
 		} else {
 			logger.Info("Agent Navigation only available as a Dev Preview in OpenShift 4.22+")
 		}
 	}
+	features, unsupported := pluginFeatures(plugin, supported, derived)
 	if len(features) > 0 {
 		extraArgs = append(extraArgs, fmt.Sprintf("-features=%s", strings.Join(features, ",")))
 	}
@@ -54,8 +61,6 @@ func createTroubleshootingPanelPluginInfo(plugin *uiv1alpha1.UIPlugin, namespace
 		ConsoleName:       pluginTypeToConsoleName[plugin.Spec.Type],
 		DisplayName:       "Troubleshooting Panel Console Plugin",
 		ResourceNamespace: namespace,
-		LokiServiceNames:  make(map[string]string),
-		TempoServiceNames: make(map[string]string),
 		ExtraArgs:         extraArgs,
 		Proxies: []PluginProxy{
 			{
@@ -66,6 +71,9 @@ func createTroubleshootingPanelPluginInfo(plugin *uiv1alpha1.UIPlugin, namespace
 				Authorize:        true,
 			},
 		},
+		Backends: []uiv1alpha1.UIPluginBackend{
+			serviceBackend(uiv1alpha1.BackendTypeKorrel8r, korrel8rSvcName, namespace, port),
+		},
 		ConfigMap: &corev1.ConfigMap{
 			TypeMeta: metav1.TypeMeta{
 				APIVersion: corev1.SchemeGroupVersion.String(),
@@ -110,6 +118,8 @@ func createTroubleshootingPanelPluginInfo(plugin *uiv1alpha1.UIPlugin, namespace
 			newClusterRoleBinding(namespace, serviceAccountName, korrel8rSvcName+"-view", plugin.Name+"-"+korrel8rSvcName),
 		},
 	}
+	pluginInfo.Features = features
+	pluginInfo.UnsupportedFeatures = unsupported
 
 	return pluginInfo, nil
 }
@@ -89,6 +89,20 @@ func TestCreateTroubleshootingPanelPluginInfo(t *testing.T) {
 		assert.Equal(t, findFeaturesArg(info.ExtraArgs), "agent-navigation")
 	})
 
+	t.Run("agent-navigation feature is enabled by the overrides", func(t *testing.T) {
+		plugin := newTroubleshootingPanelPlugin(nil)
+		plugin.Spec.Features = &uiv1alpha1.UIPluginFeatures{Enabled: []string{"agent-navigation"}}
+		info, err := getTroubleshootingPanelPluginInfo(plugin, nil, "v4.22.0", logger)
+		assert.NilError(t, err)
+		assert.Equal(t, findFeaturesArg(info.ExtraArgs), "agent-navigation")
+		assert.Equal(t, len(info.UnsupportedFeatures), 0)
+
+		info, err = getTroubleshootingPanelPluginInfo(plugin, nil, "v4.19.0", logger)
+		assert.NilError(t, err)
+		assert.Equal(t, findFeaturesArg(info.ExtraArgs), "")
+		assert.DeepEqual(t, info.UnsupportedFeatures, []string{"agent-navigation"})
+	})
+
 	t.Run("nil TroubleshootingPanel config", func(t *testing.T) {
 		plugin := newTroubleshootingPanelPlugin(nil)
 		info, err := getTroubleshootingPanelPluginInfo(plugin, nil, "v4.19.0", logger)
@@ -116,6 +130,18 @@ func TestCreateTroubleshootingPanelPluginInfo(t *testing.T) {
 		assert.Equal(t, info.Proxies[0].ServiceNamespace, "openshift-operators")
 	})
 
+	t.Run("korrel8r is reported as a backend", func(t *testing.T) {
+		plugin := newTroubleshootingPanelPlugin(nil)
+		info, err := getTroubleshootingPanelPluginInfo(plugin, nil, "v4.19.0", logger)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, info.Backends, []uiv1alpha1.UIPluginBackend{{
+			Type:      uiv1alpha1.BackendTypeKorrel8r,
+			Name:      "korrel8r",
+			Namespace: "openshift-operators",
+			URL:       "https://korrel8r.openshift-operators.svc:9443",
+		}})
+	})
+
 	t.Run("multiple features are comma-joined", func(t *testing.T) {
 		plugin := newTroubleshootingPanelPlugin(nil)
 		info, err := getTroubleshootingPanelPluginInfo(plugin, []string{"agent-navigation", "other-feature"}, "v4.22.0", logger)
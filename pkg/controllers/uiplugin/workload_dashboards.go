@@ -0,0 +1,423 @@
+package uiplugin
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/go-logr/logr"
+	"github.com/perses/perses/go-sdk/common"
+	"github.com/perses/perses/go-sdk/dashboard"
+	"github.com/perses/perses/go-sdk/panel"
+	panelgroup "github.com/perses/perses/go-sdk/panel-group"
+	"github.com/perses/plugins/prometheus/sdk/go/query"
+	timeseries "github.com/perses/plugins/timeserieschart/sdk/go"
+	specCommon "github.com/perses/spec/go/common"
+	dsSpec "github.com/perses/spec/go/datasource"
+	pluginSpec "github.com/perses/spec/go/plugin"
+	persesv1alpha2 "github.com/rhobs/perses-operator/api/v1alpha2"
+	persesv1 "github.com/rhobs/perses/pkg/model/api/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	msoapi "github.com/rhobs/observability-operator/pkg/apis/monitoring/v1alpha1"
+	uiv1alpha1 "github.com/rhobs/observability-operator/pkg/apis/uiplugin/v1alpha1"
+	"github.com/rhobs/observability-operator/pkg/reconciler"
+)
+
+const (
+	// The jobs scraping Prometheus and Alertmanager are configured by the
+	// MonitoringStack controller for self-monitoring.
+	prometheusSelfJob   = `job="prometheus-self"`
+	alertmanagerSelfJob = `job="alertmanager-self"`
+)
+
+// operatorWorkloads are the MonitoringStacks and ThanosQueriers managed by
+// the operator for which the Monitoring plugin creates Perses dashboards.
+type operatorWorkloads struct {
+	monitoringStacks []msoapi.MonitoringStack
+	thanosQueriers   []msoapi.ThanosQuerier
+}
+
+// listOperatorWorkloads returns the MonitoringStacks matching the selector of
+// the Perses configuration and the ThanosQueriers of all namespaces, sorted
+// by namespace and name. Nothing is listed when Perses isn't enabled.
+func listOperatorWorkloads(ctx context.Context, k client.Reader, plugin *uiv1alpha1.UIPlugin) (*operatorWorkloads, error) {
+	workloads := &operatorWorkloads{}
+	config := plugin.Spec.Monitoring
+	if config == nil || config.Perses == nil || !config.Perses.Enabled {
+		return workloads, nil
+	}
+
+	var opts []client.ListOption
+	if config.Perses.MonitoringStackSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(config.Perses.MonitoringStackSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid perses.monitoringStackSelector: %w", err)
+		}
+		opts = append(opts, client.MatchingLabelsSelector{Selector: selector})
+	}
+
+	stacks := &msoapi.MonitoringStackList{}
+	if err := k.List(ctx, stacks, opts...); err != nil {
+		return nil, fmt.Errorf("failed to list MonitoringStacks: %w", err)
+	}
+	workloads.monitoringStacks = stacks.Items
+	slices.SortFunc(workloads.monitoringStacks, func(a, b msoapi.MonitoringStack) int {
+		return cmp.Or(cmp.Compare(a.Namespace, b.Namespace), cmp.Compare(a.Name, b.Name))
+	})
+
+	queriers := &msoapi.ThanosQuerierList{}
+	if err := k.List(ctx, queriers); err != nil {
+		return nil, fmt.Errorf("failed to list ThanosQueriers: %w", err)
+	}
+	workloads.thanosQueriers = queriers.Items
+	slices.SortFunc(workloads.thanosQueriers, func(a, b msoapi.ThanosQuerier) int {
+		return cmp.Or(cmp.Compare(a.Namespace, b.Namespace), cmp.Compare(a.Name, b.Name))
+	})
+
+	return workloads, nil
+}
+
+// operatorWorkloadsReconcilers returns the reconcilers of the datasources and
+// dashboards of the operator's workloads. They are created in the namespace
+// of each workload and pruned by the inventory once the workload is deleted.
+func operatorWorkloadsReconcilers(plugin *uiv1alpha1.UIPlugin, workloads *operatorWorkloads, definitions *persesDefinitions, logger logr.Logger) []reconciler.Reconciler {
+	var components []reconciler.Reconciler
+	defaultNamespaces := thanosQuerierDefaultNamespaces(workloads, definitions)
+
+	for i := range workloads.monitoringStacks {
+		ms := &workloads.monitoringStacks[i]
+		components = append(components, reconciler.NewUpdater(newMonitoringStackDatasource(ms), plugin))
+
+		d, err := newMonitoringStackDashboard(ms)
+		if err != nil {
+			logger.Error(err, "Cannot build MonitoringStack dashboard", "monitoringstack", client.ObjectKeyFromObject(ms))
+			continue
+		}
+		components = append(components, reconciler.NewUpdater(d, plugin))
+	}
+
+	for i := range workloads.thanosQueriers {
+		tq := &workloads.thanosQueriers[i]
+		components = append(components, reconciler.NewUpdater(newThanosQuerierDatasource(tq, defaultNamespaces[tq.Namespace]), plugin))
+
+		d, err := newThanosQuerierDashboard(tq)
+		if err != nil {
+			logger.Error(err, "Cannot build ThanosQuerier dashboard", "thanosquerier", client.ObjectKeyFromObject(tq))
+			continue
+		}
+		components = append(components, reconciler.NewUpdater(d, plugin))
+	}
+
+	return components
+}
+
+// thanosQuerierDefaultNamespaces returns the namespaces in which the
+// datasource of a ThanosQuerier is the default datasource. It's the case
+// unless the namespace has other ThanosQueriers or a default datasource among
+// the definitions loaded from ConfigMaps.
+func thanosQuerierDefaultNamespaces(workloads *operatorWorkloads, definitions *persesDefinitions) map[string]bool {
+	candidates := map[string]int{}
+	for _, tq := range workloads.thanosQueriers {
+		candidates[tq.Namespace]++
+	}
+	if definitions != nil {
+		for _, ds := range definitions.datasources {
+			if ds.Spec.Config.Spec.Default {
+				candidates[ds.Namespace]++
+			}
+		}
+	}
+
+	namespaces := map[string]bool{}
+	for _, tq := range workloads.thanosQueriers {
+		if candidates[tq.Namespace] == 1 {
+			namespaces[tq.Namespace] = true
+		}
+	}
+	return namespaces
+}
+
+func monitoringStackDatasourceName(ms *msoapi.MonitoringStack) string {
+	return ms.Name + "-prometheus-datasource"
+}
+
+func thanosQuerierDatasourceName(tq *msoapi.ThanosQuerier) string {
+	return "thanos-querier-" + tq.Name + "-datasource"
+}
+
+func newMonitoringStackDatasource(ms *msoapi.MonitoringStack) *persesv1alpha2.PersesDatasource {
+	var tlsConfig *msoapi.WebTLSConfig
+	if ms.Spec.PrometheusConfig != nil {
+		tlsConfig = ms.Spec.PrometheusConfig.WebTLSConfig
+	}
+
+	return newWorkloadDatasource(
+		monitoringStackDatasourceName(ms),
+		ms.Namespace,
+		fmt.Sprintf("MonitoringStack %s", ms.Name),
+		fmt.Sprintf("%s-prometheus.%s.svc:9090", ms.Name, ms.Namespace),
+		tlsConfig,
+		false,
+	)
+}
+
+func newThanosQuerierDatasource(tq *msoapi.ThanosQuerier, isDefault bool) *persesv1alpha2.PersesDatasource {
+	return newWorkloadDatasource(
+		thanosQuerierDatasourceName(tq),
+		tq.Namespace,
+		fmt.Sprintf("ThanosQuerier %s", tq.Name),
+		fmt.Sprintf("thanos-querier-%s.%s.svc:10902", tq.Name, tq.Namespace),
+		tq.Spec.WebTLSConfig,
+		isDefault,
+	)
+}
+
+// newWorkloadDatasource returns the datasource of a Prometheus API served by
+// a workload of the operator at address. When TLS is enabled, the server is
+// verified with the CA of the TLS configuration of the workload.
+func newWorkloadDatasource(name, namespace, displayName, address string, tlsConfig *msoapi.WebTLSConfig, isDefault bool) *persesv1alpha2.PersesDatasource {
+	proxy := map[string]interface{}{
+		"url": "http://" + address,
+	}
+	var client *persesv1alpha2.Client
+	if tlsConfig != nil {
+		proxy["url"] = "https://" + address
+		proxy["secret"] = name + "-secret"
+		client = &persesv1alpha2.Client{
+			TLS: &persesv1alpha2.TLS{
+				Enable: ptr.To(true),
+				CaCert: &persesv1alpha2.Certificate{
+					SecretSource: persesv1alpha2.SecretSource{
+						Type:      persesv1alpha2.SecretSourceTypeSecret,
+						Name:      ptr.To(tlsConfig.CertificateAuthority.Name),
+						Namespace: ptr.To(namespace),
+					},
+					CertPath: tlsConfig.CertificateAuthority.Key,
+				},
+			},
+		}
+	}
+
+	return &persesv1alpha2.PersesDatasource{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: persesv1alpha2.GroupVersion.String(),
+			Kind:       persesDatasourceKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/managed-by": "observability-operator",
+			},
+		},
+		Spec: persesv1alpha2.DatasourceSpec{
+			Config: persesv1alpha2.Datasource{
+				Spec: dsSpec.Spec{
+					Display: &specCommon.Display{
+						Name: displayName,
+					},
+					Default: isDefault,
+					Plugin: pluginSpec.Plugin{
+						Kind: "PrometheusDatasource",
+						Spec: map[string]interface{}{
+							"proxy": map[string]interface{}{
+								"kind": "HTTPProxy",
+								"spec": proxy,
+							},
+						},
+					},
+				},
+			},
+			Client: client,
+		},
+	}
+}
+
+// workloadQuery is a query of a panel with the format of its series names.
+type workloadQuery struct {
+	expr   string
+	legend string
+}
+
+// workloadPanel returns a time series panel querying the datasource of a
+// workload.
+func workloadPanel(title, datasource, unit string, queries ...workloadQuery) panelgroup.Option {
+	options := []panel.Option{
+		timeseries.Chart(
+			timeseries.WithYAxis(timeseries.YAxis{
+				Format: &common.Format{
+					Unit: ptr.To(unit),
+				},
+			}),
+			timeseries.WithLegend(timeseries.Legend{
+				Position: timeseries.BottomPosition,
+			}),
+		),
+	}
+
+	for _, q := range queries {
+		options = append(options, panel.AddQuery(
+			query.PromQL(q.expr,
+				query.Datasource(datasource),
+				query.SeriesNameFormat(q.legend),
+			),
+		))
+	}
+
+	return panelgroup.AddPanel(title, options...)
+}
+
+func buildMonitoringStackDashboard(ms *msoapi.MonitoringStack) (dashboard.Builder, error) {
+	datasource := monitoringStackDatasourceName(ms)
+
+	options := []dashboard.Option{
+		dashboard.Name(fmt.Sprintf("MonitoringStack / %s", ms.Name)),
+		dashboard.AddPanelGroup("Prometheus Ingestion",
+			panelgroup.PanelsPerLine(3),
+			workloadPanel("Samples appended", datasource, string(common.RequestsPerSecondsUnit),
+				workloadQuery{fmt.Sprintf(`sum by (pod) (rate(prometheus_tsdb_head_samples_appended_total{%s}[$__rate_interval]))`, prometheusSelfJob), "{{pod}}"},
+			),
+			workloadPanel("Head series", datasource, common.DecimalUnit,
+				workloadQuery{fmt.Sprintf(`sum by (pod) (prometheus_tsdb_head_series{%s})`, prometheusSelfJob), "{{pod}}"},
+			),
+			workloadPanel("Targets", datasource, common.DecimalUnit,
+				workloadQuery{`count by (job) (up == 1)`, "{{job}} up"},
+				workloadQuery{`count by (job) (up == 0)`, "{{job}} down"},
+			),
+		),
+		dashboard.AddPanelGroup("Prometheus TSDB",
+			panelgroup.PanelsPerLine(3),
+			workloadPanel("Storage size", datasource, string(common.BinaryBytesUnit),
+				workloadQuery{fmt.Sprintf(`sum by (pod) (prometheus_tsdb_storage_blocks_bytes{%s})`, prometheusSelfJob), "{{pod}}"},
+			),
+			workloadPanel("Compactions", datasource, common.DecimalUnit,
+				workloadQuery{fmt.Sprintf(`sum by (pod) (increase(prometheus_tsdb_compactions_total{%s}[$__rate_interval]))`, prometheusSelfJob), "{{pod}}"},
+				workloadQuery{fmt.Sprintf(`sum by (pod) (increase(prometheus_tsdb_compactions_failed_total{%s}[$__rate_interval]))`, prometheusSelfJob), "{{pod}} failed"},
+			),
+			workloadPanel("WAL corruptions", datasource, common.DecimalUnit,
+				workloadQuery{fmt.Sprintf(`sum by (pod) (increase(prometheus_tsdb_wal_corruptions_total{%s}[$__rate_interval]))`, prometheusSelfJob), "{{pod}}"},
+			),
+		),
+	}
+
+	if ms.Spec.PrometheusConfig != nil && len(ms.Spec.PrometheusConfig.RemoteWrite) > 0 {
+		options = append(options, dashboard.AddPanelGroup("Prometheus Remote Write",
+			panelgroup.PanelsPerLine(3),
+			workloadPanel("Highest timestamp lag", datasource, string(common.SecondsUnit),
+				workloadQuery{fmt.Sprintf(`max by (pod, remote_name, url) (prometheus_remote_storage_queue_highest_timestamp_seconds{%[1]s} - prometheus_remote_storage_queue_highest_sent_timestamp_seconds{%[1]s})`, prometheusSelfJob), "{{pod}} {{url}}"},
+			),
+			workloadPanel("Pending samples", datasource, common.DecimalUnit,
+				workloadQuery{fmt.Sprintf(`sum by (pod, url) (prometheus_remote_storage_samples_pending{%s})`, prometheusSelfJob), "{{pod}} {{url}}"},
+			),
+			workloadPanel("Failed samples", datasource, string(common.RequestsPerSecondsUnit),
+				workloadQuery{fmt.Sprintf(`sum by (pod, url) (rate(prometheus_remote_storage_samples_failed_total{%s}[$__rate_interval]))`, prometheusSelfJob), "{{pod}} {{url}}"},
+			),
+		))
+	}
+
+	if !ms.Spec.AlertmanagerConfig.Disabled {
+		options = append(options, dashboard.AddPanelGroup("Alertmanager Notifications",
+			panelgroup.PanelsPerLine(3),
+			workloadPanel("Notifications", datasource, string(common.RequestsPerSecondsUnit),
+				workloadQuery{fmt.Sprintf(`sum by (integration) (rate(alertmanager_notifications_total{%s}[$__rate_interval]))`, alertmanagerSelfJob), "{{integration}}"},
+				workloadQuery{fmt.Sprintf(`sum by (integration) (rate(alertmanager_notifications_failed_total{%s}[$__rate_interval]))`, alertmanagerSelfJob), "{{integration}} failed"},
+			),
+			workloadPanel("Notification latency (p99)", datasource, string(common.SecondsUnit),
+				workloadQuery{fmt.Sprintf(`histogram_quantile(0.99, sum by (le, integration) (rate(alertmanager_notification_latency_seconds_bucket{%s}[$__rate_interval])))`, alertmanagerSelfJob), "{{integration}}"},
+			),
+			workloadPanel("Alerts sent by Prometheus", datasource, string(common.RequestsPerSecondsUnit),
+				workloadQuery{fmt.Sprintf(`sum by (pod) (rate(prometheus_notifications_sent_total{%s}[$__rate_interval]))`, prometheusSelfJob), "{{pod}}"},
+				workloadQuery{fmt.Sprintf(`sum by (pod) (rate(prometheus_notifications_errors_total{%s}[$__rate_interval]))`, prometheusSelfJob), "{{pod}} errors"},
+			),
+		))
+	}
+
+	return dashboard.New(ms.Name+"-monitoring-stack", options...)
+}
+
+func buildThanosQuerierDashboard(tq *msoapi.ThanosQuerier) (dashboard.Builder, error) {
+	datasource := thanosQuerierDatasourceName(tq)
+	// The job is named after the service of the Thanos Querier.
+	job := fmt.Sprintf(`job="thanos-querier-%s", namespace="%s"`, tq.Name, tq.Namespace)
+
+	return dashboard.New("thanos-querier-"+tq.Name,
+		dashboard.Name(fmt.Sprintf("ThanosQuerier / %s", tq.Name)),
+		dashboard.AddPanelGroup("Queries",
+			panelgroup.PanelsPerLine(3),
+			workloadPanel("Query rate", datasource, string(common.RequestsPerSecondsUnit),
+				workloadQuery{fmt.Sprintf(`sum by (handler) (rate(http_requests_total{%s, handler=~"query|query_range"}[$__rate_interval]))`, job), "{{handler}}"},
+			),
+			workloadPanel("Query errors", datasource, string(common.RequestsPerSecondsUnit),
+				workloadQuery{fmt.Sprintf(`sum by (handler, code) (rate(http_requests_total{%s, handler=~"query|query_range", code=~"5.."}[$__rate_interval]))`, job), "{{handler}} {{code}}"},
+			),
+			workloadPanel("Query latency (p99)", datasource, string(common.SecondsUnit),
+				workloadQuery{fmt.Sprintf(`histogram_quantile(0.99, sum by (le, handler) (rate(http_request_duration_seconds_bucket{%s, handler=~"query|query_range"}[$__rate_interval])))`, job), "{{handler}}"},
+			),
+		),
+		dashboard.AddPanelGroup("Stores",
+			panelgroup.PanelsPerLine(3),
+			workloadPanel("Connected stores", datasource, common.DecimalUnit,
+				workloadQuery{fmt.Sprintf(`sum by (store_type) (thanos_store_nodes_grpc_connections{%s})`, job), "{{store_type}}"},
+			),
+			workloadPanel("Store API errors", datasource, string(common.RequestsPerSecondsUnit),
+				workloadQuery{fmt.Sprintf(`sum by (grpc_method, grpc_code) (rate(grpc_client_handled_total{%s, grpc_code!="OK"}[$__rate_interval]))`, job), "{{grpc_method}} {{grpc_code}}"},
+			),
+			workloadPanel("Store API latency (p99)", datasource, string(common.SecondsUnit),
+				workloadQuery{fmt.Sprintf(`histogram_quantile(0.99, sum by (le, grpc_method) (rate(grpc_client_handling_seconds_bucket{%s}[$__rate_interval])))`, job), "{{grpc_method}}"},
+			),
+		),
+	)
+}
+
+func newMonitoringStackDashboard(ms *msoapi.MonitoringStack) (*persesv1alpha2.PersesDashboard, error) {
+	builder, err := buildMonitoringStackDashboard(ms)
+	if err != nil {
+		return nil, err
+	}
+	return newWorkloadDashboard(builder, ms.Namespace)
+}
+
+func newThanosQuerierDashboard(tq *msoapi.ThanosQuerier) (*persesv1alpha2.PersesDashboard, error) {
+	builder, err := buildThanosQuerierDashboard(tq)
+	if err != nil {
+		return nil, err
+	}
+	return newWorkloadDashboard(builder, tq.Namespace)
+}
+
+func newWorkloadDashboard(builder dashboard.Builder, namespace string) (*persesv1alpha2.PersesDashboard, error) {
+	// Workaround because of type conflict between Perses plugin types and Perses fork in rhobs org
+	rhobsDashboard := persesv1.Dashboard{}
+	bytes, err := json.Marshal(builder.Dashboard)
+	if err != nil {
+		return nil, err
+	}
+	err = rhobsDashboard.UnmarshalJSON(bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return &persesv1alpha2.PersesDashboard{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: persesv1alpha2.GroupVersion.String(),
+			Kind:       persesDashboardKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      builder.Dashboard.Metadata.Name,
+			Namespace: namespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/managed-by": "observability-operator",
+			},
+		},
+		Spec: persesv1alpha2.PersesDashboardSpec{
+			Config: persesv1alpha2.Dashboard{
+				Spec: rhobsDashboard.Spec,
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,143 @@
+package uiplugin
+
+import (
+	"context"
+	"testing"
+
+	monv1 "github.com/rhobs/obo-prometheus-operator/pkg/apis/monitoring/v1"
+	persesv1alpha2 "github.com/rhobs/perses-operator/api/v1alpha2"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	msoapi "github.com/rhobs/observability-operator/pkg/apis/monitoring/v1alpha1"
+	uiv1alpha1 "github.com/rhobs/observability-operator/pkg/apis/uiplugin/v1alpha1"
+)
+
+func TestListOperatorWorkloads(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NilError(t, msoapi.AddToScheme(scheme))
+
+	k := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&msoapi.MonitoringStack{ObjectMeta: metav1.ObjectMeta{Name: "b", Namespace: "team-a", Labels: map[string]string{"perses": "true"}}},
+		&msoapi.MonitoringStack{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "team-b"}},
+		&msoapi.MonitoringStack{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "team-a"}},
+		&msoapi.ThanosQuerier{ObjectMeta: metav1.ObjectMeta{Name: "global", Namespace: "team-a"}},
+	).Build()
+
+	plugin := newMonitoringPlugin(&uiv1alpha1.MonitoringConfig{
+		Perses: &uiv1alpha1.PersesReference{Enabled: true},
+	})
+
+	workloads, err := listOperatorWorkloads(context.Background(), k, plugin)
+	assert.NilError(t, err)
+
+	var stacks []string
+	for _, ms := range workloads.monitoringStacks {
+		stacks = append(stacks, ms.Namespace+"/"+ms.Name)
+	}
+	assert.DeepEqual(t, stacks, []string{"team-a/a", "team-a/b", "team-b/a"})
+	assert.Equal(t, len(workloads.thanosQueriers), 1)
+
+	plugin.Spec.Monitoring.Perses.MonitoringStackSelector = &metav1.LabelSelector{
+		MatchLabels: map[string]string{"perses": "true"},
+	}
+	workloads, err = listOperatorWorkloads(context.Background(), k, plugin)
+	assert.NilError(t, err)
+	stacks = nil
+	for _, ms := range workloads.monitoringStacks {
+		stacks = append(stacks, ms.Namespace+"/"+ms.Name)
+	}
+	assert.DeepEqual(t, stacks, []string{"team-a/b"})
+	assert.Equal(t, len(workloads.thanosQueriers), 1)
+
+	plugin.Spec.Monitoring.Perses.Enabled = false
+	workloads, err = listOperatorWorkloads(context.Background(), k, plugin)
+	assert.NilError(t, err)
+	assert.Equal(t, len(workloads.monitoringStacks), 0)
+	assert.Equal(t, len(workloads.thanosQueriers), 0)
+}
+
+func TestNewWorkloadDatasources(t *testing.T) {
+	ms := &msoapi.MonitoringStack{
+		ObjectMeta: metav1.ObjectMeta{Name: "team-a", Namespace: "monitoring"},
+	}
+
+	datasource := newMonitoringStackDatasource(ms)
+	assert.Equal(t, datasource.Name, "team-a-prometheus-datasource")
+	assert.Equal(t, datasource.Namespace, "monitoring")
+	assert.Assert(t, !datasource.Spec.Config.Spec.Default)
+	assert.Assert(t, datasource.Spec.Client == nil)
+	proxy := datasource.Spec.Config.Spec.Plugin.Spec.(map[string]interface{})["proxy"].(map[string]interface{})["spec"].(map[string]interface{})
+	assert.Equal(t, proxy["url"], "http://team-a-prometheus.monitoring.svc:9090")
+
+	tq := &msoapi.ThanosQuerier{
+		ObjectMeta: metav1.ObjectMeta{Name: "global", Namespace: "monitoring"},
+		Spec: msoapi.ThanosQuerierSpec{
+			WebTLSConfig: &msoapi.WebTLSConfig{
+				CertificateAuthority: msoapi.SecretKeySelector{Name: "thanos-tls", Key: "ca.crt"},
+			},
+		},
+	}
+
+	datasource = newThanosQuerierDatasource(tq, true)
+	assert.Equal(t, datasource.Name, "thanos-querier-global-datasource")
+	assert.Assert(t, datasource.Spec.Config.Spec.Default)
+	proxy = datasource.Spec.Config.Spec.Plugin.Spec.(map[string]interface{})["proxy"].(map[string]interface{})["spec"].(map[string]interface{})
+	assert.Equal(t, proxy["url"], "https://thanos-querier-global.monitoring.svc:10902")
+	assert.Equal(t, proxy["secret"], "thanos-querier-global-datasource-secret")
+	caCert := datasource.Spec.Client.TLS.CaCert
+	assert.Equal(t, *caCert.Name, "thanos-tls")
+	assert.Equal(t, *caCert.Namespace, "monitoring")
+	assert.Equal(t, caCert.CertPath, "ca.crt")
+}
+
+func TestNewMonitoringStackDashboard(t *testing.T) {
+	ms := &msoapi.MonitoringStack{
+		ObjectMeta: metav1.ObjectMeta{Name: "team-a", Namespace: "monitoring"},
+	}
+
+	dashboard, err := newMonitoringStackDashboard(ms)
+	assert.NilError(t, err)
+	assert.Equal(t, dashboard.Name, "team-a-monitoring-stack")
+	assert.Equal(t, dashboard.Namespace, "monitoring")
+	// Ingestion, TSDB and Alertmanager.
+	assert.Equal(t, len(dashboard.Spec.Config.Spec.Layouts), 3)
+
+	ms.Spec.AlertmanagerConfig.Disabled = true
+	ms.Spec.PrometheusConfig = &msoapi.PrometheusConfig{
+		RemoteWrite: []monv1.RemoteWriteSpec{{URL: "https://remote.example.com/api/v1/write"}},
+	}
+	dashboard, err = newMonitoringStackDashboard(ms)
+	assert.NilError(t, err)
+	// Ingestion, TSDB and remote write.
+	assert.Equal(t, len(dashboard.Spec.Config.Spec.Layouts), 3)
+
+	tq := &msoapi.ThanosQuerier{
+		ObjectMeta: metav1.ObjectMeta{Name: "global", Namespace: "monitoring"},
+	}
+	dashboard, err = newThanosQuerierDashboard(tq)
+	assert.NilError(t, err)
+	assert.Equal(t, dashboard.Name, "thanos-querier-global")
+	assert.Equal(t, len(dashboard.Spec.Config.Spec.Layouts), 2)
+}
+
+func TestThanosQuerierDefaultDatasources(t *testing.T) {
+	workloads := &operatorWorkloads{
+		thanosQueriers: []msoapi.ThanosQuerier{
+			{ObjectMeta: metav1.ObjectMeta{Name: "global", Namespace: "team-a"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "team-b"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "b", Namespace: "team-b"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "global", Namespace: "team-c"}},
+		},
+	}
+	definitions := &persesDefinitions{
+		datasources: []*persesv1alpha2.PersesDatasource{
+			newWorkloadDatasource("custom", "team-c", "Custom", "prometheus.team-c.svc:9090", nil, true),
+		},
+	}
+
+	// team-b has several ThanosQueriers and team-c has a default datasource.
+	assert.DeepEqual(t, thanosQuerierDefaultNamespaces(workloads, definitions), map[string]bool{"team-a": true})
+}
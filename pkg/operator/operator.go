@@ -20,6 +20,7 @@ import (
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -36,6 +37,7 @@ import (
 	opctrl "github.com/rhobs/observability-operator/pkg/controllers/operator"
 	uictrl "github.com/rhobs/observability-operator/pkg/controllers/uiplugin"
 	ctrlutil "github.com/rhobs/observability-operator/pkg/controllers/util"
+	"github.com/rhobs/observability-operator/pkg/reconciler"
 )
 
 const (
@@ -51,6 +53,8 @@ type Operator struct {
 	restConfig            *rest.Config
 	servingCertController *dynamiccertificates.DynamicServingCertificateController
 	clientCAController    *dynamiccertificates.ConfigMapCAController
+	reportApplyDiff       bool
+	dryRun                bool
 }
 
 type OpenShiftFeatureGates struct {
@@ -76,12 +80,19 @@ type OperatorConfiguration struct {
 	TLSProfile             configv1.TLSProfileSpec
 	// CancelFunc is called to trigger graceful shutdown (e.g., on TLS profile change).
 	CancelFunc context.CancelFunc
+	// DryRun runs the controllers without mutating the cluster: all write
+	// requests are sent as server-side dry-run requests.
+	DryRun bool
+	// ReportApplyDiff logs the changes made by the controllers to the
+	// resources they manage. It is always enabled in dry-run mode.
+	ReportApplyDiff bool
 }
 
 type ObservabilityInstallerConfiguration struct {
-	COONamespace     string
-	OpenTelemetryCSV string
-	TempoCSV         string
+	COONamespace      string
+	OpenTelemetryCSV  string
+	TempoCSV          string
+	StorageProbeImage string
 }
 
 func WithNamespace(ns string) func(*OperatorConfiguration) {
@@ -133,6 +144,12 @@ func WithUIPluginImages(images map[string]string) func(*OperatorConfiguration) {
 	}
 }
 
+func WithUIPluginCompatibilityMatrix(path string) func(*OperatorConfiguration) {
+	return func(oc *OperatorConfiguration) {
+		oc.UIPlugins.CompatibilityMatrixFile = path
+	}
+}
+
 func WithFeatureGates(featureGates FeatureGates) func(*OperatorConfiguration) {
 	return func(oc *OperatorConfiguration) {
 		oc.FeatureGates = featureGates
@@ -159,6 +176,18 @@ func WithCancelFunc(cancel context.CancelFunc) func(*OperatorConfiguration) {
 	}
 }
 
+func WithDryRun(dryRun bool) func(*OperatorConfiguration) {
+	return func(oc *OperatorConfiguration) {
+		oc.DryRun = dryRun
+	}
+}
+
+func WithReportApplyDiff(report bool) func(*OperatorConfiguration) {
+	return func(oc *OperatorConfiguration) {
+		oc.ReportApplyDiff = report
+	}
+}
+
 func WithTLSProfile(tlsProfile configv1.TLSProfileSpec) func(*OperatorConfiguration) {
 	return func(oc *OperatorConfiguration) {
 		oc.TLSProfile = tlsProfile
@@ -318,6 +347,7 @@ func New(ctx context.Context, cfg *OperatorConfiguration) (*Operator, error) {
 			HealthProbeBindAddress: cfg.HealthProbeAddr,
 			PprofBindAddress:       "127.0.0.1:8083",
 			Cache:                  cacheOptions,
+			Client:                 client.Options{DryRun: ptr.To(cfg.DryRun)},
 		})
 	if err != nil {
 		return nil, fmt.Errorf("unable to create manager: %w", err)
@@ -349,15 +379,20 @@ func New(ctx context.Context, cfg *OperatorConfiguration) (*Operator, error) {
 		if err = watcher.SetupWithManager(mgr); err != nil {
 			return nil, fmt.Errorf("unable to setup TLS profile watcher: %w", err)
 		}
+	}
 
-		if err := uictrl.RegisterWithManager(mgr, uictrl.Options{
-			PluginsConf:    cfg.UIPlugins,
-			ClusterVersion: cfg.FeatureGates.OpenShift.Version,
-		}); err != nil {
-			return nil, fmt.Errorf("unable to register observability-ui-plugin controller: %w", err)
-		}
-	} else {
-		setupLog.Info("OpenShift feature gate is disabled, UIPlugins are not enabled")
+	// Without OpenShift, only the Perses and the health analyzer components
+	// of the Monitoring plugin are deployed since there is no console to
+	// register plugins.
+	if !cfg.FeatureGates.OpenShift.Enabled {
+		setupLog.Info("OpenShift feature gate is disabled, only the Perses and the health analyzer components of the Monitoring UIPlugin are enabled")
+	}
+	if err := uictrl.RegisterWithManager(mgr, uictrl.Options{
+		PluginsConf:    cfg.UIPlugins,
+		ClusterVersion: cfg.FeatureGates.OpenShift.Version,
+		OpenShift:      cfg.FeatureGates.OpenShift.Enabled,
+	}); err != nil {
+		return nil, fmt.Errorf("unable to register observability-ui-plugin controller: %w", err)
 	}
 
 	if cfg.FeatureGates.OpenShift.Enabled {
@@ -383,6 +418,7 @@ func New(ctx context.Context, cfg *OperatorConfiguration) (*Operator, error) {
 				StartingCSV: cfg.ObservabilityInstaller.TempoCSV,
 				Channel:     "stable",
 			},
+			StorageProbeImage: cfg.ObservabilityInstaller.StorageProbeImage,
 		}); err != nil {
 			return nil, fmt.Errorf("unable to register cluster observability controller: %w", err)
 		}
@@ -399,9 +435,12 @@ func New(ctx context.Context, cfg *OperatorConfiguration) (*Operator, error) {
 		restConfig:            restConfig,
 		servingCertController: servingCertController,
 		clientCAController:    clientCAController,
+		reportApplyDiff:       cfg.ReportApplyDiff || cfg.DryRun,
+		dryRun:                cfg.DryRun,
 	}
 
-	if cfg.FeatureGates.OpenShift.Enabled {
+	// The console deregistration would mutate the cluster on shutdown.
+	if cfg.FeatureGates.OpenShift.Enabled && !cfg.DryRun {
 		if err := mgr.Add(op.newShutdownCleanupRunnable()); err != nil {
 			return nil, fmt.Errorf("unable to add shutdown cleanup runnable: %w", err)
 		}
@@ -419,6 +458,15 @@ func (o *Operator) Start(ctx context.Context) error {
 		go o.servingCertController.Run(1, ctx.Done())
 	}
 
+	// The cache only holds the resources carrying the operator labels.
+	ctx = reconciler.WithLiveReader(ctx, o.manager.GetAPIReader())
+	if o.reportApplyDiff {
+		ctx = reconciler.WithDiffReporting(ctx)
+	}
+	if o.dryRun {
+		ctx = reconciler.WithDryRun(ctx)
+	}
+
 	if err := o.manager.Start(ctx); err != nil {
 		return fmt.Errorf("unable to start manager: %w", err)
 	}
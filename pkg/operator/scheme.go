@@ -35,6 +35,9 @@ func NewScheme(cfg *OperatorConfiguration) *runtime.Scheme {
 	utilruntime.Must(obsv1alpha1.AddToScheme(scheme))
 	utilruntime.Must(otelv1beta1.AddToScheme(scheme))
 	utilruntime.Must(tempov1alpha1.AddToScheme(scheme))
+	// The Perses resources are managed by the UIPlugin controller on
+	// OpenShift and on Kubernetes.
+	utilruntime.Must(persesv1alpha2.AddToScheme(scheme))
 
 	if cfg.FeatureGates.OpenShift.Enabled {
 		utilruntime.Must(configv1.Install(scheme))
@@ -42,7 +45,6 @@ func NewScheme(cfg *OperatorConfiguration) *runtime.Scheme {
 		utilruntime.Must(operatorv1.Install(scheme))
 		utilruntime.Must(corev1.AddToScheme(scheme))
 		utilruntime.Must(monv1.AddToScheme(scheme))
-		utilruntime.Must(persesv1alpha2.AddToScheme(scheme))
 		utilruntime.Must(olmv1alpha1.AddToScheme(scheme))
 
 		if uiplugin.IsVersionAheadOrEqual(cfg.FeatureGates.OpenShift.Version, "v4.19") {
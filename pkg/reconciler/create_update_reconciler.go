@@ -4,11 +4,13 @@ import (
 	"context"
 	"fmt"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	"github.com/rhobs/observability-operator/pkg/controllers/util"
 )
@@ -18,19 +20,35 @@ type createUpdateReconciler struct {
 	resource      client.Object
 }
 
-func (r createUpdateReconciler) Reconcile(ctx context.Context, c client.Client, scheme *runtime.Scheme) error {
-	// If the resource owner is in the same namespace as the resource, or if the resource owner is cluster scoped set the owner reference.
-	if r.resourceOwner.GetNamespace() == r.resource.GetNamespace() || r.resourceOwner.GetNamespace() == "" {
-		if err := controllerutil.SetControllerReference(r.resourceOwner, r.resource, scheme); err != nil {
-			return fmt.Errorf("%s/%s (%s): updater failed to set owner reference: %w",
-				r.resource.GetNamespace(), r.resource.GetName(),
-				r.resource.GetObjectKind().GroupVersionKind().String(), err)
-		}
+func (r createUpdateReconciler) Reconcile(ctx context.Context, c client.Client, scheme *runtime.Scheme, recorder EventRecorder) error {
+	if err := setOwner(r.resourceOwner, r.resource, scheme); err != nil {
+		err = fmt.Errorf("%s/%s (%s): updater failed to set owner reference: %w",
+			r.resource.GetNamespace(), r.resource.GetName(),
+			r.resource.GetObjectKind().GroupVersionKind().String(), err)
+		recorder.Failed(ctx, r.resource, "CreateOrUpdate", err)
+		return err
 	}
 
-	_, err := ctrl.CreateOrUpdate(ctx, c, r.resource, func() error { return nil })
+	description := resourceDescription(r.resource)
+	result, err := ctrl.CreateOrUpdate(ctx, c, r.resource, func() error { return nil })
+	if err != nil {
+		recorder.Failed(ctx, r.resource, "CreateOrUpdate", err)
+		return err
+	}
+	if result != controllerutil.OperationResultNone && DiffReportingEnabled(ctx) {
+		log.FromContext(ctx).Info("object "+string(result),
+			"object", client.ObjectKeyFromObject(r.resource),
+			"gvk", r.resource.GetObjectKind().GroupVersionKind().String())
+	}
+
+	switch result {
+	case controllerutil.OperationResultCreated:
+		recorder.Event(ctx, r.resource, corev1.EventTypeNormal, ReasonCreated, "Create", description+" created")
+	case controllerutil.OperationResultUpdated:
+		recorder.Event(ctx, r.resource, corev1.EventTypeNormal, ReasonUpdated, "Update", description+" updated")
+	}
 
-	return err
+	return nil
 }
 
 func NewCreateUpdateReconciler(resource client.Object, owner metav1.Object) Reconciler {
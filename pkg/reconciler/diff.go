@@ -0,0 +1,110 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-cmp/cmp"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+type diffReportingKey struct{}
+
+// WithDiffReporting returns a context which enables the reporting of the
+// changes made by the reconcilers. Before applying a resource, the Updater
+// computes the effective diff with a server-side dry-run and logs it.
+func WithDiffReporting(ctx context.Context) context.Context {
+	return context.WithValue(ctx, diffReportingKey{}, true)
+}
+
+// DiffReportingEnabled returns true if the context enables diff reporting.
+func DiffReportingEnabled(ctx context.Context) bool {
+	enabled, _ := ctx.Value(diffReportingKey{}).(bool)
+	return enabled
+}
+
+// reportApplyDiff logs the changes which applying the resource would make to
+// the cluster. Failures are logged and don't prevent the actual apply.
+func reportApplyDiff(ctx context.Context, c client.Client, scheme *runtime.Scheme, resource client.Object) {
+	logger := log.FromContext(ctx).WithValues(
+		"object", client.ObjectKeyFromObject(resource),
+		"gvk", resource.GetObjectKind().GroupVersionKind().String(),
+	)
+
+	diff, err := applyDiff(ctx, c, scheme, resource)
+	if err != nil {
+		logger.Error(err, "failed to compute apply diff")
+		return
+	}
+	if diff == "" {
+		logger.V(1).Info("apply diff: no changes")
+		return
+	}
+	logger.Info("apply diff", "diff", diff)
+}
+
+// applyDiff returns the difference between the resource in the cluster and
+// the result of a server-side dry-run apply of the desired resource.
+func applyDiff(ctx context.Context, c client.Client, scheme *runtime.Scheme, resource client.Object) (string, error) {
+	gvk, err := apiutil.GVKForObject(resource, scheme)
+	if err != nil {
+		return "", err
+	}
+	obj, err := scheme.New(gvk)
+	if err != nil {
+		return "", err
+	}
+	current := obj.(client.Object)
+	if err := liveReader(ctx, c).Get(ctx, client.ObjectKeyFromObject(resource), current); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return "", fmt.Errorf("failed to get current object: %w", err)
+		}
+		current = nil
+	} else {
+		// The type meta isn't always set by the client.
+		current.GetObjectKind().SetGroupVersionKind(gvk)
+	}
+
+	desired := resource.DeepCopyObject().(client.Object)
+	if err := c.Apply(ctx, &clientObjectApplyConfig{obj: desired}, client.ForceOwnership, client.FieldOwner(fieldOwner), client.DryRunAll); err != nil {
+		return "", fmt.Errorf("failed to dry-run apply: %w", err)
+	}
+	desired.GetObjectKind().SetGroupVersionKind(gvk)
+
+	return objectDiff(current, desired)
+}
+
+// objectDiff returns the difference between two objects, ignoring the fields
+// maintained by the API server. A nil current object means that the desired
+// object would be created.
+func objectDiff(current, desired client.Object) (string, error) {
+	var before map[string]any
+	if current != nil {
+		u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(current)
+		if err != nil {
+			return "", err
+		}
+		before = normalizeForDiff(u)
+	}
+
+	after, err := runtime.DefaultUnstructuredConverter.ToUnstructured(desired)
+	if err != nil {
+		return "", err
+	}
+
+	return cmp.Diff(before, normalizeForDiff(after)), nil
+}
+
+func normalizeForDiff(obj map[string]any) map[string]any {
+	delete(obj, "status")
+	if metadata, ok := obj["metadata"].(map[string]any); ok {
+		for _, field := range []string{"managedFields", "resourceVersion", "generation", "uid", "creationTimestamp"} {
+			delete(metadata, field)
+		}
+	}
+	return obj
+}
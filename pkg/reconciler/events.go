@@ -0,0 +1,102 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// Reasons of the events recorded for the reconciled resources.
+const (
+	ReasonCreated         = "Created"
+	ReasonUpdated         = "Updated"
+	ReasonDeleted         = "Deleted"
+	ReasonReconcileFailed = "ReconcileFailed"
+)
+
+// maxEventNoteLength is the maximum length of an event note accepted by the
+// Kubernetes API.
+const maxEventNoteLength = 1024
+
+type dryRunKey struct{}
+
+// WithDryRun returns a context which marks the reconciliation as a dry-run.
+// No events are recorded since the resources are not modified.
+func WithDryRun(ctx context.Context) context.Context {
+	return context.WithValue(ctx, dryRunKey{}, true)
+}
+
+// DryRunEnabled returns true if the context is marked as a dry-run.
+func DryRunEnabled(ctx context.Context) bool {
+	enabled, _ := ctx.Value(dryRunKey{}).(bool)
+	return enabled
+}
+
+// EventRecorder records the changes made to the reconciled resources as
+// Kubernetes events on their owner. The zero value doesn't record anything.
+type EventRecorder struct {
+	recorder events.EventRecorder
+	owner    runtime.Object
+}
+
+// NewEventRecorder returns an EventRecorder which records the events on owner.
+func NewEventRecorder(recorder events.EventRecorder, owner runtime.Object) EventRecorder {
+	return EventRecorder{recorder: recorder, owner: owner}
+}
+
+func (r EventRecorder) enabled(ctx context.Context) bool {
+	return r.recorder != nil && r.owner != nil && !DryRunEnabled(ctx)
+}
+
+// Event records an event about resource on the owner.
+func (r EventRecorder) Event(ctx context.Context, resource client.Object, eventtype, reason, action, note string) {
+	if !r.enabled(ctx) {
+		return
+	}
+	if len(note) > maxEventNoteLength {
+		note = note[:maxEventNoteLength-3] + "..."
+	}
+	r.recorder.Eventf(r.owner, resource, eventtype, reason, action, "%s", note)
+}
+
+// Failed records a warning event for a resource which failed to reconcile.
+// Conflicts due to a stale cache are retried by the controllers and aren't
+// recorded.
+func (r EventRecorder) Failed(ctx context.Context, resource client.Object, action string, err error) {
+	if apierrors.IsConflict(err) || apierrors.IsAlreadyExists(err) {
+		return
+	}
+	r.Event(ctx, resource, corev1.EventTypeWarning, ReasonReconcileFailed, action, err.Error())
+}
+
+// resourceVersion returns the resource version of the object in the cluster,
+// or an empty string if it doesn't exist. Only the metadata of the object is
+// read.
+func resourceVersion(ctx context.Context, reader client.Reader, scheme *runtime.Scheme, resource client.Object) (string, error) {
+	gvk, err := apiutil.GVKForObject(resource, scheme)
+	if err != nil {
+		return "", err
+	}
+	current := &metav1.PartialObjectMetadata{}
+	current.SetGroupVersionKind(gvk)
+	if err := reader.Get(ctx, client.ObjectKeyFromObject(resource), current); err != nil {
+		return "", client.IgnoreNotFound(err)
+	}
+	return current.GetResourceVersion(), nil
+}
+
+// resourceDescription returns a human-readable reference to the resource.
+func resourceDescription(resource client.Object) string {
+	kind := resource.GetObjectKind().GroupVersionKind().Kind
+	if resource.GetNamespace() == "" {
+		return fmt.Sprintf("%s %s", kind, resource.GetName())
+	}
+	return fmt.Sprintf("%s %s/%s", kind, resource.GetNamespace(), resource.GetName())
+}
@@ -0,0 +1,235 @@
+package reconciler
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/rhobs/observability-operator/pkg/controllers/util"
+)
+
+const (
+	// InventoryLabel is set on the inventory config maps.
+	InventoryLabel = "observability.openshift.io/inventory"
+
+	inventoryKey = "resources"
+)
+
+// inventoryEntry identifies a resource applied for an owner.
+type inventoryEntry struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+}
+
+func (e inventoryEntry) String() string {
+	return fmt.Sprintf("%s/%s %s/%s", e.APIVersion, e.Kind, e.Namespace, e.Name)
+}
+
+func compareInventoryEntries(a, b inventoryEntry) int {
+	return cmp.Or(
+		cmp.Compare(a.APIVersion, b.APIVersion),
+		cmp.Compare(a.Kind, b.Kind),
+		cmp.Compare(a.Namespace, b.Namespace),
+		cmp.Compare(a.Name, b.Name),
+	)
+}
+
+// inventory records the resources applied for an owner in a config map and
+// prunes the resources which were applied by the previous reconciliation but
+// aren't desired anymore.
+type inventory struct {
+	owner       client.Object
+	namespace   string
+	reconcilers []Reconciler
+}
+
+// NewInventory returns a reconciler which prunes the resources applied for
+// owner by a previous reconciliation which aren't part of reconcilers anymore.
+// The inventory is stored in a config map in namespace. It must run after the
+// other reconcilers succeeded.
+//
+// Only the resources managed by the operator for the owner are pruned: they
+// must be controlled by the owner or, when they can't reference the owner
+// (cluster-scoped resources or resources in another namespace), carry the
+// UID of the owner in the OwnerUIDLabel. The resources are read from the
+// cluster rather than the cache which doesn't hold all of them.
+func NewInventory(owner client.Object, namespace string, reconcilers []Reconciler) Reconciler {
+	return inventory{
+		owner:       owner,
+		namespace:   namespace,
+		reconcilers: reconcilers,
+	}
+}
+
+func (i inventory) Reconcile(ctx context.Context, c client.Client, scheme *runtime.Scheme, recorder EventRecorder) error {
+	cm, err := i.configMap(scheme)
+	if err != nil {
+		return fmt.Errorf("failed to create the inventory: %w", err)
+	}
+
+	desired, err := i.desiredEntries(scheme)
+	if err != nil {
+		return fmt.Errorf("%s/%s: failed to compute the inventory: %w", cm.Namespace, cm.Name, err)
+	}
+
+	previous, err := i.previousEntries(ctx, c, cm)
+	if err != nil {
+		return fmt.Errorf("%s/%s: failed to read the inventory: %w", cm.Namespace, cm.Name, err)
+	}
+
+	// Resources which fail to be pruned are kept in the inventory to be
+	// retried by the next reconciliation.
+	entries := desired
+	var errs []error
+	for _, entry := range previous {
+		if _, found := slices.BinarySearchFunc(desired, entry, compareInventoryEntries); found {
+			continue
+		}
+		if err := i.prune(ctx, c, scheme, recorder, entry); err != nil {
+			errs = append(errs, err)
+			entries = append(entries, entry)
+		}
+	}
+	slices.SortFunc(entries, compareInventoryEntries)
+
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return fmt.Errorf("%s/%s: failed to encode the inventory: %w", cm.Namespace, cm.Name, err)
+	}
+	cm.Data = map[string]string{inventoryKey: string(data)}
+	if err := NewUpdater(cm, i.owner).Reconcile(ctx, c, scheme, recorder); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+func (i inventory) configMap(scheme *runtime.Scheme) (*corev1.ConfigMap, error) {
+	gvk, err := apiutil.GVKForObject(i.owner, scheme)
+	if err != nil {
+		return nil, err
+	}
+	return &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: corev1.SchemeGroupVersion.String(),
+			Kind:       "ConfigMap",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-%s-inventory", i.owner.GetName(), strings.ToLower(gvk.Kind)),
+			Namespace: i.namespace,
+			Labels: map[string]string{
+				InventoryLabel: "true",
+			},
+		},
+	}, nil
+}
+
+// desiredEntries returns the sorted entries of the resources applied by the reconcilers.
+func (i inventory) desiredEntries(scheme *runtime.Scheme) ([]inventoryEntry, error) {
+	var entries []inventoryEntry
+	for _, r := range i.reconcilers {
+		var resource client.Object
+		switch r := r.(type) {
+		case Updater:
+			resource = r.resource
+		case createUpdateReconciler:
+			resource = r.resource
+		default:
+			continue
+		}
+
+		gvk, err := apiutil.GVKForObject(resource, scheme)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, inventoryEntry{
+			APIVersion: gvk.GroupVersion().String(),
+			Kind:       gvk.Kind,
+			Namespace:  resource.GetNamespace(),
+			Name:       resource.GetName(),
+		})
+	}
+	slices.SortFunc(entries, compareInventoryEntries)
+	return slices.CompactFunc(entries, func(a, b inventoryEntry) bool {
+		return compareInventoryEntries(a, b) == 0
+	}), nil
+}
+
+func (i inventory) previousEntries(ctx context.Context, c client.Client, cm *corev1.ConfigMap) ([]inventoryEntry, error) {
+	previous := &corev1.ConfigMap{}
+	if err := liveReader(ctx, c).Get(ctx, client.ObjectKeyFromObject(cm), previous); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+
+	var entries []inventoryEntry
+	if data, ok := previous.Data[inventoryKey]; ok {
+		if err := json.Unmarshal([]byte(data), &entries); err != nil {
+			return nil, err
+		}
+	}
+	return entries, nil
+}
+
+// prune deletes the resource of the entry if it's managed for the owner.
+func (i inventory) prune(ctx context.Context, c client.Client, scheme *runtime.Scheme, recorder EventRecorder, entry inventoryEntry) error {
+	logger := log.FromContext(ctx).WithValues("resource", entry.String())
+
+	gv, err := schema.ParseGroupVersion(entry.APIVersion)
+	if err != nil {
+		logger.Error(err, "dropping invalid inventory entry")
+		return nil
+	}
+	gvk := gv.WithKind(entry.Kind)
+	obj, err := scheme.New(gvk)
+	if err != nil {
+		logger.Error(err, "dropping inventory entry of unknown kind")
+		return nil
+	}
+	resource := obj.(client.Object)
+
+	if err := liveReader(ctx, c).Get(ctx, client.ObjectKey{Namespace: entry.Namespace, Name: entry.Name}, resource); err != nil {
+		if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return nil
+		}
+		return fmt.Errorf("%s: failed to get resource to prune: %w", entry, err)
+	}
+
+	if !i.manages(resource) {
+		logger.V(1).Info("not pruning resource which isn't managed for the owner anymore")
+		return nil
+	}
+
+	resource.GetObjectKind().SetGroupVersionKind(gvk)
+	return NewDeleter(resource).Reconcile(ctx, c, scheme, recorder)
+}
+
+// manages returns true if the resource is managed by the operator for the owner.
+func (i inventory) manages(resource client.Object) bool {
+	labels := resource.GetLabels()
+	if labels[util.ResourceLabel] != util.OpName {
+		return false
+	}
+
+	if ref := metav1.GetControllerOf(resource); ref != nil {
+		return ref.UID == i.owner.GetUID()
+	}
+
+	canReferenceOwner := i.owner.GetNamespace() == "" || i.owner.GetNamespace() == resource.GetNamespace()
+	return !canReferenceOwner && labels[OwnerUIDLabel] == string(i.owner.GetUID())
+}
@@ -0,0 +1,158 @@
+package reconciler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestInventory(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+
+	owner := &corev1.ConfigMap{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+		ObjectMeta: metav1.ObjectMeta{Name: "owner", Namespace: "default", UID: "owner-uid"},
+	}
+	newSecret := func(name string) *corev1.Secret {
+		return &corev1.Secret{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		}
+	}
+	newClusterRole := func(name string) *rbacv1.ClusterRole {
+		return &rbacv1.ClusterRole{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRole"},
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+		}
+	}
+	// unmanaged isn't pruned because it isn't controlled by the owner.
+	unmanaged := newSecret("unmanaged")
+	unmanaged.Labels = map[string]string{"app.kubernetes.io/managed-by": "observability-operator"}
+	// other is controlled by another owner.
+	other := newClusterRole("other")
+	other.Labels = map[string]string{"app.kubernetes.io/managed-by": "observability-operator"}
+	other.OwnerReferences = []metav1.OwnerReference{{Kind: "ConfigMap", Name: "other", UID: "other-uid", Controller: ptr.To(true)}}
+	// namesake is managed for another owner with the same name.
+	namesake := newClusterRole("namesake")
+	namesake.Labels = map[string]string{
+		"app.kubernetes.io/managed-by": "observability-operator",
+		"app.kubernetes.io/part-of":    "owner",
+		OwnerUIDLabel:                  "other-uid",
+	}
+
+	c := newFakeApplyClient(t, scheme, unmanaged, other, namesake)
+	ctx := context.Background()
+
+	reconcile := func(reconcilers ...Reconciler) {
+		t.Helper()
+		reconcilers = append(reconcilers, NewInventory(owner, "default", reconcilers))
+		for _, r := range reconcilers {
+			require.NoError(t, r.Reconcile(ctx, c, scheme, EventRecorder{}))
+		}
+	}
+	exists := func(obj client.Object) bool {
+		t.Helper()
+		err := c.Get(ctx, client.ObjectKeyFromObject(obj), obj)
+		if apierrors.IsNotFound(err) {
+			return false
+		}
+		require.NoError(t, err)
+		return true
+	}
+
+	// The first reconciliation only records the applied resources.
+	reconcile(
+		NewUpdater(newSecret("a"), owner),
+		NewUpdater(newSecret("b"), owner),
+		NewUpdater(newClusterRole("a"), owner),
+		NewUpdater(newClusterRole("b"), owner),
+	)
+	for _, obj := range []client.Object{newSecret("a"), newSecret("b"), newClusterRole("a"), newClusterRole("b")} {
+		require.True(t, exists(obj), obj.GetName())
+	}
+	clusterRole := newClusterRole("b")
+	require.True(t, exists(clusterRole))
+	require.Equal(t, "owner-uid", clusterRole.Labels[OwnerUIDLabel])
+	cm := &corev1.ConfigMap{}
+	require.NoError(t, c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "owner-configmap-inventory"}, cm))
+	require.Equal(t, "true", cm.Labels[InventoryLabel])
+	require.Contains(t, cm.Data[inventoryKey], `"kind":"ClusterRole"`)
+
+	// Add the resources which aren't managed for the owner to the inventory.
+	cm.Data[inventoryKey] = `[` +
+		`{"apiVersion":"rbac.authorization.k8s.io/v1","kind":"ClusterRole","name":"a"},` +
+		`{"apiVersion":"rbac.authorization.k8s.io/v1","kind":"ClusterRole","name":"b"},` +
+		`{"apiVersion":"rbac.authorization.k8s.io/v1","kind":"ClusterRole","name":"namesake"},` +
+		`{"apiVersion":"rbac.authorization.k8s.io/v1","kind":"ClusterRole","name":"other"},` +
+		`{"apiVersion":"v1","kind":"Secret","namespace":"default","name":"a"},` +
+		`{"apiVersion":"v1","kind":"Secret","namespace":"default","name":"b"},` +
+		`{"apiVersion":"v1","kind":"Secret","namespace":"default","name":"unmanaged"},` +
+		`{"apiVersion":"example.com/v1","kind":"Unknown","name":"unknown"}]`
+	require.NoError(t, c.Update(ctx, cm))
+
+	// The resources which aren't desired anymore are pruned.
+	reconcile(
+		NewUpdater(newSecret("a"), owner),
+		NewUpdater(newClusterRole("a"), owner),
+		NewDeleter(newSecret("c")),
+	)
+	require.True(t, exists(newSecret("a")))
+	require.True(t, exists(newClusterRole("a")))
+	require.False(t, exists(newSecret("b")))
+	require.False(t, exists(newClusterRole("b")))
+	require.True(t, exists(newSecret("unmanaged")))
+	require.True(t, exists(newClusterRole("other")))
+	require.True(t, exists(newClusterRole("namesake")))
+
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(cm), cm))
+	require.JSONEq(t, `[`+
+		`{"apiVersion":"rbac.authorization.k8s.io/v1","kind":"ClusterRole","name":"a"},`+
+		`{"apiVersion":"v1","kind":"Secret","namespace":"default","name":"a"}]`,
+		cm.Data[inventoryKey])
+}
+
+func TestInventoryPrunesResourcesOutsideTheCache(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+
+	owner := &corev1.ConfigMap{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+		ObjectMeta: metav1.ObjectMeta{Name: "owner", Namespace: "default", UID: "owner-uid"},
+	}
+	service := &corev1.Service{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Service"},
+		ObjectMeta: metav1.ObjectMeta{Name: "svc", Namespace: "default"},
+	}
+	c := newFakeApplyClient(t, scheme)
+	ctx := WithLiveReader(context.Background(), c)
+	// The cache of the client doesn't serve the services.
+	cached := interceptor.NewClient(c.(client.WithWatch), interceptor.Funcs{
+		Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+			if _, ok := obj.(*corev1.Service); ok {
+				return apierrors.NewNotFound(corev1.Resource("services"), key.Name)
+			}
+			return c.Get(ctx, key, obj, opts...)
+		},
+	})
+
+	for _, reconcilers := range [][]Reconciler{{NewUpdater(service.DeepCopy(), owner)}, nil} {
+		reconcilers = append(reconcilers, NewInventory(owner, "default", reconcilers))
+		for _, r := range reconcilers {
+			require.NoError(t, r.Reconcile(ctx, cached, scheme, EventRecorder{}))
+		}
+	}
+
+	err := c.Get(ctx, client.ObjectKeyFromObject(service), &corev1.Service{})
+	require.True(t, apierrors.IsNotFound(err))
+}
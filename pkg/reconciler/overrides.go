@@ -0,0 +1,113 @@
+package reconciler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	commonv1alpha1 "github.com/rhobs/observability-operator/pkg/apis/common/v1alpha1"
+)
+
+// Reasons and message of the OverridesApplied condition which the controllers
+// set from the error returned by ApplyOverrides.
+const (
+	OverridesAppliedReason       = "OverridesApplied"
+	FailedToApplyOverridesReason = "FailedToApplyOverrides"
+	OverridesAppliedMessage      = "All overrides are applied"
+)
+
+// ApplyOverrides patches the resources applied by the reconcilers with the
+// overrides, before they are reconciled. An override applies to the resources
+// matching its kind and name.
+//
+// The returned error reports the overrides which failed to be applied or
+// which don't match any resource. The resources are left unpatched by the
+// failing overrides.
+func ApplyOverrides(reconcilers []Reconciler, overrides []commonv1alpha1.ResourceOverride) error {
+	if len(overrides) == 0 {
+		return nil
+	}
+
+	var resources []client.Object
+	for _, r := range reconcilers {
+		switch r := r.(type) {
+		case Updater:
+			resources = append(resources, r.resource)
+		case createUpdateReconciler:
+			resources = append(resources, r.resource)
+		}
+	}
+
+	var errs []error
+	for i, override := range overrides {
+		matched := false
+		for _, resource := range resources {
+			if resource.GetObjectKind().GroupVersionKind().Kind != override.Kind || resource.GetName() != override.Name {
+				continue
+			}
+			matched = true
+
+			if err := applyOverride(resource, override); err != nil {
+				errs = append(errs, fmt.Errorf("override %d (%s %s): %w", i, override.Kind, override.Name, err))
+			}
+		}
+		if !matched {
+			errs = append(errs, fmt.Errorf("override %d (%s %s): no matching resource", i, override.Kind, override.Name))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// applyOverride patches the resource in place. The resource isn't modified
+// when the patch fails.
+func applyOverride(resource client.Object, override commonv1alpha1.ResourceOverride) error {
+	patch, err := yaml.YAMLToJSON([]byte(override.Patch))
+	if err != nil {
+		return fmt.Errorf("invalid patch: %w", err)
+	}
+
+	original, err := json.Marshal(resource)
+	if err != nil {
+		return err
+	}
+
+	var patched []byte
+	switch override.GetType() {
+	case commonv1alpha1.StrategicMergePatchType:
+		patched, err = strategicpatch.StrategicMergePatch(original, patch, resource)
+	case commonv1alpha1.JSONPatchType:
+		var p jsonpatch.Patch
+		if p, err = jsonpatch.DecodePatch(patch); err != nil {
+			return fmt.Errorf("invalid patch: %w", err)
+		}
+		patched, err = p.Apply(original)
+	default:
+		return fmt.Errorf("unsupported patch type %q", override.Type)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to apply patch: %w", err)
+	}
+
+	// Decode the patched resource in a new object so that the fields removed
+	// by the patch are unset.
+	result := reflect.New(reflect.TypeOf(resource).Elem()).Interface().(client.Object)
+	if err := json.Unmarshal(patched, result); err != nil {
+		return fmt.Errorf("invalid patched resource: %w", err)
+	}
+	if result.GetName() != resource.GetName() || result.GetNamespace() != resource.GetNamespace() {
+		return fmt.Errorf("the name and the namespace can't be changed")
+	}
+	if result.GetObjectKind().GroupVersionKind() != resource.GetObjectKind().GroupVersionKind() {
+		return fmt.Errorf("the apiVersion and the kind can't be changed")
+	}
+
+	reflect.ValueOf(resource).Elem().Set(reflect.ValueOf(result).Elem())
+	return nil
+}
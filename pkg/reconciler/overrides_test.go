@@ -0,0 +1,149 @@
+package reconciler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	commonv1alpha1 "github.com/rhobs/observability-operator/pkg/apis/common/v1alpha1"
+)
+
+func TestApplyOverrides(t *testing.T) {
+	owner := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "owner", Namespace: "ns"}}
+	newDeployment := func() *appsv1.Deployment {
+		return &appsv1.Deployment{
+			TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test",
+				Namespace: "ns",
+			},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: ptr.To(int32(1)),
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{
+							{Name: "main", Image: "image:v1"},
+							{Name: "sidecar", Image: "sidecar:v1"},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	for _, tc := range []struct {
+		name      string
+		overrides []commonv1alpha1.ResourceOverride
+		expected  func(*appsv1.Deployment)
+		err       string
+	}{
+		{
+			name: "no overrides",
+		},
+		{
+			name: "strategic merge patch",
+			overrides: []commonv1alpha1.ResourceOverride{{
+				Kind: "Deployment",
+				Name: "test",
+				Patch: `
+spec:
+  template:
+    spec:
+      containers:
+      - name: sidecar
+        image: sidecar:v2
+`,
+			}},
+			expected: func(d *appsv1.Deployment) {
+				d.Spec.Template.Spec.Containers[1].Image = "sidecar:v2"
+			},
+		},
+		{
+			name: "JSON patch",
+			overrides: []commonv1alpha1.ResourceOverride{{
+				Kind:  "Deployment",
+				Name:  "test",
+				Type:  commonv1alpha1.JSONPatchType,
+				Patch: `[{"op": "replace", "path": "/spec/replicas", "value": 3}, {"op": "remove", "path": "/spec/template/spec/containers/1"}]`,
+			}},
+			expected: func(d *appsv1.Deployment) {
+				d.Spec.Replicas = ptr.To(int32(3))
+				d.Spec.Template.Spec.Containers = d.Spec.Template.Spec.Containers[:1]
+			},
+		},
+		{
+			name: "no matching resource",
+			overrides: []commonv1alpha1.ResourceOverride{{
+				Kind:  "Deployment",
+				Name:  "other",
+				Patch: `{"spec": {"replicas": 3}}`,
+			}},
+			err: "override 0 (Deployment other): no matching resource",
+		},
+		{
+			name: "invalid JSON patch",
+			overrides: []commonv1alpha1.ResourceOverride{{
+				Kind:  "Deployment",
+				Name:  "test",
+				Type:  commonv1alpha1.JSONPatchType,
+				Patch: `{"spec": {"replicas": 3}}`,
+			}},
+			err: "override 0 (Deployment test): invalid patch",
+		},
+		{
+			name: "name change",
+			overrides: []commonv1alpha1.ResourceOverride{{
+				Kind:  "Deployment",
+				Name:  "test",
+				Patch: `{"metadata": {"name": "renamed"}}`,
+			}},
+			err: "the name and the namespace can't be changed",
+		},
+		{
+			name: "failed override doesn't prevent the others",
+			overrides: []commonv1alpha1.ResourceOverride{
+				{
+					Kind:  "Deployment",
+					Name:  "other",
+					Patch: `{"spec": {"replicas": 2}}`,
+				},
+				{
+					Kind:  "Deployment",
+					Name:  "test",
+					Patch: `{"spec": {"replicas": 3}}`,
+				},
+			},
+			expected: func(d *appsv1.Deployment) {
+				d.Spec.Replicas = ptr.To(int32(3))
+			},
+			err: "override 0 (Deployment other): no matching resource",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			deployment := newDeployment()
+			reconcilers := []Reconciler{
+				NewUpdater(deployment, owner),
+				NewDeleter(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "ns"}}),
+			}
+
+			err := ApplyOverrides(reconcilers, tc.overrides)
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			expected := newDeployment()
+			// The updater sets the owner reference and the managed-by label.
+			expected.ObjectMeta = deployment.ObjectMeta
+			if tc.expected != nil {
+				tc.expected(expected)
+			}
+			require.Equal(t, expected, reconcilers[0].(Updater).resource)
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package reconciler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Step identifies a reconciler added to a Plan.
+type Step int
+
+type planStep struct {
+	reconciler   Reconciler
+	dependencies []Step
+}
+
+// Plan runs reconcilers in the order they were added. Unlike a plain loop
+// over the reconcilers, a failing reconciler doesn't stop the plan: only the
+// reconcilers which depend on it, directly or not, are skipped.
+type Plan struct {
+	steps []planStep
+}
+
+// NewPlan returns an empty plan.
+func NewPlan() *Plan {
+	return &Plan{}
+}
+
+// Add adds a reconciler which runs only if its dependencies succeeded.
+func (p *Plan) Add(r Reconciler, dependencies ...Step) Step {
+	p.steps = append(p.steps, planStep{reconciler: r, dependencies: dependencies})
+	return Step(len(p.steps) - 1)
+}
+
+// AddAll adds independent reconcilers which share the same dependencies.
+func (p *Plan) AddAll(reconcilers []Reconciler, dependencies ...Step) []Step {
+	steps := make([]Step, 0, len(reconcilers))
+	for _, r := range reconcilers {
+		steps = append(steps, p.Add(r, dependencies...))
+	}
+	return steps
+}
+
+// Reconcilers returns the reconcilers of the plan in order.
+func (p *Plan) Reconcilers() []Reconciler {
+	reconcilers := make([]Reconciler, 0, len(p.steps))
+	for _, s := range p.steps {
+		reconcilers = append(reconcilers, s.reconciler)
+	}
+	return reconcilers
+}
+
+// Reconcile runs the plan. It returns a *PlanError reporting the outcome of
+// the reconcilers which failed or were skipped.
+func (p *Plan) Reconcile(ctx context.Context, c client.Client, scheme *runtime.Scheme, recorder EventRecorder) error {
+	logger := log.FromContext(ctx)
+
+	failed := make([]bool, len(p.steps))
+	var outcomes []Outcome
+	for i, s := range p.steps {
+		// The description is computed before running the reconciler which
+		// may reset the type information of the resource.
+		description := reconcilerDescription(s.reconciler)
+
+		if dependencyFailed(failed, s.dependencies) {
+			failed[i] = true
+			outcomes = append(outcomes, Outcome{Description: description, Skipped: true})
+			logger.V(1).Info("skipping reconciler with failed dependencies", "reconciler", description)
+			continue
+		}
+
+		if err := s.reconciler.Reconcile(ctx, c, scheme, recorder); err != nil {
+			failed[i] = true
+			outcomes = append(outcomes, Outcome{Description: description, Err: err})
+		}
+	}
+
+	if len(outcomes) == 0 {
+		return nil
+	}
+	return &PlanError{Outcomes: outcomes, Total: len(p.steps)}
+}
+
+func dependencyFailed(failed []bool, dependencies []Step) bool {
+	for _, d := range dependencies {
+		if failed[d] {
+			return true
+		}
+	}
+	return false
+}
+
+// Outcome is the outcome of a reconciler which didn't succeed.
+type Outcome struct {
+	// Description identifies the reconciled resource.
+	Description string
+	// Err is the error returned by the reconciler.
+	Err error
+	// Skipped is true when the reconciler didn't run because one of its
+	// dependencies failed.
+	Skipped bool
+}
+
+func (o Outcome) String() string {
+	if o.Skipped {
+		return fmt.Sprintf("%s: skipped since a dependency failed", o.Description)
+	}
+	return o.Err.Error()
+}
+
+// PlanError reports the reconcilers of a plan which failed or were skipped.
+type PlanError struct {
+	Outcomes []Outcome
+	// Total is the number of reconcilers in the plan.
+	Total int
+}
+
+func (e *PlanError) Error() string {
+	messages := make([]string, 0, len(e.Outcomes))
+	for _, o := range e.Outcomes {
+		messages = append(messages, o.String())
+	}
+	return fmt.Sprintf("%d of %d resources not reconciled: %s", len(e.Outcomes), e.Total, strings.Join(messages, "; "))
+}
+
+// Unwrap returns the errors of the reconcilers which failed so that the
+// callers can check for specific errors with errors.Is and errors.As.
+func (e *PlanError) Unwrap() []error {
+	var errs []error
+	for _, o := range e.Outcomes {
+		if o.Err != nil {
+			errs = append(errs, o.Err)
+		}
+	}
+	return errs
+}
+
+// IsStaleCacheError returns true if err only reports conflicts and resources
+// which already exist. Such errors are caused by a stale cache and resolved by
+// reconciling again. A plan error is a stale cache error only if all the
+// failed reconcilers returned one: any other failure must be reported.
+func IsStaleCacheError(err error) bool {
+	var planErr *PlanError
+	if !errors.As(err, &planErr) {
+		return apierrors.IsAlreadyExists(err) || apierrors.IsConflict(err)
+	}
+	errs := planErr.Unwrap()
+	if len(errs) == 0 {
+		return false
+	}
+	for _, err := range errs {
+		if !IsStaleCacheError(err) {
+			return false
+		}
+	}
+	return true
+}
+
+// reconcilerDescription returns a human-readable reference to the resource
+// handled by the reconciler.
+func reconcilerDescription(r Reconciler) string {
+	switch r := r.(type) {
+	case Updater:
+		return resourceDescription(r.resource)
+	case createUpdateReconciler:
+		return resourceDescription(r.resource)
+	case Deleter:
+		return resourceDescription(r.resource)
+	case inventory:
+		return fmt.Sprintf("inventory of %s", r.owner.GetName())
+	case fmt.Stringer:
+		return r.String()
+	default:
+		return fmt.Sprintf("%T", r)
+	}
+}
@@ -0,0 +1,106 @@
+package reconciler
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// fakeReconciler records its name when it runs and returns err.
+type fakeReconciler struct {
+	name string
+	err  error
+	ran  *[]string
+}
+
+func (r fakeReconciler) String() string {
+	return r.name
+}
+
+func (r fakeReconciler) Reconcile(_ context.Context, _ client.Client, _ *runtime.Scheme, _ EventRecorder) error {
+	*r.ran = append(*r.ran, r.name)
+	return r.err
+}
+
+func TestPlan(t *testing.T) {
+	t.Run("all reconcilers succeed", func(t *testing.T) {
+		var ran []string
+		plan := NewPlan()
+		a := plan.Add(fakeReconciler{name: "a", ran: &ran})
+		plan.AddAll([]Reconciler{
+			fakeReconciler{name: "b", ran: &ran},
+			fakeReconciler{name: "c", ran: &ran},
+		}, a)
+
+		require.NoError(t, plan.Reconcile(context.Background(), nil, nil, EventRecorder{}))
+		require.Equal(t, []string{"a", "b", "c"}, ran)
+		require.Len(t, plan.Reconcilers(), 3)
+	})
+
+	t.Run("failure skips the dependent reconcilers only", func(t *testing.T) {
+		var ran []string
+		plan := NewPlan()
+		a := plan.Add(fakeReconciler{name: "a", err: errors.New("a failed"), ran: &ran})
+		b := plan.Add(fakeReconciler{name: "b", ran: &ran})
+		c := plan.Add(fakeReconciler{name: "c", ran: &ran}, a)
+		plan.Add(fakeReconciler{name: "d", ran: &ran}, b, c)
+		plan.Add(fakeReconciler{name: "e", ran: &ran}, b)
+
+		err := plan.Reconcile(context.Background(), nil, nil, EventRecorder{})
+		require.Equal(t, []string{"a", "b", "e"}, ran)
+
+		var planErr *PlanError
+		require.ErrorAs(t, err, &planErr)
+		require.Equal(t, 5, planErr.Total)
+		require.Len(t, planErr.Outcomes, 3)
+		require.Equal(t, "3 of 5 resources not reconciled: a failed; c: skipped since a dependency failed; d: skipped since a dependency failed", err.Error())
+	})
+
+	t.Run("errors are unwrapped", func(t *testing.T) {
+		var ran []string
+		conflict := apierrors.NewConflict(schema.GroupResource{Resource: "configmaps"}, "test", errors.New("stale"))
+		plan := NewPlan()
+		plan.Add(fakeReconciler{name: "a", err: errors.New("a failed"), ran: &ran})
+		plan.Add(fakeReconciler{name: "b", err: conflict, ran: &ran})
+
+		err := plan.Reconcile(context.Background(), nil, nil, EventRecorder{})
+		require.True(t, apierrors.IsConflict(err))
+		require.ErrorIs(t, err, conflict)
+	})
+}
+
+func TestIsStaleCacheError(t *testing.T) {
+	conflict := apierrors.NewConflict(schema.GroupResource{Resource: "configmaps"}, "test", errors.New("stale"))
+	exists := apierrors.NewAlreadyExists(schema.GroupResource{Resource: "configmaps"}, "test")
+	newPlanError := func(errs ...error) error {
+		var ran []string
+		plan := NewPlan()
+		for _, err := range errs {
+			step := plan.Add(fakeReconciler{name: "a", err: err, ran: &ran})
+			plan.Add(fakeReconciler{name: "b", ran: &ran}, step)
+		}
+		return plan.Reconcile(context.Background(), nil, nil, EventRecorder{})
+	}
+
+	for _, tc := range []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "no error"},
+		{name: "conflict", err: conflict, want: true},
+		{name: "other error", err: errors.New("failed")},
+		{name: "plan with stale cache errors only", err: newPlanError(conflict, exists), want: true},
+		{name: "plan with another error", err: newPlanError(conflict, errors.New("failed"))},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, IsStaleCacheError(tc.err))
+		})
+	}
+}
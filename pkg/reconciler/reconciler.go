@@ -5,10 +5,12 @@ import (
 	"encoding/json"
 	"fmt"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	"github.com/rhobs/observability-operator/pkg/controllers/util"
 )
@@ -17,13 +19,40 @@ const (
 	// OpenshiftMonitoringNamespace is the namespace in which the OpenShift
 	// monitoring components are deployed.
 	OpenshiftMonitoringNamespace = "openshift-monitoring"
+
+	// fieldOwner is the field manager used for server-side apply.
+	fieldOwner = "observability-operator"
+
+	// OwnerUIDLabel identifies the owner of the resources which can't hold
+	// a controller reference to it: cluster-scoped resources and resources
+	// in another namespace than the owner. The value is the UID of the owner.
+	OwnerUIDLabel = "observability.openshift.io/owner-uid"
 )
 
+type liveReaderKey struct{}
+
+// WithLiveReader returns a context in which the reconcilers read the current
+// state of the resources with reader instead of their client. The cache of
+// the manager only holds the resources matching its label selectors, an
+// uncached reader such as the API reader of the manager sees all of them.
+func WithLiveReader(ctx context.Context, reader client.Reader) context.Context {
+	return context.WithValue(ctx, liveReaderKey{}, reader)
+}
+
+// liveReader returns the reader of the context, or c if it has none.
+func liveReader(ctx context.Context, c client.Reader) client.Reader {
+	if reader, ok := ctx.Value(liveReaderKey{}).(client.Reader); ok {
+		return reader
+	}
+	return c
+}
+
 // This interface is used by the resourceManagers to reconcile the resources they
 // watch. If any component needs special treatment in the reconcile loop, create
-// a new type that implements this interface.
+// a new type that implements this interface. The changes made to the resources
+// are recorded as events with the recorder.
 type Reconciler interface {
-	Reconcile(ctx context.Context, c client.Client, scheme *runtime.Scheme) error
+	Reconcile(ctx context.Context, c client.Client, scheme *runtime.Scheme, recorder EventRecorder) error
 }
 
 // Updater simply updates a resource by setting a controller reference
@@ -34,30 +63,78 @@ type Updater struct {
 	shouldBypassSetCtrlRef bool
 }
 
-func (r Updater) Reconcile(ctx context.Context, c client.Client, scheme *runtime.Scheme) error {
+func (r Updater) Reconcile(ctx context.Context, c client.Client, scheme *runtime.Scheme, recorder EventRecorder) error {
 	// Only set the controller reference if the bypass flag is false.
 	// Bypassing allows other operators to own the resource
 	// (e.g. Observability-operator creates the perses instance. But Perses-operator manages the perses instance)
 	if !r.shouldBypassSetCtrlRef {
-		// If the resource owner is in the same namespace as the resource, or if the resource owner is cluster scoped set the owner reference.
-		if r.resourceOwner.GetNamespace() == r.resource.GetNamespace() || r.resourceOwner.GetNamespace() == "" {
-			if err := controllerutil.SetControllerReference(r.resourceOwner, r.resource, scheme); err != nil {
-				return fmt.Errorf("%s/%s (%s): updater failed to set owner reference: %w",
-					r.resource.GetNamespace(), r.resource.GetName(),
-					r.resource.GetObjectKind().GroupVersionKind().String(), err)
-			}
+		if err := setOwner(r.resourceOwner, r.resource, scheme); err != nil {
+			err = fmt.Errorf("%s/%s (%s): updater failed to set owner reference: %w",
+				r.resource.GetNamespace(), r.resource.GetName(),
+				r.resource.GetObjectKind().GroupVersionKind().String(), err)
+			recorder.Failed(ctx, r.resource, "Apply", err)
+			return err
+		}
+	}
+
+	if DiffReportingEnabled(ctx) {
+		reportApplyDiff(ctx, c, scheme, r.resource)
+	}
+
+	// The previous resource version tells whether the apply created or
+	// changed the resource. It's read from the cluster since the cache
+	// doesn't hold all the resources.
+	var (
+		previousVersion string
+		recordChanges   = recorder.enabled(ctx)
+	)
+	if recordChanges {
+		var err error
+		if previousVersion, err = resourceVersion(ctx, liveReader(ctx, c), scheme, r.resource); err != nil {
+			log.FromContext(ctx).V(1).Info("failed to get the resource version, changes won't be recorded",
+				"object", client.ObjectKeyFromObject(r.resource), "err", err)
+			recordChanges = false
 		}
 	}
 
-	if err := c.Apply(ctx, &clientObjectApplyConfig{obj: r.resource}, client.ForceOwnership, client.FieldOwner("observability-operator")); err != nil {
-		return fmt.Errorf("%s/%s (%s): updater failed to apply: %w",
+	// The apply response may not set the type meta of the resource.
+	description := resourceDescription(r.resource)
+	if err := c.Apply(ctx, &clientObjectApplyConfig{obj: r.resource}, client.ForceOwnership, client.FieldOwner(fieldOwner)); err != nil {
+		err = fmt.Errorf("%s/%s (%s): updater failed to apply: %w",
 			r.resource.GetNamespace(), r.resource.GetName(),
 			r.resource.GetObjectKind().GroupVersionKind().String(), err)
+		recorder.Failed(ctx, r.resource, "Apply", err)
+		return err
+	}
+
+	switch {
+	case !recordChanges:
+	case previousVersion == "":
+		recorder.Event(ctx, r.resource, corev1.EventTypeNormal, ReasonCreated, "Create", description+" created")
+	case previousVersion != r.resource.GetResourceVersion():
+		recorder.Event(ctx, r.resource, corev1.EventTypeNormal, ReasonUpdated, "Update", description+" updated")
 	}
 
 	return nil
 }
 
+// setOwner sets the controller reference of owner on the resource if the
+// owner is in the same namespace as the resource or cluster-scoped. Otherwise
+// the resource is labelled with the UID of the owner.
+func setOwner(owner metav1.Object, resource client.Object, scheme *runtime.Scheme) error {
+	if owner.GetNamespace() == resource.GetNamespace() || owner.GetNamespace() == "" {
+		return controllerutil.SetControllerReference(owner, resource, scheme)
+	}
+
+	labels := resource.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[OwnerUIDLabel] = string(owner.GetUID())
+	resource.SetLabels(labels)
+	return nil
+}
+
 func NewUpdater(resource client.Object, owner metav1.Object) Updater {
 	return newUpdater(resource, owner, false)
 }
@@ -80,11 +157,21 @@ type Deleter struct {
 	resource client.Object
 }
 
-func (r Deleter) Reconcile(ctx context.Context, c client.Client, scheme *runtime.Scheme) error {
+func (r Deleter) Reconcile(ctx context.Context, c client.Client, scheme *runtime.Scheme, recorder EventRecorder) error {
+	description := resourceDescription(r.resource)
 	if err := c.Delete(ctx, r.resource); client.IgnoreNotFound(err) != nil {
-		return fmt.Errorf("%s/%s (%s): deleter failed to delete: %w",
+		err = fmt.Errorf("%s/%s (%s): deleter failed to delete: %w",
 			r.resource.GetNamespace(), r.resource.GetName(),
 			r.resource.GetObjectKind().GroupVersionKind().String(), err)
+		recorder.Failed(ctx, r.resource, "Delete", err)
+		return err
+	} else if err == nil {
+		if DiffReportingEnabled(ctx) {
+			log.FromContext(ctx).Info("deleted object",
+				"object", client.ObjectKeyFromObject(r.resource),
+				"gvk", r.resource.GetObjectKind().GroupVersionKind().String())
+		}
+		recorder.Event(ctx, r.resource, corev1.EventTypeNormal, ReasonDeleted, "Delete", description+" deleted")
 	}
 	return nil
 }
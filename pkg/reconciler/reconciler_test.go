@@ -1,13 +1,22 @@
 package reconciler
 
 import (
+	"context"
 	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 )
 
 func TestClientObjectApplyConfig_MarshalJSON(t *testing.T) {
@@ -99,3 +108,164 @@ func TestClientObjectApplyConfig_Getters(t *testing.T) {
 	require.Equal(t, "ConfigMap", *ac.GetKind())
 	require.Equal(t, "v1", *ac.GetAPIVersion())
 }
+
+func TestDiffReportingEnabled(t *testing.T) {
+	require.False(t, DiffReportingEnabled(context.Background()))
+	require.True(t, DiffReportingEnabled(WithDiffReporting(context.Background())))
+}
+
+func TestObjectDiff(t *testing.T) {
+	newConfigMap := func(data map[string]string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-cm",
+				Namespace: "default",
+			},
+			Data: data,
+		}
+	}
+
+	t.Run("server-side fields are ignored", func(t *testing.T) {
+		current := newConfigMap(map[string]string{"key": "value"})
+		current.ResourceVersion = "1"
+		current.UID = "uid"
+		current.ManagedFields = []metav1.ManagedFieldsEntry{{Manager: "observability-operator"}}
+		desired := newConfigMap(map[string]string{"key": "value"})
+		desired.ResourceVersion = "2"
+
+		diff, err := objectDiff(current, desired)
+		require.NoError(t, err)
+		require.Empty(t, diff)
+	})
+
+	t.Run("changed field", func(t *testing.T) {
+		diff, err := objectDiff(newConfigMap(map[string]string{"key": "old"}), newConfigMap(map[string]string{"key": "new"}))
+		require.NoError(t, err)
+		require.Contains(t, diff, `string("old")`)
+		require.Contains(t, diff, `string("new")`)
+	})
+
+	t.Run("created object", func(t *testing.T) {
+		diff, err := objectDiff(nil, newConfigMap(map[string]string{"key": "value"}))
+		require.NoError(t, err)
+		require.Contains(t, diff, "test-cm")
+	})
+}
+
+func TestApplyDiffLiveReader(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	newConfigMap := func() *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cm", Namespace: "default"},
+			Data:       map[string]string{"key": "value"},
+		}
+	}
+	// The object isn't served by the cache of the client.
+	reader := fake.NewClientBuilder().WithScheme(scheme).WithObjects(newConfigMap()).Build()
+
+	diff, err := applyDiff(context.Background(), newFakeApplyClient(t, scheme), scheme, newConfigMap())
+	require.NoError(t, err)
+	require.NotEmpty(t, diff)
+
+	diff, err = applyDiff(WithLiveReader(context.Background(), reader), newFakeApplyClient(t, scheme), scheme, newConfigMap())
+	require.NoError(t, err)
+	require.Empty(t, diff)
+}
+
+func TestReconcilerEvents(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	c := newFakeApplyClient(t, scheme)
+
+	owner := &corev1.ConfigMap{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+		ObjectMeta: metav1.ObjectMeta{Name: "owner", Namespace: "default", UID: "owner-uid"},
+	}
+	newConfigMap := func(value string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cm", Namespace: "default"},
+			Data:       map[string]string{"key": value},
+		}
+	}
+	fakeRecorder := events.NewFakeRecorder(10)
+	recorder := NewEventRecorder(fakeRecorder, owner)
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		name      string
+		reconcile Reconciler
+		wantEvent string
+	}{
+		{name: "create", reconcile: NewUpdater(newConfigMap("a"), owner), wantEvent: "Normal Created ConfigMap default/test-cm created"},
+		{name: "unchanged", reconcile: NewUpdater(newConfigMap("a"), owner)},
+		{name: "update", reconcile: NewUpdater(newConfigMap("b"), owner), wantEvent: "Normal Updated ConfigMap default/test-cm updated"},
+		{name: "delete", reconcile: NewDeleter(newConfigMap("b")), wantEvent: "Normal Deleted ConfigMap default/test-cm deleted"},
+		{name: "already deleted", reconcile: NewDeleter(newConfigMap("b"))},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.NoError(t, tc.reconcile.Reconcile(ctx, c, scheme, recorder))
+			if tc.wantEvent == "" {
+				require.Empty(t, fakeRecorder.Events)
+				return
+			}
+			require.Len(t, fakeRecorder.Events, 1)
+			require.Equal(t, tc.wantEvent, <-fakeRecorder.Events)
+		})
+	}
+
+	t.Run("dry-run", func(t *testing.T) {
+		require.NoError(t, NewUpdater(newConfigMap("c"), owner).Reconcile(WithDryRun(ctx), c, scheme, recorder))
+		require.Empty(t, fakeRecorder.Events)
+	})
+
+	t.Run("zero value", func(t *testing.T) {
+		require.NoError(t, NewUpdater(newConfigMap("d"), owner).Reconcile(ctx, c, scheme, EventRecorder{}))
+	})
+
+	t.Run("object outside the cache", func(t *testing.T) {
+		// The cache of the client doesn't serve the object.
+		cached := interceptor.NewClient(c.(client.WithWatch), interceptor.Funcs{
+			Get: func(_ context.Context, _ client.WithWatch, key client.ObjectKey, obj client.Object, _ ...client.GetOption) error {
+				return apierrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, key.Name)
+			},
+		})
+		require.NoError(t, NewUpdater(newConfigMap("d"), owner).Reconcile(WithLiveReader(ctx, c), cached, scheme, recorder))
+		require.Empty(t, fakeRecorder.Events)
+	})
+}
+
+// newFakeApplyClient returns a fake client which emulates server-side apply
+// with create and update requests since the fake client doesn't support the
+// apply of arbitrary objects.
+func newFakeApplyClient(t *testing.T, scheme *runtime.Scheme, objs ...client.Object) client.Client {
+	t.Helper()
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).WithInterceptorFuncs(interceptor.Funcs{
+		Apply: func(ctx context.Context, c client.WithWatch, obj runtime.ApplyConfiguration, opts ...client.ApplyOption) error {
+			desired := obj.(*clientObjectApplyConfig).obj
+			gvk, err := apiutil.GVKForObject(desired, scheme)
+			if err != nil {
+				return err
+			}
+			current, err := scheme.New(gvk)
+			if err != nil {
+				return err
+			}
+			if err := c.Get(ctx, client.ObjectKeyFromObject(desired), current.(client.Object)); err != nil {
+				if !apierrors.IsNotFound(err) {
+					return err
+				}
+				return c.Create(ctx, desired)
+			}
+			current.GetObjectKind().SetGroupVersionKind(gvk)
+			desired.SetResourceVersion(current.(client.Object).GetResourceVersion())
+			if diff, err := objectDiff(current.(client.Object), desired); err != nil || diff == "" {
+				return err
+			}
+			return c.Update(ctx, desired)
+		},
+	}).Build()
+}